@@ -0,0 +1,102 @@
+package alerts
+
+import (
+	"slices"
+	"strings"
+
+	"schedule-optimizer/internal/search"
+)
+
+// snapshot maps a section key ("term:crn") to what a saved search last saw.
+type snapshot map[string]snapshotSection
+
+type snapshotSection struct {
+	Seats     int    `json:"seats"`
+	CourseKey string `json:"courseKey"`
+	Title     string `json:"title"`
+}
+
+// add records the sections of a search response, limited to term unless it's empty.
+func (snap snapshot) add(resp *search.SearchResponse, term string) {
+	for key, section := range resp.Sections {
+		if term != "" && section.Term != term {
+			continue
+		}
+		snap[key] = snapshotSection{
+			Seats:     section.SeatsAvailable,
+			CourseKey: section.CourseKey,
+			Title:     resp.Courses[section.CourseKey].Title,
+		}
+	}
+}
+
+// merge returns next plus the entries of prev from terms other than term,
+// so rerunning a search for one term keeps what it saw in the others. When
+// next was truncated, prev's entries in term are kept too, since sections
+// past the cut may still match.
+func merge(prev, next snapshot, term string, truncated bool) snapshot {
+	merged := make(snapshot, len(next))
+	for key, section := range prev {
+		if truncated || !strings.HasPrefix(key, term+":") {
+			merged[key] = section
+		}
+	}
+	for key, section := range next {
+		merged[key] = section
+	}
+	return merged
+}
+
+// diff compares a search's sections in term before and after a scrape.
+// When either run was partial, a section seen by only one of them may just
+// be past the other's cut, so only seat changes of sections in both are
+// reported. Alerts are ordered by section key and have no ID or timestamp yet.
+func diff(prev, next snapshot, term string, partial bool) []Alert {
+	keys := make([]string, 0, len(next))
+	for key := range next {
+		keys = append(keys, key)
+	}
+	for key := range prev {
+		if _, ok := next[key]; !ok && strings.HasPrefix(key, term+":") {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	var alerts []Alert
+	for _, key := range keys {
+		before, had := prev[key]
+		after, has := next[key]
+
+		var kind string
+		switch {
+		case partial && (!had || !has):
+		case !had:
+			kind = KindNewSection
+		case !has:
+			// No longer matching only matters if the section was open, e.g. an
+			// openSeats search dropping a section that just filled up
+			if before.Seats > 0 {
+				kind = KindSectionClosed
+			}
+		case before.Seats <= 0 && after.Seats > 0:
+			kind = KindSeatsOpened
+		case before.Seats > 0 && after.Seats <= 0:
+			kind = KindSectionClosed
+		}
+		if kind == "" {
+			continue
+		}
+
+		alert := Alert{Kind: kind, Term: term, CRN: strings.TrimPrefix(key, term+":")}
+		if has {
+			seats := after.Seats
+			alert.SeatsAvailable = &seats
+			alert.CourseKey, alert.Title = after.CourseKey, after.Title
+		} else {
+			alert.CourseKey, alert.Title = before.CourseKey, before.Title
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
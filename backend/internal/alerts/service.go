@@ -0,0 +1,339 @@
+// Package alerts stores named searches per session and reports how their
+// results change after each scrape.
+package alerts
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"schedule-optimizer/internal/search"
+	"schedule-optimizer/internal/store"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+var (
+	ErrInvalidName     = errors.New("name is required and must be at most 100 characters")
+	ErrInvalidQuery    = errors.New("invalid search query")
+	ErrTooManySearches = errors.New("too many saved searches (limit 20 per session)")
+	ErrNotFound        = errors.New("saved search not found")
+)
+
+const (
+	MaxNameLength    = 100
+	MaxSavedSearches = 20  // Per session
+	MaxResultPages   = 5   // Search pages followed per run, MaxCourseResults courses each
+	MaxAlerts        = 100 // Maximum and default alerts per request
+
+	rerunTimeout   = 5 * time.Minute
+	alertRetention = "-30 days" // SQLite datetime modifier
+	subscriberBuf  = 32
+)
+
+// Service stores saved searches and reruns them when jobs scrape a term.
+type Service struct {
+	queries *store.Queries
+	search  *search.Service
+
+	runMu sync.Mutex // Serializes reruns so snapshots aren't updated concurrently
+
+	mu          sync.Mutex
+	subscribers map[string]map[chan Alert]struct{} // By session ID
+	closed      bool
+}
+
+// NewService creates a new alerts service.
+func NewService(queries *store.Queries, searchService *search.Service) *Service {
+	return &Service{
+		queries:     queries,
+		search:      searchService,
+		subscribers: make(map[string]map[chan Alert]struct{}),
+	}
+}
+
+// Create saves a search for a session. query is an /api/search query string;
+// the search runs once to validate it and to record the baseline that later
+// runs are compared against.
+func (s *Service) Create(ctx context.Context, sessionID, name, query string) (*SavedSearch, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > MaxNameLength {
+		return nil, ErrInvalidName
+	}
+	req, params, err := parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.queries.CountSavedSearchesBySession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= MaxSavedSearches {
+		return nil, ErrTooManySearches
+	}
+
+	snap, truncated, err := s.run(ctx, req, "")
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(snap)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.queries.CreateSavedSearch(ctx, store.CreateSavedSearchParams{
+		SessionID: sessionID,
+		Name:      name,
+		Params:    params,
+		Snapshot:  string(encoded),
+		Truncated: boolInt(truncated),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &SavedSearch{
+		ID:        id,
+		Name:      name,
+		Query:     params,
+		Sections:  len(snap),
+		Truncated: truncated,
+		LastRunAt: &now,
+		CreatedAt: now,
+	}, nil
+}
+
+// List returns a session's saved searches, oldest first.
+func (s *Service) List(ctx context.Context, sessionID string) ([]SavedSearch, error) {
+	rows, err := s.queries.GetSavedSearchesBySession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	searches := make([]SavedSearch, len(rows))
+	for i, row := range rows {
+		var snap snapshot
+		if err := json.Unmarshal([]byte(row.Snapshot), &snap); err != nil {
+			return nil, fmt.Errorf("saved search %d snapshot: %w", row.ID, err)
+		}
+		searches[i] = SavedSearch{
+			ID:        row.ID,
+			Name:      row.Name,
+			Query:     row.Params,
+			Sections:  len(snap),
+			Truncated: row.Truncated == 1,
+			CreatedAt: row.CreatedAt.Time,
+		}
+		if row.LastRunAt.Valid {
+			searches[i].LastRunAt = &row.LastRunAt.Time
+		}
+	}
+	return searches, nil
+}
+
+// Delete removes a session's saved search and its alerts.
+func (s *Service) Delete(ctx context.Context, sessionID string, id int64) error {
+	n, err := s.queries.DeleteSavedSearch(ctx, store.DeleteSavedSearchParams{ID: id, SessionID: sessionID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	// Foreign keys cascade this in production; deleting explicitly keeps it
+	// from depending on the connection settings
+	return s.queries.DeleteSavedSearchAlerts(ctx, id)
+}
+
+// Alerts returns a session's alerts with IDs above after, oldest first.
+// limit is clamped to MaxAlerts.
+func (s *Service) Alerts(ctx context.Context, sessionID string, after int64, limit int) ([]Alert, error) {
+	if limit <= 0 || limit > MaxAlerts {
+		limit = MaxAlerts
+	}
+	rows, err := s.queries.GetSavedSearchAlerts(ctx, store.GetSavedSearchAlertsParams{
+		SessionID: sessionID,
+		ID:        after,
+		Limit:     int64(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := make([]Alert, len(rows))
+	for i, row := range rows {
+		alerts[i] = Alert{
+			ID:            row.ID,
+			SavedSearchID: row.SavedSearchID,
+			Kind:          row.Kind,
+			Term:          row.Term,
+			CRN:           row.Crn,
+			CourseKey:     row.CourseKey,
+			Title:         row.Title,
+			CreatedAt:     row.CreatedAt.Time,
+		}
+		if row.SeatsAvailable.Valid {
+			seats := int(row.SeatsAvailable.Int64)
+			alerts[i].SeatsAvailable = &seats
+		}
+	}
+	return alerts, nil
+}
+
+// TermScraped implements jobs.ScrapeListener. Saved searches are rerun in
+// the background.
+func (s *Service) TermScraped(term string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), rerunTimeout)
+		defer cancel()
+		if err := s.rerun(ctx, term); err != nil {
+			slog.Error("Failed to rerun saved searches", "term", term, "error", err)
+		}
+	}()
+}
+
+// rerun runs every saved search that covers term, records an alert for each
+// change to its sections in term, and drops alerts past retention.
+func (s *Service) rerun(ctx context.Context, term string) error {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	rows, err := s.queries.GetSavedSearches(ctx)
+	if err != nil {
+		return err
+	}
+
+	var searched, alerted int
+	for _, row := range rows {
+		req, _, err := parseQuery(row.Params)
+		if err != nil {
+			slog.Warn("Skipping unparseable saved search", "id", row.ID, "error", err)
+			continue
+		}
+		// Year-scoped searches are rerun for every term; ones outside the
+		// year match no sections in term and produce no alerts
+		if req.Term != "" && req.Term != term {
+			continue
+		}
+
+		var prev snapshot
+		if err := json.Unmarshal([]byte(row.Snapshot), &prev); err != nil {
+			slog.Warn("Resetting corrupt saved search snapshot", "id", row.ID, "error", err)
+			prev = snapshot{}
+		}
+		next, truncated, err := s.run(ctx, req, term)
+		if err != nil {
+			slog.Warn("Saved search failed", "id", row.ID, "error", err)
+			continue
+		}
+		searched++
+
+		// A capped run misses the sections past its cut, so only sections
+		// both runs reached are compared
+		partial := truncated || row.Truncated == 1
+		for _, alert := range diff(prev, next, term, partial) {
+			alert.SavedSearchID = row.ID
+			if err := s.record(ctx, row.SessionID, &alert); err != nil {
+				return err
+			}
+			alerted++
+		}
+
+		encoded, err := json.Marshal(merge(prev, next, term, truncated))
+		if err != nil {
+			return err
+		}
+		if err := s.queries.UpdateSavedSearchSnapshot(ctx, store.UpdateSavedSearchSnapshotParams{
+			Snapshot:  string(encoded),
+			Truncated: boolInt(truncated),
+			ID:        row.ID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := s.queries.DeleteSavedSearchAlertsBefore(ctx, alertRetention); err != nil {
+		return err
+	}
+	if searched > 0 {
+		slog.Info("Reran saved searches", "term", term, "searches", searched, "alerts", alerted)
+	}
+	return nil
+}
+
+// run executes a search, following cursors for up to MaxResultPages pages,
+// and snapshots its sections in term, or in every term if term is empty.
+// Reports whether results were left past the last page.
+func (s *Service) run(ctx context.Context, req search.SearchRequest, term string) (snapshot, bool, error) {
+	snap := snapshot{}
+	for range MaxResultPages {
+		resp, err := s.search.SearchUncached(ctx, req)
+		if err != nil {
+			return nil, false, err
+		}
+		snap.add(resp, term)
+		if resp.NextCursor == "" {
+			return snap, false, nil
+		}
+		req.Cursor = resp.NextCursor
+	}
+	return snap, true, nil
+}
+
+func boolInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// record stores an alert, filling in its ID and timestamp, and sends it to
+// the session's subscribers.
+func (s *Service) record(ctx context.Context, sessionID string, alert *Alert) error {
+	params := store.InsertSavedSearchAlertParams{
+		SavedSearchID: alert.SavedSearchID,
+		SessionID:     sessionID,
+		Kind:          alert.Kind,
+		Term:          alert.Term,
+		Crn:           alert.CRN,
+		CourseKey:     alert.CourseKey,
+		Title:         alert.Title,
+	}
+	if alert.SeatsAvailable != nil {
+		params.SeatsAvailable = sql.NullInt64{Int64: int64(*alert.SeatsAvailable), Valid: true}
+	}
+	row, err := s.queries.InsertSavedSearchAlert(ctx, params)
+	if err != nil {
+		return err
+	}
+	alert.ID = row.ID
+	alert.CreatedAt = row.CreatedAt.Time
+
+	s.publish(sessionID, *alert)
+	return nil
+}
+
+// parseQuery binds an /api/search query string to a search request. The
+// returned string is the canonical form to store, without paging parameters.
+func parseQuery(query string) (search.SearchRequest, string, error) {
+	var req search.SearchRequest
+	values, err := url.ParseQuery(strings.TrimPrefix(strings.TrimSpace(query), "?"))
+	if err != nil {
+		return req, "", fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+	}
+	values.Del("cursor")
+	values.Del("pageSize")
+	if err := binding.MapFormWithTag(&req, values, "form"); err != nil {
+		return req, "", fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+	}
+	return req, values.Encode(), nil
+}
@@ -0,0 +1,223 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"schedule-optimizer/internal/search"
+	"schedule-optimizer/internal/testutil"
+)
+
+const (
+	session      = "6f1c2d3e-4b5a-4c7d-8e9f-0a1b2c3d4e5f"
+	otherSession = "0a1b2c3d-4e5f-4a7b-8c9d-0e1f2a3b4c5d"
+)
+
+func TestCreateAndList(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(queries, search.NewService(db, queries, nil, nil))
+	ctx := context.Background()
+
+	saved, err := svc.Create(ctx, session, " CSCI this term ", "?term=202520&subject=CSCI&pageSize=1&cursor=abc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Paging parameters are dropped; following cursors still finds both sections
+	if saved.Name != "CSCI this term" || saved.Query != "subject=CSCI&term=202520" || saved.Sections != 2 {
+		t.Errorf("saved = %+v", saved)
+	}
+
+	list, err := svc.List(ctx, session)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != saved.ID || list[0].Sections != 2 || list[0].LastRunAt == nil {
+		t.Errorf("list = %+v", list)
+	}
+	if list, _ := svc.List(ctx, otherSession); len(list) != 0 {
+		t.Errorf("other session sees %+v", list)
+	}
+
+	if _, err := svc.Create(ctx, session, "  ", "subject=CSCI"); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("expected ErrInvalidName, got %v", err)
+	}
+	if _, err := svc.Create(ctx, session, "bad", "subject=CSCI&minCredits=four"); !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("expected ErrInvalidQuery, got %v", err)
+	}
+	if _, err := svc.Create(ctx, session, "no filters", "term=202520"); !errors.Is(err, search.ErrNoFilters) {
+		t.Errorf("expected ErrNoFilters, got %v", err)
+	}
+
+	for i := 1; i < MaxSavedSearches; i++ {
+		if _, err := svc.Create(ctx, session, fmt.Sprintf("search %d", i), "subject=MATH"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := svc.Create(ctx, session, "one too many", "subject=MATH"); !errors.Is(err, ErrTooManySearches) {
+		t.Errorf("expected ErrTooManySearches, got %v", err)
+	}
+
+	if err := svc.Delete(ctx, otherSession, saved.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound deleting another session's search, got %v", err)
+	}
+	if err := svc.Delete(ctx, session, saved.ID); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRerun(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(queries, search.NewService(db, queries, nil, nil))
+	ctx := context.Background()
+
+	termSearch, err := svc.Create(ctx, session, "CSCI spring", "term=202520&subject=CSCI")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	openSearch, err := svc.Create(ctx, otherSession, "Open CSCI", "subject=CSCI&openSeats=true")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	live, unsubscribe := svc.Subscribe(session)
+	defer unsubscribe()
+
+	// 20001 fills up, 20002 opens, and a new section appears
+	_, err = db.Exec(`
+		UPDATE sections SET enrollment = 30, seats_available = 0, is_open = 0 WHERE crn = '20001';
+		UPDATE sections SET enrollment = 27, seats_available = 3, is_open = 1 WHERE crn = '20002';
+		INSERT INTO sections (term, crn, subject, course_number, title, credit_hours_low, enrollment, max_enrollment, seats_available, is_open)
+		VALUES ('202520', '20005', 'CSCI', '247', 'Data Structures', 4, 0, 30, 30, 1);
+	`)
+	if err != nil {
+		t.Fatalf("failed to update sections: %v", err)
+	}
+
+	// Neither search sees changes in a term without any
+	if err := svc.rerun(ctx, "202510"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := svc.Alerts(ctx, session, 0, 0); len(got) != 0 {
+		t.Fatalf("expected no alerts, got %+v", got)
+	}
+
+	if err := svc.rerun(ctx, "202520"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := svc.Alerts(ctx, session, 0, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []struct {
+		kind, crn string
+		seats     int
+	}{
+		{KindSectionClosed, "20001", 0},
+		{KindSeatsOpened, "20002", 3},
+		{KindNewSection, "20005", 30},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("alerts = %+v", got)
+	}
+	for i, e := range expected {
+		a := got[i]
+		if a.SavedSearchID != termSearch.ID || a.Kind != e.kind || a.CRN != e.crn || a.Term != "202520" ||
+			a.SeatsAvailable == nil || *a.SeatsAvailable != e.seats {
+			t.Errorf("alert %d = %+v, expected %+v", i, a, e)
+		}
+		select {
+		case published := <-live:
+			if published.ID != a.ID {
+				t.Errorf("published alert %d, expected %d", published.ID, a.ID)
+			}
+		default:
+			t.Errorf("alert %d was not published", a.ID)
+		}
+	}
+	if got[0].CourseKey != "CSCI:247" || got[0].Title != "Data Structures" {
+		t.Errorf("alert course = %q %q", got[0].CourseKey, got[0].Title)
+	}
+
+	if later, _ := svc.Alerts(ctx, session, got[0].ID, 0); len(later) != 2 {
+		t.Errorf("expected 2 alerts after %d, got %+v", got[0].ID, later)
+	}
+
+	// The open-seats search loses 20001 without seeing its seat count, and
+	// keeps what it saw in 202510
+	got, err = svc.Alerts(ctx, otherSession, 0, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 3 || got[0].SavedSearchID != openSearch.ID || got[0].Kind != KindSectionClosed ||
+		got[0].CRN != "20001" || got[0].SeatsAvailable != nil {
+		t.Errorf("open search alerts = %+v", got)
+	}
+	list, _ := svc.List(ctx, otherSession)
+	if len(list) != 1 || list[0].Sections != 3 {
+		t.Errorf("open search = %+v, expected 3 sections (10001, 20002, 20005)", list)
+	}
+
+	// Unchanged results produce no new alerts
+	if err := svc.rerun(ctx, "202520"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := svc.Alerts(ctx, session, 0, 0); len(got) != 3 {
+		t.Errorf("expected 3 alerts after an unchanged rerun, got %d", len(got))
+	}
+}
+
+func TestDiff_Partial(t *testing.T) {
+	prev := snapshot{
+		"202520:20001": {Seats: 5},
+		"202520:20002": {Seats: 0},
+		"202520:20003": {Seats: 8},
+		"202510:10001": {Seats: 2},
+	}
+	// 20003 fell past the cut and 20004 came into it; 20002 opened
+	next := snapshot{
+		"202520:20001": {Seats: 5},
+		"202520:20002": {Seats: 3},
+		"202520:20004": {Seats: 9},
+	}
+
+	if got := diff(prev, next, "202520", false); len(got) != 3 {
+		t.Errorf("complete diff = %+v, expected 3 alerts", got)
+	}
+	got := diff(prev, next, "202520", true)
+	if len(got) != 1 || got[0].Kind != KindSeatsOpened || got[0].CRN != "20002" {
+		t.Errorf("partial diff = %+v, expected only 20002 opening", got)
+	}
+
+	// A truncated run keeps what the previous run saw past its cut
+	if merged := merge(prev, next, "202520", true); len(merged) != 5 {
+		t.Errorf("truncated merge = %v, expected 5 sections", merged)
+	}
+	if merged := merge(prev, next, "202520", false); len(merged) != 4 {
+		t.Errorf("complete merge = %v, expected 4 sections", merged)
+	}
+}
+
+func TestSubscribe_Close(t *testing.T) {
+	svc := NewService(nil, nil)
+	live, unsubscribe := svc.Subscribe(session)
+
+	svc.Close()
+	if _, ok := <-live; ok {
+		t.Error("expected Close to close subscriptions")
+	}
+	unsubscribe() // Must not close the channel twice
+
+	late, _ := svc.Subscribe(session)
+	if _, ok := <-late; ok {
+		t.Error("expected subscriptions after Close to be closed")
+	}
+}
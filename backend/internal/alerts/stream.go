@@ -0,0 +1,57 @@
+package alerts
+
+// Subscribe returns a channel that receives a session's alerts as they are
+// recorded, and a function that unsubscribes and closes it. Alerts are
+// dropped for subscribers that fall behind; they can catch up with Alerts.
+// The channel is also closed by Close.
+func (s *Service) Subscribe(sessionID string) (<-chan Alert, func()) {
+	ch := make(chan Alert, subscriberBuf)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	if s.subscribers[sessionID] == nil {
+		s.subscribers[sessionID] = make(map[chan Alert]struct{})
+	}
+	s.subscribers[sessionID][ch] = struct{}{}
+
+	return ch, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.subscribers[sessionID][ch]; !ok {
+			return // Already closed by Close
+		}
+		delete(s.subscribers[sessionID], ch)
+		if len(s.subscribers[sessionID]) == 0 {
+			delete(s.subscribers, sessionID)
+		}
+		close(ch)
+	}
+}
+
+// Close ends all subscriptions so open streams finish. Call on shutdown.
+func (s *Service) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for _, subs := range s.subscribers {
+		for ch := range subs {
+			close(ch)
+		}
+	}
+	clear(s.subscribers)
+}
+
+func (s *Service) publish(sessionID string, alert Alert) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subscribers[sessionID] {
+		select {
+		case ch <- alert:
+		default:
+		}
+	}
+}
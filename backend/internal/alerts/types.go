@@ -0,0 +1,34 @@
+package alerts
+
+import "time"
+
+// Alert kinds
+const (
+	KindNewSection    = "new_section"    // Section matches the search for the first time
+	KindSeatsOpened   = "seats_opened"   // Section went from no seats to some
+	KindSectionClosed = "section_closed" // Section filled up, or an open section stopped matching
+)
+
+// SavedSearch is a named search stored for a session.
+type SavedSearch struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Query     string     `json:"query"`               // /api/search query string, without cursor and pageSize
+	Sections  int        `json:"sections"`            // Matching sections as of the last run
+	Truncated bool       `json:"truncated,omitempty"` // The last run stopped at MaxResultPages
+	LastRunAt *time.Time `json:"lastRunAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// Alert is one change to a saved search's results found after a scrape.
+type Alert struct {
+	ID             int64     `json:"id"`
+	SavedSearchID  int64     `json:"savedSearchId"`
+	Kind           string    `json:"kind"`
+	Term           string    `json:"term"`
+	CRN            string    `json:"crn"`
+	CourseKey      string    `json:"courseKey"`
+	Title          string    `json:"title"`
+	SeatsAvailable *int      `json:"seatsAvailable,omitempty"` // Omitted when the section no longer matches
+	CreatedAt      time.Time `json:"createdAt"`
+}
@@ -3,17 +3,25 @@ package api
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"schedule-optimizer/internal/alerts"
 	"schedule-optimizer/internal/cache"
 	"schedule-optimizer/internal/generator"
 	"schedule-optimizer/internal/jobs"
+	"schedule-optimizer/internal/prereq"
 	"schedule-optimizer/internal/search"
+	"schedule-optimizer/internal/stats"
+	"schedule-optimizer/internal/stats/catalog"
 	"schedule-optimizer/internal/stats/grades"
 	"schedule-optimizer/internal/store"
 
@@ -30,6 +38,10 @@ type Handlers struct {
 	queries   *store.Queries
 	search    *search.Service
 	grades    *grades.Service
+	catalog   *catalog.Service
+	alerts    *alerts.Service
+	prereqs   *prereq.Service
+	jobs      *jobs.Service
 }
 
 // Response types for type-safe JSON serialization
@@ -59,6 +71,19 @@ type SectionResponse struct {
 	GPA            float64           `json:"gpa,omitempty"`
 	GPASource      string            `json:"gpaSource,omitempty"`
 	PassRate       *float64          `json:"passRate,omitempty"`
+	RemovedAt      *time.Time        `json:"removedAt,omitempty"` // Set once Banner stops listing the section
+	Details        *CourseDetails    `json:"details,omitempty"`
+	LinkedSections [][]string        `json:"linkedSections,omitempty"` // Alternative sets of CRNs to register with this one
+}
+
+// CourseDetails is catalog text for a course, fetched by the detail-scrape
+// job (SCRAPER_DETAILS). Empty fields mean Banner shows nothing.
+type CourseDetails struct {
+	Description   string    `json:"description,omitempty"`
+	Prerequisites string    `json:"prerequisites,omitempty"`
+	Restrictions  string    `json:"restrictions,omitempty"`
+	Fees          string    `json:"fees,omitempty"`
+	FetchedAt     time.Time `json:"fetchedAt"`
 }
 
 type CRNResponse struct {
@@ -72,6 +97,7 @@ type CourseInfo struct {
 	Credits      int      `json:"credits"`
 	GPA          float64  `json:"gpa,omitempty"`
 	PassRate     *float64 `json:"passRate,omitempty"`
+	Details      *CourseDetails `json:"details,omitempty"`
 }
 
 type CourseSectionInfo struct {
@@ -85,6 +111,7 @@ type CourseSectionInfo struct {
 	GPA            float64  `json:"gpa,omitempty"`
 	GPASource      string   `json:"gpaSource,omitempty"`
 	PassRate       *float64 `json:"passRate,omitempty"`
+	LinkedSections [][]string `json:"linkedSections,omitempty"`
 }
 
 type CourseResponse struct {
@@ -160,15 +187,36 @@ func boolToInt64(b bool) int64 {
 }
 
 // NewHandlers creates a new Handlers instance with all dependencies.
-func NewHandlers(db *sql.DB, cache *cache.ScheduleCache, generator *generator.Service, queries *store.Queries, searchSvc *search.Service, gradesSvc *grades.Service) *Handlers {
-	return &Handlers{
+// statsSvc may be nil, which disables grade data and the catalog endpoints.
+func NewHandlers(db *sql.DB, cache *cache.ScheduleCache, generator *generator.Service, queries *store.Queries, searchSvc *search.Service, statsSvc *stats.Service) *Handlers {
+	h := &Handlers{
 		db:        db,
 		cache:     cache,
 		generator: generator,
 		queries:   queries,
 		search:    searchSvc,
-		grades:    gradesSvc,
 	}
+	if statsSvc != nil {
+		h.grades = statsSvc.Grades
+		h.catalog = statsSvc.Catalog
+	}
+	return h
+}
+
+// SetAlerts enables the saved search endpoints.
+func (h *Handlers) SetAlerts(alertsSvc *alerts.Service) {
+	h.alerts = alertsSvc
+}
+
+// SetPrerequisites enables the prerequisite endpoints.
+func (h *Handlers) SetPrerequisites(prereqSvc *prereq.Service) {
+	h.prereqs = prereqSvc
+}
+
+// SetJobs enables the job control endpoints. jobsSvc is nil when jobs are
+// disabled, and the endpoints return 503.
+func (h *Handlers) SetJobs(jobsSvc *jobs.Service) {
+	h.jobs = jobsSvc
 }
 
 // validateTerm checks if a term exists and sends an error response if not.
@@ -187,9 +235,158 @@ func (h *Handlers) validateTerm(c *gin.Context, term string) bool {
 	return true
 }
 
-// Health returns server health status.
+// Health returns server health status along with the freshness of cached terms.
 func (h *Handlers) Health(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"status": "healthy"})
+	c.JSON(http.StatusOK, gin.H{
+		"status": "healthy",
+		"terms":  h.cache.Status(),
+	})
+}
+
+// GetCacheMetrics returns schedule cache counters and per-term residency.
+func (h *Handlers) GetCacheMetrics(c *gin.Context) {
+	c.JSON(http.StatusOK, h.cache.Metrics())
+}
+
+// maxScrapeRuns caps runs returned by GetScrapeRuns.
+const maxScrapeRuns = 200
+
+// ScrapeRunResponse is a scrape run in the admin API.
+type ScrapeRunResponse struct {
+	ID         int64      `json:"id"`
+	Term       string     `json:"term"`
+	Job        string     `json:"job"`
+	Status     string     `json:"status"`
+	Stored     int64      `json:"stored"`
+	Expected   int64      `json:"expected"`
+	PageErrors int64      `json:"pageErrors"`
+	SaveErrors int64      `json:"saveErrors"`
+	Removed    int64      `json:"removed"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt"` // Null while running
+}
+
+// GetScrapeRuns returns recent scrape runs, newest first, optionally for one
+// ?term=, up to ?limit= (default 50, max 200).
+func (h *Handlers) GetScrapeRuns(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+		return
+	}
+	limit = min(limit, maxScrapeRuns)
+
+	runs, err := h.queries.GetScrapeRuns(c.Request.Context(), store.GetScrapeRunsParams{
+		Term:  c.Query("term"),
+		Limit: int64(limit),
+	})
+	if err != nil {
+		slog.Error("Failed to fetch scrape runs", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scrape runs"})
+		return
+	}
+
+	result := make([]ScrapeRunResponse, len(runs))
+	for i, run := range runs {
+		result[i] = ScrapeRunResponse{
+			ID:         run.ID,
+			Term:       run.Term,
+			Job:        run.Job,
+			Status:     run.Status,
+			Stored:     run.Stored,
+			Expected:   run.Expected,
+			PageErrors: run.PageErrors,
+			SaveErrors: run.SaveErrors,
+			Removed:    run.Removed,
+			Error:      run.Error.String,
+			StartedAt:  run.StartedAt.Time,
+		}
+		if run.FinishedAt.Valid {
+			result[i].FinishedAt = &run.FinishedAt.Time
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"runs": result})
+}
+
+// requireJobs sends a 503 and returns false if jobs are disabled.
+func (h *Handlers) requireJobs(c *gin.Context) bool {
+	if h.jobs == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Jobs are disabled"})
+		return false
+	}
+	return true
+}
+
+// writeJobError maps a jobs control error to a response.
+func writeJobError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, jobs.ErrUnknownJob):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, jobs.ErrPaused):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// GetJobs returns whether jobs are paused and each job's last run, last
+// error, and next eligible time.
+func (h *Handlers) GetJobs(c *gin.Context) {
+	if !h.requireJobs(c) {
+		return
+	}
+	c.JSON(http.StatusOK, h.jobs.Status())
+}
+
+// TriggerJob queues a job by name to run on the next check, whether or not
+// it's due.
+func (h *Handlers) TriggerJob(c *gin.Context) {
+	if !h.requireJobs(c) {
+		return
+	}
+	if err := h.jobs.Trigger(c.Param("name")); err != nil {
+		writeJobError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, h.jobs.Status())
+}
+
+// TriggerTermScrape queues a scrape of one term, whether or not it's due.
+// The term needn't be stored yet, so only its code's format is checked.
+func (h *Handlers) TriggerTermScrape(c *gin.Context) {
+	if !h.requireJobs(c) {
+		return
+	}
+	term := c.Param("term")
+	if _, _, err := jobs.ParseTermCode(term); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid term code: " + term})
+		return
+	}
+	if err := h.jobs.TriggerScrape(term); err != nil {
+		writeJobError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, h.jobs.Status())
+}
+
+// PauseJobs stops jobs from running, cancelling the running job.
+func (h *Handlers) PauseJobs(c *gin.Context) {
+	if !h.requireJobs(c) {
+		return
+	}
+	h.jobs.Pause()
+	c.JSON(http.StatusOK, h.jobs.Status())
+}
+
+// ResumeJobs lets jobs run again.
+func (h *Handlers) ResumeJobs(c *gin.Context) {
+	if !h.requireJobs(c) {
+		return
+	}
+	h.jobs.Resume()
+	c.JSON(http.StatusOK, h.jobs.Status())
 }
 
 // Generate creates schedule combinations for requested courses.
@@ -212,6 +409,11 @@ func (h *Handlers) Generate(c *gin.Context) {
 
 	resp, err := h.generator.Generate(c.Request.Context(), req)
 	if err != nil {
+		if errors.Is(err, generator.ErrPinnedNotFound) || errors.Is(err, generator.ErrPinnedConflict) ||
+			errors.Is(err, prereq.ErrInvalidCourse) || errors.Is(err, prereq.ErrTooManyCourses) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -375,23 +577,7 @@ func (h *Handlers) Search(c *gin.Context) {
 
 	result, err := h.search.Search(c.Request.Context(), req)
 	if err != nil {
-		switch {
-		case errors.Is(err, search.ErrNoFilters):
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		case errors.Is(err, search.ErrTooManyTokens):
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		case errors.Is(err, search.ErrInvalidTerm):
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		case errors.Is(err, search.ErrInvalidYear):
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		case errors.Is(err, search.ErrWildcardOnly):
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		case errors.Is(err, search.ErrFilterTooShort):
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		default:
-			slog.Error("Search failed", "error", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
-		}
+		writeSearchError(c, err)
 		return
 	}
 
@@ -424,6 +610,38 @@ func (h *Handlers) Search(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// writeSearchError maps a search.Service error to a response.
+func writeSearchError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, search.ErrNoFilters):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, search.ErrInvalidTerm):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, search.ErrInvalidYear):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, search.ErrWildcardOnly):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, search.ErrFilterTooShort):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, search.ErrInvalidDays),
+		errors.Is(err, search.ErrInvalidTime),
+		errors.Is(err, search.ErrInvalidBlockedTime),
+		errors.Is(err, search.ErrCRNsRequireTerm),
+		errors.Is(err, search.ErrCRNNotFound),
+		errors.Is(err, search.ErrInvalidLevel),
+		errors.Is(err, search.ErrInvalidAvailability),
+		errors.Is(err, search.ErrInvalidGPABand),
+		errors.Is(err, search.ErrInvalidSort),
+		errors.Is(err, search.ErrInvalidWeight),
+		errors.Is(err, search.ErrInvalidCursor),
+		errors.Is(err, search.ErrInvalidPageSize):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		slog.Error("Search failed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
+	}
+}
+
 // GetCRN looks up a specific CRN.
 // Accepts optional ?term= query param. If not provided, searches the most recent term first.
 func (h *Handlers) GetCRN(c *gin.Context) {
@@ -465,6 +683,11 @@ func (h *Handlers) GetCRN(c *gin.Context) {
 		if h.grades != nil && h.grades.IsLoaded() {
 			resp.GPA, resp.PassRate, resp.GPASource = h.grades.LookupSectionGPA(resp.Subject, resp.CourseNumber, resp.Instructor)
 		}
+		if err := h.addSectionDetails(c.Request.Context(), resp); err != nil {
+			slog.Error("Failed to fetch section details", "crn", crn, "term", term, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch section details"})
+			return
+		}
 		c.JSON(http.StatusOK, CRNResponse{Section: resp})
 		return
 	}
@@ -493,6 +716,11 @@ func (h *Handlers) GetCRN(c *gin.Context) {
 			if h.grades != nil && h.grades.IsLoaded() {
 				resp.GPA, resp.PassRate, resp.GPASource = h.grades.LookupSectionGPA(resp.Subject, resp.CourseNumber, resp.Instructor)
 			}
+			if err := h.addSectionDetails(c.Request.Context(), resp); err != nil {
+				slog.Error("Failed to fetch section details", "crn", crn, "term", t.Code, "error", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch section details"})
+				return
+			}
 			c.JSON(http.StatusOK, CRNResponse{Section: resp})
 			return
 		}
@@ -506,6 +734,56 @@ func (h *Handlers) GetCRN(c *gin.Context) {
 	c.JSON(http.StatusOK, CRNResponse{Section: nil})
 }
 
+// addSectionDetails adds the section's course details and linked sections,
+// where the scraper has fetched them.
+func (h *Handlers) addSectionDetails(ctx context.Context, resp *SectionResponse) error {
+	details, err := h.courseDetails(ctx, resp.Term, resp.Subject, resp.CourseNumber)
+	if err != nil {
+		return err
+	}
+	resp.Details = details
+
+	links, err := h.queries.GetSectionLinks(ctx, store.GetSectionLinksParams{Term: resp.Term, Crn: resp.CRN})
+	if err != nil {
+		return err
+	}
+	for _, link := range links {
+		resp.LinkedSections = appendLink(resp.LinkedSections, link.LinkGroup, link.LinkedCrn)
+	}
+	return nil
+}
+
+// courseDetails returns a course's fetched details, or nil if it has none.
+func (h *Handlers) courseDetails(ctx context.Context, term, subject, courseNumber string) (*CourseDetails, error) {
+	row, err := h.queries.GetCourseDetails(ctx, store.GetCourseDetailsParams{
+		Term:         term,
+		Subject:      subject,
+		CourseNumber: courseNumber,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &CourseDetails{
+		Description:   fromNullString(row.Description),
+		Prerequisites: fromNullString(row.Prerequisites),
+		Restrictions:  fromNullString(row.Restrictions),
+		Fees:          fromNullString(row.Fees),
+		FetchedAt:     row.FetchedAt.Time,
+	}, nil
+}
+
+// appendLink adds crn to link group n of groups, given links ordered by group.
+func appendLink(groups [][]string, n int64, crn string) [][]string {
+	if int64(len(groups)) <= n {
+		groups = append(groups, nil)
+	}
+	groups[len(groups)-1] = append(groups[len(groups)-1], crn)
+	return groups
+}
+
 func formatSectionResponse(s *store.GetSectionWithInstructorByTermAndCRNRow, meetings []*store.MeetingTime) *SectionResponse {
 	meetingTimes := make([]MeetingTimeInfo, 0, len(meetings))
 	for _, m := range meetings {
@@ -526,7 +804,7 @@ func formatSectionResponse(s *store.GetSectionWithInstructorByTermAndCRNRow, mee
 		})
 	}
 
-	return &SectionResponse{
+	resp := &SectionResponse{
 		CRN:            s.Crn,
 		Term:           s.Term,
 		Subject:        s.Subject,
@@ -541,6 +819,10 @@ func formatSectionResponse(s *store.GetSectionWithInstructorByTermAndCRNRow, mee
 		IsOpen:         fromNullInt64ToBool(s.IsOpen),
 		MeetingTimes:   meetingTimes,
 	}
+	if s.RemovedAt.Valid {
+		resp.RemovedAt = &s.RemovedAt.Time
+	}
+	return resp
 }
 
 // GetCourse returns course info and all sections for a course.
@@ -577,6 +859,21 @@ func (h *Handlers) GetCourse(c *gin.Context) {
 		return
 	}
 
+	links, err := h.queries.GetSectionLinksByCourse(c.Request.Context(), store.GetSectionLinksByCourseParams{
+		Term:         term,
+		Subject:      subject,
+		CourseNumber: courseNumber,
+	})
+	if err != nil {
+		slog.Error("Failed to get linked sections", "term", term, "subject", subject, "courseNumber", courseNumber, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get course"})
+		return
+	}
+	linked := make(map[string][][]string)
+	for _, link := range links {
+		linked[link.Crn] = appendLink(linked[link.Crn], link.LinkGroup, link.LinkedCrn)
+	}
+
 	// Build sections response
 	sectionList := make([]CourseSectionInfo, 0, len(sections))
 	for _, s := range sections {
@@ -588,6 +885,7 @@ func (h *Handlers) GetCourse(c *gin.Context) {
 			SeatsAvailable: fromNullInt64Raw(s.SeatsAvailable),
 			WaitCount:      fromNullInt64Raw(s.WaitCount),
 			IsOpen:         fromNullInt64ToBool(s.IsOpen),
+			LinkedSections: linked[s.Crn],
 		}
 		if h.grades != nil && h.grades.IsLoaded() {
 			si.GPA, si.PassRate, si.GPASource = h.grades.LookupSectionGPA(subject, courseNumber, fromNullString(s.InstructorName))
@@ -605,6 +903,11 @@ func (h *Handlers) GetCourse(c *gin.Context) {
 	if h.grades != nil && h.grades.IsLoaded() {
 		courseInfo.GPA, courseInfo.PassRate, _ = h.grades.LookupCourseGPA(subject, courseNumber)
 	}
+	if courseInfo.Details, err = h.courseDetails(c.Request.Context(), term, subject, courseNumber); err != nil {
+		slog.Error("Failed to get course details", "term", term, "subject", subject, "courseNumber", courseNumber, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get course"})
+		return
+	}
 	c.JSON(http.StatusOK, CourseResponse{
 		Course:       courseInfo,
 		Sections:     sectionList,
@@ -612,6 +915,365 @@ func (h *Handlers) GetCourse(c *gin.Context) {
 	})
 }
 
+// requireCatalog sends a 503 and returns false if the catalog endpoints are
+// disabled.
+func (h *Handlers) requireCatalog(c *gin.Context) bool {
+	if h.catalog == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Catalog data is unavailable"})
+		return false
+	}
+	return true
+}
+
+// GetCourseHistory returns a course's offerings across all scraped terms,
+// with enrollment trends, meeting patterns, and grade aggregates.
+func (h *Handlers) GetCourseHistory(c *gin.Context) {
+	if !h.requireCatalog(c) {
+		return
+	}
+	subject := strings.ToUpper(strings.TrimSpace(c.Param("subject")))
+	courseNumber := strings.ToUpper(strings.TrimSpace(c.Param("courseNumber")))
+
+	if subject == "" || courseNumber == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "subject and courseNumber are required"})
+		return
+	}
+
+	history, err := h.catalog.CourseHistory(c.Request.Context(), subject, courseNumber)
+	if errors.Is(err, catalog.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to get course history", "subject", subject, "courseNumber", courseNumber, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get course history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// GetCRNHistory returns the changes recorded for a section across scrapes.
+// Requires ?term=, since CRNs are reused between terms.
+func (h *Handlers) GetCRNHistory(c *gin.Context) {
+	if !h.requireCatalog(c) {
+		return
+	}
+	crn := c.Param("crn")
+	term := c.Query("term")
+	if term == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "term is required"})
+		return
+	}
+
+	timeline, err := h.catalog.SectionTimeline(c.Request.Context(), term, crn)
+	if errors.Is(err, catalog.ErrSectionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to get section history", "term", term, "crn", crn, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get section history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, timeline)
+}
+
+// GetTermChanges returns a term's section changes after ?after= (a change ID,
+// default 0), oldest first, optionally filtered by ?kind=, up to ?limit=
+// (default and max 500). Poll with the last ID seen to follow new scrapes.
+func (h *Handlers) GetTermChanges(c *gin.Context) {
+	if !h.requireCatalog(c) {
+		return
+	}
+	term := c.Param("term")
+	if !h.validateTerm(c, term) {
+		return
+	}
+	after, err := strconv.ParseInt(c.DefaultQuery("after", "0"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "after must be a change id"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a number"})
+		return
+	}
+
+	changes, err := h.catalog.TermChanges(c.Request.Context(), term, after, c.Query("kind"), limit)
+	if err != nil {
+		slog.Error("Failed to get term changes", "term", term, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get term changes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"term": term, "changes": changes})
+}
+
+// SearchInstructors finds instructors by name substring (?q=).
+func (h *Handlers) SearchInstructors(c *gin.Context) {
+	if !h.requireCatalog(c) {
+		return
+	}
+	matches, err := h.catalog.SearchInstructors(c.Request.Context(), c.Query("q"))
+	if errors.Is(err, catalog.ErrQueryTooShort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to search instructors", "query", c.Query("q"), "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search instructors"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"instructors": matches})
+}
+
+// GetInstructor returns an instructor's teaching history and grade aggregates.
+func (h *Handlers) GetInstructor(c *gin.Context) {
+	if !h.requireCatalog(c) {
+		return
+	}
+	name := strings.TrimSpace(c.Param("name"))
+
+	profile, err := h.catalog.InstructorProfile(c.Request.Context(), name)
+	if errors.Is(err, catalog.ErrInstructorNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to get instructor profile", "name", name, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get instructor"})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// SavedSearchRequest is the request body for saving a search.
+type SavedSearchRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Query string `json:"query" binding:"required"` // Query string as sent to /api/search
+}
+
+// requireSessionID returns the request's X-Session-ID, or sends an error
+// response and returns false if it has none.
+func requireSessionID(c *gin.Context) (string, bool) {
+	sessionID := getSessionID(c)
+	if !sessionID.Valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "X-Session-ID header is required"})
+		return "", false
+	}
+	return sessionID.String, true
+}
+
+// requireAlerts sends a 503 and returns false if saved searches are disabled.
+func (h *Handlers) requireAlerts(c *gin.Context) bool {
+	if h.alerts == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Saved searches are disabled"})
+		return false
+	}
+	return true
+}
+
+// CreateSavedSearch saves a search for the session. It is rerun after each
+// scrape of the terms it covers, and changes to its results become alerts.
+func (h *Handlers) CreateSavedSearch(c *gin.Context) {
+	if !h.requireAlerts(c) {
+		return
+	}
+	sessionID, ok := requireSessionID(c)
+	if !ok {
+		return
+	}
+	var req SavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name and query are required"})
+		return
+	}
+
+	saved, err := h.alerts.Create(c.Request.Context(), sessionID, req.Name, req.Query)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusCreated, saved)
+	case errors.Is(err, alerts.ErrInvalidName), errors.Is(err, alerts.ErrInvalidQuery):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, alerts.ErrTooManySearches):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		writeSearchError(c, err)
+	}
+}
+
+// ListSavedSearches returns the session's saved searches.
+func (h *Handlers) ListSavedSearches(c *gin.Context) {
+	if !h.requireAlerts(c) {
+		return
+	}
+	sessionID, ok := requireSessionID(c)
+	if !ok {
+		return
+	}
+	searches, err := h.alerts.List(c.Request.Context(), sessionID)
+	if err != nil {
+		slog.Error("Failed to list saved searches", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list saved searches"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"savedSearches": searches})
+}
+
+// DeleteSavedSearch removes one of the session's saved searches.
+func (h *Handlers) DeleteSavedSearch(c *gin.Context) {
+	if !h.requireAlerts(c) {
+		return
+	}
+	sessionID, ok := requireSessionID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid saved search id"})
+		return
+	}
+
+	err = h.alerts.Delete(c.Request.Context(), sessionID, id)
+	if errors.Is(err, alerts.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to delete saved search", "id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete saved search"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetSavedSearchAlerts returns the session's alerts after ?after= (an alert
+// ID, default 0), oldest first, up to ?limit= (default and max 100).
+func (h *Handlers) GetSavedSearchAlerts(c *gin.Context) {
+	if !h.requireAlerts(c) {
+		return
+	}
+	sessionID, ok := requireSessionID(c)
+	if !ok {
+		return
+	}
+	after, err := strconv.ParseInt(c.DefaultQuery("after", "0"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "after must be an alert id"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a number"})
+		return
+	}
+
+	list, err := h.alerts.Alerts(c.Request.Context(), sessionID, after, limit)
+	if err != nil {
+		slog.Error("Failed to get saved search alerts", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get alerts"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"alerts": list})
+}
+
+const alertKeepalive = 30 * time.Second
+
+// StreamSavedSearchAlerts sends the session's alerts as server-sent events,
+// starting with any after Last-Event-ID (or ?after=). EventSource can't set
+// headers, so the session ID may also be passed as ?sessionId=.
+func (h *Handlers) StreamSavedSearchAlerts(c *gin.Context) {
+	if !h.requireAlerts(c) {
+		return
+	}
+	sessionID := getSessionID(c)
+	if id := c.Query("sessionId"); !sessionID.Valid && uuidPattern.MatchString(strings.ToLower(id)) {
+		sessionID = sql.NullString{String: id, Valid: true}
+	}
+	if !sessionID.Valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "X-Session-ID header or sessionId parameter is required"})
+		return
+	}
+	lastID := c.GetHeader("Last-Event-ID")
+	if lastID == "" {
+		lastID = c.DefaultQuery("after", "0")
+	}
+	after, err := strconv.ParseInt(lastID, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "after must be an alert id"})
+		return
+	}
+
+	// Subscribe before reading the backlog so nothing recorded in between is missed
+	ctx := c.Request.Context()
+	live, unsubscribe := h.alerts.Subscribe(sessionID.String)
+	defer unsubscribe()
+
+	var backlog []alerts.Alert
+	for {
+		page, err := h.alerts.Alerts(ctx, sessionID.String, after, alerts.MaxAlerts)
+		if err != nil {
+			slog.Error("Failed to get saved search alerts", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get alerts"})
+			return
+		}
+		backlog = append(backlog, page...)
+		if len(page) < alerts.MaxAlerts {
+			break
+		}
+		after = page[len(page)-1].ID
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	for _, alert := range backlog {
+		if err := writeAlertEvent(c.Writer, alert); err != nil {
+			return
+		}
+		after = alert.ID
+	}
+	c.Writer.Flush()
+
+	keepalive := time.NewTicker(alertKeepalive)
+	defer keepalive.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case alert, ok := <-live:
+			if !ok {
+				return false
+			}
+			if alert.ID <= after {
+				return true // Already sent with the backlog
+			}
+			after = alert.ID
+			return writeAlertEvent(w, alert) == nil
+		case <-keepalive.C:
+			_, err := io.WriteString(w, ": keepalive\n\n")
+			return err == nil
+		case <-ctx.Done():
+			return false
+		}
+	})
+}
+
+func writeAlertEvent(w io.Writer, alert alerts.Alert) error {
+	data, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: alert\ndata: %s\n\n", alert.ID, data)
+	return err
+}
+
 const maxValidateCourses = 20
 
 // ValidateCoursesRequest is the request body for batch course validation.
@@ -739,6 +1401,78 @@ func (h *Handlers) ValidateCourses(c *gin.Context) {
 	c.JSON(http.StatusOK, ValidateCoursesResponse{Results: results})
 }
 
+// requirePrereqs sends a 503 and returns false if prerequisites are disabled.
+func (h *Handlers) requirePrereqs(c *gin.Context) bool {
+	if h.prereqs == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Prerequisites are disabled"})
+		return false
+	}
+	return true
+}
+
+// EligibleRequest is the request body for checking which courses a student
+// can take.
+type EligibleRequest struct {
+	Term      string   `json:"term" binding:"required"`
+	Completed []string `json:"completed"` // Course codes, such as "CSCI 145"
+}
+
+// GetEligibleCourses sorts the term's courses by whether the completed
+// courses meet their prerequisites.
+func (h *Handlers) GetEligibleCourses(c *gin.Context) {
+	if !h.requirePrereqs(c) {
+		return
+	}
+	var req EligibleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !h.validateTerm(c, req.Term) {
+		return
+	}
+
+	result, err := h.prereqs.Eligible(c.Request.Context(), req.Term, req.Completed)
+	if errors.Is(err, prereq.ErrInvalidCourse) || errors.Is(err, prereq.ErrTooManyCourses) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to check eligible courses", "term", req.Term, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check eligible courses"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetCoursePrerequisites returns a course's prerequisites in ?term= and the
+// courses that name it as a prerequisite.
+func (h *Handlers) GetCoursePrerequisites(c *gin.Context) {
+	if !h.requirePrereqs(c) {
+		return
+	}
+	subject := strings.ToUpper(strings.TrimSpace(c.Param("subject")))
+	courseNumber := strings.ToUpper(strings.TrimSpace(c.Param("courseNumber")))
+	term := c.Query("term")
+	if term == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "term is required"})
+		return
+	}
+	if !h.validateTerm(c, term) {
+		return
+	}
+
+	result, err := h.prereqs.Course(c.Request.Context(), term, subject, courseNumber)
+	if err != nil {
+		slog.Error("Failed to get course prerequisites", "term", term, "subject", subject, "courseNumber", courseNumber, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get course prerequisites"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // AnnouncementResponse wraps the announcement for JSON serialization.
 type AnnouncementResponse struct {
 	Announcement *AnnouncementInfo `json:"announcement"`
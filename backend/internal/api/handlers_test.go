@@ -1,13 +1,27 @@
 package api
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"database/sql"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"schedule-optimizer/internal/alerts"
+	"schedule-optimizer/internal/jobs"
+	"schedule-optimizer/internal/prereq"
+	"schedule-optimizer/internal/search"
+	"schedule-optimizer/internal/stats"
+	"schedule-optimizer/internal/stats/catalog"
+	"schedule-optimizer/internal/store"
 	"schedule-optimizer/internal/testutil"
 )
 
@@ -289,3 +303,513 @@ func TestValidateCourses_BatchLimit(t *testing.T) {
 		t.Errorf("body = %s, want to contain 'Maximum 20 courses'", w.Body.String())
 	}
 }
+
+func TestGetCourseHistory(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	testutil.SeedTestData(t, db)
+	defer db.Close()
+
+	h := NewHandlers(db, nil, nil, queries, nil, stats.NewService(nil, catalog.NewService(queries, nil)))
+	r := gin.New()
+	r.GET("/api/course/:subject/:courseNumber/history", h.GetCourseHistory)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantTerms  int
+	}{
+		{"offered in two terms", "/api/course/csci/247/history", http.StatusOK, 2},
+		{"never offered", "/api/course/CSCI/999/history", http.StatusNotFound, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got catalog.CourseHistory
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(got.Offerings) != tt.wantTerms {
+				t.Errorf("offerings = %d, want %d", len(got.Offerings), tt.wantTerms)
+			}
+		})
+	}
+}
+
+func TestCatalogDisabled(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	testutil.SeedTestData(t, db)
+	defer db.Close()
+
+	h := NewHandlers(db, nil, nil, queries, nil, nil)
+	r := gin.New()
+	r.GET("/api/course/:subject/:courseNumber/history", h.GetCourseHistory)
+	r.GET("/api/instructors", h.SearchInstructors)
+	r.GET("/api/instructors/:name", h.GetInstructor)
+	r.GET("/api/crn/:crn/history", h.GetCRNHistory)
+	r.GET("/api/terms/:term/changes", h.GetTermChanges)
+
+	for _, path := range []string{
+		"/api/crn/20001/history?term=202520",
+		"/api/terms/202520/changes",
+		"/api/course/CSCI/247/history",
+		"/api/instructors?q=smith",
+		"/api/instructors/Dr.%20Smith",
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s: status = %d, want 503", path, w.Code)
+		}
+	}
+}
+
+func TestCourseDetailsInResponses(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	testutil.SeedTestData(t, db)
+	defer db.Close()
+
+	ctx := context.Background()
+	if err := queries.UpsertCourseDetails(ctx, store.UpsertCourseDetailsParams{
+		Term:          "202520",
+		Subject:       "CSCI",
+		CourseNumber:  "247",
+		Description:   sql.NullString{String: "Linear data structures.", Valid: true},
+		Prerequisites: sql.NullString{String: "CSCI 145 C-", Valid: true},
+	}); err != nil {
+		t.Fatalf("UpsertCourseDetails failed: %v", err)
+	}
+	for _, link := range []store.InsertSectionLinkParams{
+		{Term: "202520", Crn: "20001", LinkGroup: 0, LinkedCrn: "20011"},
+		{Term: "202520", Crn: "20001", LinkGroup: 1, LinkedCrn: "20012"},
+		{Term: "202520", Crn: "20001", LinkGroup: 1, LinkedCrn: "20013"},
+	} {
+		if err := queries.InsertSectionLink(ctx, link); err != nil {
+			t.Fatalf("InsertSectionLink failed: %v", err)
+		}
+	}
+
+	h := NewHandlers(db, nil, nil, queries, nil, nil)
+	r := gin.New()
+	r.GET("/api/crn/:crn", h.GetCRN)
+	r.GET("/api/course/:subject/:courseNumber", h.GetCourse)
+	wantLinks := [][]string{{"20011"}, {"20012", "20013"}}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/crn/20001?term=202520", nil))
+	var crn CRNResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &crn); err != nil || crn.Section == nil {
+		t.Fatalf("bad CRN response %d: %s", w.Code, w.Body.String())
+	}
+	if d := crn.Section.Details; d == nil || d.Description != "Linear data structures." || d.Prerequisites != "CSCI 145 C-" {
+		t.Errorf("section details = %+v", d)
+	}
+	if !reflect.DeepEqual(crn.Section.LinkedSections, wantLinks) {
+		t.Errorf("linked sections = %v, want %v", crn.Section.LinkedSections, wantLinks)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/course/CSCI/247?term=202520", nil))
+	var course struct {
+		Course   CourseInfo          `json:"course"`
+		Sections []CourseSectionInfo `json:"sections"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &course); err != nil {
+		t.Fatalf("bad course response %d: %s", w.Code, w.Body.String())
+	}
+	if course.Course.Details == nil || course.Course.Details.Description != "Linear data structures." {
+		t.Errorf("course details = %+v", course.Course.Details)
+	}
+	if len(course.Sections) != 1 || !reflect.DeepEqual(course.Sections[0].LinkedSections, wantLinks) {
+		t.Errorf("sections = %+v, want 20001 linked to %v", course.Sections, wantLinks)
+	}
+
+	// Courses without fetched details omit them
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/crn/20003?term=202520", nil))
+	if strings.Contains(w.Body.String(), "details") || strings.Contains(w.Body.String(), "linkedSections") {
+		t.Errorf("response without details = %s", w.Body.String())
+	}
+}
+
+func TestGetEligibleCourses(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	testutil.SeedTestData(t, db)
+	defer db.Close()
+
+	ctx := context.Background()
+	for _, details := range []store.UpsertCourseDetailsParams{
+		{Term: "202520", Subject: "CSCI", CourseNumber: "247", Prerequisites: sql.NullString{String: "CSCI 145 C-", Valid: true}},
+		{Term: "202520", Subject: "CSCI", CourseNumber: "301", Prerequisites: sql.NullString{String: "CSCI 247 or instructor permission", Valid: true}},
+		{Term: "202520", Subject: "MATH", CourseNumber: "204"},
+	} {
+		if err := queries.UpsertCourseDetails(ctx, details); err != nil {
+			t.Fatalf("UpsertCourseDetails failed: %v", err)
+		}
+	}
+
+	prereqs := prereq.NewService(db, queries)
+	if _, err := prereqs.RebuildStale(context.Background()); err != nil {
+		t.Fatalf("RebuildStale failed: %v", err)
+	}
+	h := NewHandlers(db, nil, nil, queries, nil, nil)
+	h.SetPrerequisites(prereqs)
+	r := gin.New()
+	r.POST("/api/prerequisites/eligible", h.GetEligibleCourses)
+	r.GET("/api/course/:subject/:courseNumber/prerequisites", h.GetCoursePrerequisites)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/prerequisites/eligible", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(`{"term": "202520", "completed": ["MATH 204"]}`)
+	var result prereq.Eligibility
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("bad eligible response %d: %s", w.Code, w.Body.String())
+	}
+	if len(result.Eligible) != 0 || len(result.Unverified) != 1 || len(result.Ineligible) != 1 {
+		t.Fatalf("result = %+v, want CSCI 301 unverified and CSCI 247 ineligible", result)
+	}
+	if c := result.Ineligible[0]; c.CourseNumber != "247" || !reflect.DeepEqual(c.Missing, []string{"CSCI 145"}) {
+		t.Errorf("ineligible = %+v, want CSCI 247 missing CSCI 145", c)
+	}
+
+	if w := post(`{"term": "202520", "completed": ["Data Structures"]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid course status = %d, want 400", w.Code)
+	}
+	if w := post(`{"term": "199910"}`); w.Code != http.StatusNotFound {
+		t.Errorf("unknown term status = %d, want 404", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/course/csci/247/prerequisites?term=202520", nil))
+	var course prereq.CoursePrerequisites
+	if err := json.Unmarshal(w.Body.Bytes(), &course); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("bad prerequisites response %d: %s", w.Code, w.Body.String())
+	}
+	if course.Prerequisites == nil || course.Prerequisites.Course != "CSCI 145" || !reflect.DeepEqual(course.Unlocks, []string{"CSCI 301"}) {
+		t.Errorf("CSCI 247 = %+v, want CSCI 145 required, unlocks CSCI 301", course)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/course/CSCI/247/prerequisites?term=209920", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown term status = %d, want 404", w.Code)
+	}
+
+	// Without the service both endpoints are unavailable
+	disabled := NewHandlers(db, nil, nil, queries, nil, nil)
+	r = gin.New()
+	r.POST("/api/prerequisites/eligible", disabled.GetEligibleCourses)
+	r.GET("/api/course/:subject/:courseNumber/prerequisites", disabled.GetCoursePrerequisites)
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/api/prerequisites/eligible", strings.NewReader(`{"term": "202520"}`)),
+		httptest.NewRequest(http.MethodGet, "/api/course/CSCI/247/prerequisites?term=202520", nil),
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s %s: status = %d, want 503", req.Method, req.URL.Path, w.Code)
+		}
+	}
+}
+
+func TestGetScrapeRuns(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	for i, term := range []string{"202510", "202520", "202520"} {
+		id, err := queries.CreateScrapeRun(ctx, store.CreateScrapeRunParams{Term: term, Job: "active-scrape"})
+		if err != nil {
+			t.Fatalf("create run: %v", err)
+		}
+		if term == "202510" {
+			continue
+		}
+		params := store.FinishScrapeRunParams{Status: "success", Stored: 2, Expected: 2, ID: id}
+		if i == 1 {
+			params = store.FinishScrapeRunParams{Status: "partial", Stored: 1, Expected: 2, SaveErrors: 1, ID: id}
+		}
+		if err := queries.FinishScrapeRun(ctx, params); err != nil {
+			t.Fatalf("finish run: %v", err)
+		}
+	}
+
+	h := NewHandlers(db, nil, nil, queries, nil, nil)
+	r := gin.New()
+	r.GET("/api/admin/scrape-runs", h.GetScrapeRuns)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantRuns   int
+	}{
+		{"all terms", "", http.StatusOK, 3},
+		{"one term", "?term=202520", http.StatusOK, 2},
+		{"limited", "?limit=1", http.StatusOK, 1},
+		{"bad limit", "?limit=x", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/scrape-runs"+tt.query, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got struct {
+				Runs []ScrapeRunResponse `json:"runs"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(got.Runs) != tt.wantRuns {
+				t.Fatalf("runs = %d, want %d", len(got.Runs), tt.wantRuns)
+			}
+			if tt.query == "" {
+				// Newest first; the 202510 run never finished
+				if got.Runs[2].Status != "running" || got.Runs[2].FinishedAt != nil {
+					t.Errorf("oldest run = %+v, want running with no finish time", got.Runs[2])
+				}
+				if got.Runs[0].Status != "success" || got.Runs[0].FinishedAt == nil {
+					t.Errorf("newest run = %+v, want finished success", got.Runs[0])
+				}
+				if got.Runs[1].Status != "partial" || got.Runs[1].SaveErrors != 1 {
+					t.Errorf("middle run = %+v, want partial with one save error", got.Runs[1])
+				}
+				if !strings.Contains(w.Body.String(), `"saveErrors":1`) {
+					t.Errorf("body = %s, want camelCase saveErrors", w.Body.String())
+				}
+			}
+		})
+	}
+}
+
+const testSessionID = "6f1c2d3e-4b5a-4c7d-8e9f-0a1b2c3d4e5f"
+
+func TestJobsAdmin(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	// The service is never started, so triggers only queue work
+	jobsSvc := jobs.NewService(time.Minute)
+	jobsSvc.Register(jobs.NewTermScrapeJob(queries, nil))
+
+	h := NewHandlers(db, nil, nil, queries, nil, nil)
+	r := gin.New()
+	r.GET("/api/admin/jobs", h.GetJobs)
+	r.POST("/api/admin/jobs/pause", h.PauseJobs)
+	r.POST("/api/admin/jobs/resume", h.ResumeJobs)
+	r.POST("/api/admin/jobs/:name/trigger", h.TriggerJob)
+	r.POST("/api/admin/terms/:term/scrape", h.TriggerTermScrape)
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	if w := do(http.MethodGet, "/api/admin/jobs"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status without jobs = %d, want 503", w.Code)
+	}
+	h.SetJobs(jobsSvc)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{"list", http.MethodGet, "/api/admin/jobs", http.StatusOK},
+		{"trigger", http.MethodPost, "/api/admin/jobs/term-scrape/trigger", http.StatusAccepted},
+		{"trigger unknown job", http.MethodPost, "/api/admin/jobs/nope/trigger", http.StatusNotFound},
+		{"scrape term", http.MethodPost, "/api/admin/terms/202520/scrape", http.StatusAccepted},
+		{"scrape term not stored yet", http.MethodPost, "/api/admin/terms/203010/scrape", http.StatusAccepted},
+		{"scrape invalid term", http.MethodPost, "/api/admin/terms/202550/scrape", http.StatusBadRequest},
+		{"pause", http.MethodPost, "/api/admin/jobs/pause", http.StatusOK},
+		{"trigger while paused", http.MethodPost, "/api/admin/jobs/term-scrape/trigger", http.StatusConflict},
+		{"scrape while paused", http.MethodPost, "/api/admin/terms/202520/scrape", http.StatusConflict},
+		{"resume", http.MethodPost, "/api/admin/jobs/resume", http.StatusOK},
+	}
+	for _, tt := range tests {
+		w := do(tt.method, tt.path)
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s: status = %d, want %d: %s", tt.name, w.Code, tt.wantStatus, w.Body.String())
+		}
+	}
+
+	var status jobs.Status
+	if err := json.Unmarshal(do(http.MethodGet, "/api/admin/jobs").Body.Bytes(), &status); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if status.Paused || len(status.Jobs) != 1 || status.Jobs[0].Name != "term-scrape" || !status.Jobs[0].Triggered {
+		t.Errorf("status = %+v, want unpaused with term-scrape triggered", status)
+	}
+}
+
+func TestSavedSearches(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	testutil.SeedTestData(t, db)
+	defer db.Close()
+
+	searchService := search.NewService(db, queries, nil, nil)
+	h := NewHandlers(db, nil, nil, queries, searchService, nil)
+	h.SetAlerts(alerts.NewService(queries, searchService))
+	r := gin.New()
+	r.POST("/api/saved-searches", h.CreateSavedSearch)
+	r.GET("/api/saved-searches", h.ListSavedSearches)
+	r.DELETE("/api/saved-searches/:id", h.DeleteSavedSearch)
+
+	do := func(method, path, body string, withSession bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if withSession {
+			req.Header.Set("X-Session-ID", testSessionID)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name        string
+		body        string
+		withSession bool
+		wantStatus  int
+	}{
+		{"no session", `{"name": "CSCI", "query": "subject=CSCI"}`, false, http.StatusBadRequest},
+		{"missing query", `{"name": "CSCI"}`, true, http.StatusBadRequest},
+		{"unknown term", `{"name": "CSCI", "query": "term=209920&subject=CSCI"}`, true, http.StatusNotFound},
+		{"saved", `{"name": "CSCI", "query": "term=202520&subject=CSCI"}`, true, http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := do(http.MethodPost, "/api/saved-searches", tt.body, tt.withSession)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+
+	w := do(http.MethodGet, "/api/saved-searches", "", true)
+	var list struct {
+		SavedSearches []alerts.SavedSearch `json:"savedSearches"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(list.SavedSearches) != 1 || list.SavedSearches[0].Sections != 2 {
+		t.Fatalf("saved searches = %+v", list.SavedSearches)
+	}
+
+	path := "/api/saved-searches/" + strconv.FormatInt(list.SavedSearches[0].ID, 10)
+	if w := do(http.MethodDelete, path, "", true); w.Code != http.StatusNoContent {
+		t.Errorf("delete status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if w := do(http.MethodDelete, path, "", true); w.Code != http.StatusNotFound {
+		t.Errorf("second delete status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestSavedSearches_Disabled(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	h := NewHandlers(db, nil, nil, queries, nil, nil)
+	r := gin.New()
+	r.POST("/api/saved-searches", h.CreateSavedSearch)
+	r.GET("/api/saved-searches", h.ListSavedSearches)
+	r.DELETE("/api/saved-searches/:id", h.DeleteSavedSearch)
+	r.GET("/api/saved-searches/alerts", h.GetSavedSearchAlerts)
+	r.GET("/api/saved-searches/alerts/stream", h.StreamSavedSearchAlerts)
+
+	for _, route := range []struct{ method, path string }{
+		{http.MethodPost, "/api/saved-searches"},
+		{http.MethodGet, "/api/saved-searches"},
+		{http.MethodDelete, "/api/saved-searches/1"},
+		{http.MethodGet, "/api/saved-searches/alerts"},
+		{http.MethodGet, "/api/saved-searches/alerts/stream"},
+	} {
+		req := httptest.NewRequest(route.method, route.path, strings.NewReader(`{"name": "CSCI", "query": "subject=CSCI"}`))
+		req.Header.Set("X-Session-ID", testSessionID)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s %s: status = %d, want 503", route.method, route.path, w.Code)
+		}
+	}
+}
+
+func TestStreamSavedSearchAlerts(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	testutil.SeedTestData(t, db)
+	defer db.Close()
+
+	_, err := db.Exec(strings.ReplaceAll(`
+		INSERT INTO saved_searches (id, session_id, name, params) VALUES (1, 'SESSION', 'CSCI', 'subject=CSCI');
+		INSERT INTO saved_search_alerts (saved_search_id, session_id, kind, term, crn, course_key, title, seats_available)
+		VALUES
+			(1, 'SESSION', 'seats_opened', '202520', '20002', 'CSCI:301', 'Algorithms', 2),
+			(1, 'SESSION', 'new_section', '202520', '20005', 'CSCI:247', 'Data Structures', 30);
+	`, "SESSION", testSessionID))
+	if err != nil {
+		t.Fatalf("failed to seed alerts: %v", err)
+	}
+
+	h := NewHandlers(db, nil, nil, queries, nil, nil)
+	h.SetAlerts(alerts.NewService(queries, nil))
+	r := gin.New()
+	r.GET("/api/saved-searches/alerts/stream", h.StreamSavedSearchAlerts)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// EventSource resumes with Last-Event-ID, and passes the session as a parameter
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/saved-searches/alerts/stream?sessionId="+testSessionID, nil)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("content type = %q", ct)
+	}
+	lines := bufio.NewScanner(resp.Body)
+	var event []string
+	for lines.Scan() && lines.Text() != "" {
+		event = append(event, lines.Text())
+	}
+	if len(event) != 3 || event[0] != "id: 2" || event[1] != "event: alert" ||
+		!strings.Contains(event[2], `"crn":"20005"`) {
+		t.Errorf("event = %q", event)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/saved-searches/alerts/stream", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status without a session = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,182 @@
+package cache
+
+import (
+	"slices"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// Policy bounds how many terms the cache keeps resident.
+// When a load pushes the cache over either limit, the least recently used
+// unpinned terms are evicted until it fits. Zero values mean unlimited.
+type Policy struct {
+	MaxTerms int
+	MaxBytes int64
+	IsPinned func(term string) bool // Pinned terms are never evicted
+}
+
+func (p Policy) isPinned(term string) bool {
+	return p.IsPinned != nil && p.IsPinned(term)
+}
+
+// SetPolicy configures eviction limits. It does not evict immediately; limits
+// are enforced on the next load.
+func (c *ScheduleCache) SetPolicy(p Policy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.policy = p
+}
+
+// counters tracks cache activity for the admin metrics endpoint.
+type counters struct {
+	hits          atomic.Int64
+	misses        atomic.Int64
+	loads         atomic.Int64
+	reloads       atomic.Int64
+	seatRefreshes atomic.Int64
+	evictions     atomic.Int64
+}
+
+// Metrics is a point-in-time snapshot of cache activity and residency.
+type Metrics struct {
+	Hits          int64        `json:"hits"`
+	Misses        int64        `json:"misses"`
+	Loads         int64        `json:"loads"`
+	Reloads       int64        `json:"reloads"`
+	SeatRefreshes int64        `json:"seatRefreshes"`
+	Evictions     int64        `json:"evictions"`
+	ResidentTerms int          `json:"residentTerms"`
+	ResidentBytes int64        `json:"residentBytes"`
+	MaxTerms      int          `json:"maxTerms"`
+	MaxBytes      int64        `json:"maxBytes"`
+	Terms         []TermStatus `json:"terms"`
+}
+
+// Metrics returns hit/load/eviction counters and per-term residency.
+func (c *ScheduleCache) Metrics() Metrics {
+	terms := c.Status()
+
+	var bytes int64
+	for _, t := range terms {
+		bytes += t.Bytes
+	}
+
+	c.mu.RLock()
+	policy := c.policy
+	c.mu.RUnlock()
+
+	return Metrics{
+		Hits:          c.metrics.hits.Load(),
+		Misses:        c.metrics.misses.Load(),
+		Loads:         c.metrics.loads.Load(),
+		Reloads:       c.metrics.reloads.Load(),
+		SeatRefreshes: c.metrics.seatRefreshes.Load(),
+		Evictions:     c.metrics.evictions.Load(),
+		ResidentTerms: len(terms),
+		ResidentBytes: bytes,
+		MaxTerms:      policy.MaxTerms,
+		MaxBytes:      policy.MaxBytes,
+		Terms:         terms,
+	}
+}
+
+// trackLocked starts access tracking for a newly loaded term. Caller must hold the write lock.
+func (c *ScheduleCache) trackLocked(term string) {
+	if _, ok := c.lastAccess[term]; !ok {
+		c.lastAccess[term] = &atomic.Int64{}
+	}
+	c.touch(term)
+}
+
+// touch records a read of term. Safe under the read lock since only the
+// map lookup needs it; the timestamp itself is atomic.
+func (c *ScheduleCache) touch(term string) {
+	if a, ok := c.lastAccess[term]; ok {
+		a.Store(time.Now().UnixNano())
+	}
+}
+
+func (c *ScheduleCache) lastAccessLocked(term string) time.Time {
+	if a, ok := c.lastAccess[term]; ok {
+		return time.Unix(0, a.Load())
+	}
+	return time.Time{}
+}
+
+// evictLocked removes least recently used unpinned terms until the cache fits the
+// policy. The term just loaded is never evicted. Caller must hold the write lock.
+// Returns the evicted terms.
+func (c *ScheduleCache) evictLocked(keep string) []string {
+	var evicted []string
+	for c.overLimitLocked() {
+		victim := ""
+		var oldest int64
+		for _, term := range c.activeTerms {
+			if term == keep || c.policy.isPinned(term) {
+				continue
+			}
+			at := c.lastAccess[term].Load()
+			if victim == "" || at < oldest {
+				victim, oldest = term, at
+			}
+		}
+		if victim == "" {
+			break // Everything left is pinned
+		}
+		c.removeLocked(victim)
+		c.metrics.evictions.Add(1)
+		evicted = append(evicted, victim)
+	}
+	return evicted
+}
+
+func (c *ScheduleCache) overLimitLocked() bool {
+	if c.policy.MaxTerms > 0 && len(c.terms) > c.policy.MaxTerms {
+		return true
+	}
+	if c.policy.MaxBytes > 0 {
+		var total int64
+		for _, t := range c.terms {
+			total += t.Bytes
+		}
+		return total > c.policy.MaxBytes
+	}
+	return false
+}
+
+// removeLocked drops a term and its bookkeeping. Caller must hold the write lock.
+func (c *ScheduleCache) removeLocked(term string) {
+	delete(c.terms, term)
+	delete(c.lastAccess, term)
+	if i := slices.Index(c.activeTerms, term); i >= 0 {
+		c.activeTerms = slices.Delete(c.activeTerms, i, i+1)
+	}
+}
+
+// Approximate per-entry overhead of Go maps and slice headers, used by estimateBytes.
+const (
+	mapEntryOverhead = 48
+	indexEntryBytes  = int64(unsafe.Sizeof(uintptr(0)))
+)
+
+// estimateBytes approximates the heap held by a term: course structs, their
+// strings and meeting times, plus the three indexes. It's intended for relative
+// budgeting, not exact accounting.
+func estimateBytes(t *TermData) int64 {
+	var total int64
+	for crn, course := range t.Courses {
+		total += int64(unsafe.Sizeof(*course)) + mapEntryOverhead + int64(len(crn))
+		total += int64(len(course.Term) + len(course.CRN) + len(course.Subject) +
+			len(course.SubjectDescription) + len(course.CourseNumber) + len(course.Title) +
+			len(course.Instructor) + len(course.InstructorEmail) + len(course.InstructionalMethod) +
+			len(course.GPASource))
+		for _, mt := range course.MeetingTimes {
+			total += int64(unsafe.Sizeof(mt)) +
+				int64(len(mt.StartTime)+len(mt.EndTime)+len(mt.Building)+len(mt.Room))
+		}
+		total += 2 * indexEntryBytes // BySubject and ByCourseCode slots
+	}
+	total += int64(len(t.BySubject)+len(t.ByCourseCode)) * mapEntryOverhead
+	return total
+}
@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"schedule-optimizer/internal/testutil"
+)
+
+func TestEviction_MaxTerms(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	testutil.SeedTestData(t, db)
+
+	cache := NewScheduleCache(queries, nil)
+	cache.SetPolicy(Policy{MaxTerms: 2})
+	ctx := context.Background()
+
+	cache.LoadTerm(ctx, "202520")
+	cache.LoadTerm(ctx, "202510")
+	time.Sleep(time.Millisecond)
+
+	// Touch 202520 so 202510 becomes least recently used
+	cache.GetCourse("202520", "20001")
+
+	cache.LoadTerm(ctx, "999999")
+
+	if !cache.IsTermLoaded("202520") {
+		t.Error("recently used term should stay resident")
+	}
+	if cache.IsTermLoaded("202510") {
+		t.Error("least recently used term should be evicted")
+	}
+	if !cache.IsTermLoaded("999999") {
+		t.Error("just-loaded term should never be evicted")
+	}
+	if terms := cache.GetActiveTerms(); len(terms) != 2 {
+		t.Errorf("expected 2 active terms after eviction, got %v", terms)
+	}
+
+	m := cache.Metrics()
+	if m.Evictions != 1 || m.Loads != 3 || m.ResidentTerms != 2 {
+		t.Errorf("metrics = %+v, want 1 eviction, 3 loads, 2 resident", m)
+	}
+}
+
+func TestEviction_PinnedTermsStay(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	testutil.SeedTestData(t, db)
+
+	cache := NewScheduleCache(queries, nil)
+	cache.SetPolicy(Policy{
+		MaxTerms: 1,
+		IsPinned: func(term string) bool { return term == "202520" },
+	})
+	ctx := context.Background()
+
+	cache.LoadTerm(ctx, "202520")
+	cache.LoadTerm(ctx, "202510")
+	cache.LoadTerm(ctx, "999999")
+
+	if !cache.IsTermLoaded("202520") {
+		t.Error("pinned term should never be evicted")
+	}
+	if cache.IsTermLoaded("202510") {
+		t.Error("unpinned term should be evicted")
+	}
+
+	// Over the limit only because of the pinned term; the newest load stays
+	if !cache.IsTermLoaded("999999") {
+		t.Error("just-loaded term should stay even when pinned terms fill the budget")
+	}
+}
+
+func TestEviction_MaxBytes(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	testutil.SeedTestData(t, db)
+
+	cache := NewScheduleCache(queries, nil)
+	ctx := context.Background()
+	cache.LoadTerm(ctx, "202520")
+	termBytes := cache.Metrics().ResidentBytes
+	if termBytes <= 0 {
+		t.Fatalf("expected positive byte estimate, got %d", termBytes)
+	}
+
+	cache.SetPolicy(Policy{MaxBytes: termBytes})
+	cache.LoadTerm(ctx, "202510")
+
+	if cache.IsTermLoaded("202520") {
+		t.Error("older term should be evicted to fit the byte budget")
+	}
+	if !cache.IsTermLoaded("202510") {
+		t.Error("new term should be resident")
+	}
+}
+
+func TestMetrics_HitsAndMisses(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	testutil.SeedTestData(t, db)
+
+	cache := NewScheduleCache(queries, nil)
+	ctx := context.Background()
+
+	cache.LoadTermIfNeeded(ctx, "202520")
+	cache.LoadTermIfNeeded(ctx, "202520")
+	cache.LoadTermIfNeeded(ctx, "202520")
+
+	m := cache.Metrics()
+	if m.Misses != 1 || m.Hits != 2 {
+		t.Errorf("hits = %d, misses = %d, want 2 and 1", m.Hits, m.Misses)
+	}
+	if len(m.Terms) != 1 || m.Terms[0].LastAccess.IsZero() {
+		t.Errorf("expected one term with a recorded access, got %+v", m.Terms)
+	}
+}
@@ -6,9 +6,11 @@ package cache
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"log/slog"
 	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/sync/singleflight"
@@ -40,6 +42,7 @@ type Course struct {
 	GPA                 float64       `json:"gpa,omitempty"`
 	GPASource           string        `json:"gpaSource,omitempty"` // "course_professor", "course", ""
 	PassRate            *float64      `json:"passRate,omitempty"`
+	Mask                [8]uint64     `json:"-"` // Conflict bitmask from SetMaskFunc; zero if unset or async/TBD
 }
 
 // MeetingTime represents when and where a course meets.
@@ -51,13 +54,29 @@ type MeetingTime struct {
 	Room      string  `json:"room,omitempty"`
 }
 
+const (
+	// reloadTimeout bounds a background term rebuild triggered by a scrape.
+	reloadTimeout = 2 * time.Minute
+
+	// fullReloadInterval is how long seat-only refreshes are used before a scrape
+	// triggers a full rebuild to pick up meeting time, instructor, and title changes.
+	fullReloadInterval = 12 * time.Hour
+)
+
 // TermData holds all courses for a single term, indexed for fast access.
+// A TermData is immutable once published; reloads build a new one and swap it in.
 type TermData struct {
-	Term         string
-	LoadedAt     time.Time
-	Courses      map[string]*Course   // CRN -> Course
-	BySubject    map[string][]*Course // Subject -> Courses
-	ByCourseCode map[string][]*Course // "CSCI:247" -> Courses (all sections)
+	Term             string
+	LoadedAt         time.Time            // Last full rebuild
+	SeatsRefreshedAt time.Time            // Last seat-count patch, or LoadedAt
+	Version          uint64               // Increases on every load or patch of any term
+	Bytes            int64                // Estimated resident size, see estimateBytes
+	Courses          map[string]*Course   // CRN -> Course
+	BySubject        map[string][]*Course // Subject -> Courses
+	ByCourseCode     map[string][]*Course // "CSCI:247" -> Courses (all sections)
+
+	seatsSince time.Time    // Sections updated at or after this are re-read by RefreshSeats
+	scrapedAt  sql.NullTime // Term's last_scraped_at when this data was read, used to validate snapshots
 }
 
 // ScheduleCache holds course data for active terms used in schedule generation.
@@ -68,6 +87,33 @@ type ScheduleCache struct {
 	queries      *store.Queries
 	gradeService *grades.Service
 	loadGroup    singleflight.Group // Deduplicates concurrent LoadTerm calls
+	reloadGroup  singleflight.Group // Deduplicates seat refreshes, separate so requests never wait on them
+	version      atomic.Uint64
+
+	reloadMu sync.Mutex
+	reloads  map[string]bool // Term -> another rebuild is queued; present while a rebuild runs
+
+	policy     Policy
+	lastAccess map[string]*atomic.Int64 // Term -> unix nanos of last read, guarded by mu for map access
+	metrics    counters
+
+	maskFunc    func([]MeetingTime) [8]uint64 // Precomputes Course.Mask when set
+	snapshotDir string                        // Snapshots are disabled when empty
+
+	snapshotMu      sync.Mutex
+	snapshotWriters map[string]*snapshotWriter // Term -> writer, guarded by snapshotMu
+}
+
+// TermStatus describes the freshness of a cached term.
+type TermStatus struct {
+	Term             string    `json:"term"`
+	LoadedAt         time.Time `json:"loadedAt"`
+	SeatsRefreshedAt time.Time `json:"seatsRefreshedAt"`
+	Version          uint64    `json:"version"`
+	Courses          int       `json:"courses"`
+	Bytes            int64     `json:"bytes"`
+	LastAccess       time.Time `json:"lastAccess"`
+	Pinned           bool      `json:"pinned"`
 }
 
 // NewScheduleCache creates a new schedule cache.
@@ -76,21 +122,194 @@ func NewScheduleCache(queries *store.Queries, gradeService *grades.Service) *Sch
 		terms:        make(map[string]*TermData),
 		queries:      queries,
 		gradeService: gradeService,
+		lastAccess:   make(map[string]*atomic.Int64),
+		reloads:      make(map[string]bool),
+
+		snapshotWriters: make(map[string]*snapshotWriter),
 	}
 }
 
 // LoadTerm loads all course data for a term into memory using sqlc queries.
+// When the term isn't resident, a valid on-disk snapshot is used in place of the database.
 func (c *ScheduleCache) LoadTerm(ctx context.Context, term string) error {
 	start := time.Now()
 
+	var termData *TermData
+	source := "snapshot"
+	if !c.IsTermLoaded(term) {
+		termData = c.loadSnapshot(ctx, term)
+	}
+	if termData == nil {
+		source = "database"
+		var err error
+		termData, err = c.buildTerm(ctx, term)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	c.terms[term] = termData
+	// Track active terms
+	found := slices.Contains(c.activeTerms, term)
+	if !found {
+		c.activeTerms = append(c.activeTerms, term)
+	}
+	c.trackLocked(term)
+	evicted := c.evictLocked(term)
+	c.mu.Unlock()
+
+	c.metrics.loads.Add(1)
+	for _, t := range evicted {
+		slog.Info("Evicted term from schedule cache", "term", t)
+	}
+
+	slog.Info("Loaded term into schedule cache",
+		"term", term,
+		"source", source,
+		"courses", len(termData.Courses),
+		"subjects", len(termData.BySubject),
+		"version", termData.Version,
+		"duration", time.Since(start),
+	)
+
+	if source == "database" {
+		c.writeSnapshot(termData)
+	}
+	return nil
+}
+
+// ReloadTerm rebuilds an already-loaded term in the background and swaps it in.
+// Readers keep serving the previous TermData until the rebuild completes, so
+// requests never block on a reload. Terms that aren't loaded are skipped since
+// they'll be read fresh on first request. A call while a rebuild is running
+// queues one more rebuild after it, since the running one may have read the
+// database before the change that prompted the call.
+func (c *ScheduleCache) ReloadTerm(term string) {
+	if !c.IsTermLoaded(term) {
+		return
+	}
+
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+	if _, running := c.reloads[term]; running {
+		c.reloads[term] = true
+		return
+	}
+	c.reloads[term] = false
+	go c.reloadLoop(term)
+}
+
+// reloadLoop rebuilds a term until no further reload was requested during the last rebuild.
+func (c *ScheduleCache) reloadLoop(term string) {
+	for {
+		retry := c.reload(term)
+
+		c.reloadMu.Lock()
+		if !retry && !c.reloads[term] {
+			delete(c.reloads, term)
+			c.reloadMu.Unlock()
+			return
+		}
+		c.reloads[term] = false
+		c.reloadMu.Unlock()
+	}
+}
+
+// reload rebuilds a term once and swaps it in if the published TermData is
+// still the one seen before the rebuild. Reports whether another TermData was
+// published meanwhile, in which case the rebuild is discarded and should be retried.
+func (c *ScheduleCache) reload(term string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), reloadTimeout)
+	defer cancel()
+
+	c.mu.RLock()
+	base, loaded := c.terms[term]
+	c.mu.RUnlock()
+	if !loaded {
+		return false
+	}
+
+	start := time.Now()
+	termData, err := c.buildTerm(ctx, term)
+	if err != nil {
+		slog.Error("Failed to reload term", "term", term, "error", err)
+		return false
+	}
+
+	c.mu.Lock()
+	// Don't resurrect a term that was unloaded while rebuilding, and don't
+	// overwrite a load or seat patch that may have read after this rebuild did
+	current, loaded := c.terms[term]
+	swapped := current == base
+	if swapped {
+		c.terms[term] = termData
+	}
+	c.mu.Unlock()
+	if !swapped {
+		return loaded
+	}
+	c.metrics.reloads.Add(1)
+
+	slog.Info("Reloaded term in schedule cache",
+		"term", term,
+		"courses", len(termData.Courses),
+		"version", termData.Version,
+		"duration", time.Since(start),
+	)
+	c.writeSnapshot(termData)
+	return false
+}
+
+// TermScraped implements jobs.ScrapeListener. Seat counts are patched in place of
+// a full rebuild unless the term hasn't been fully reloaded within fullReloadInterval.
+func (c *ScheduleCache) TermScraped(term string) {
+	c.mu.RLock()
+	termData, ok := c.terms[term]
+	c.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	if time.Since(termData.LoadedAt) >= fullReloadInterval {
+		c.ReloadTerm(term)
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), reloadTimeout)
+		defer cancel()
+		if _, err := c.RefreshSeats(ctx, term); err != nil {
+			slog.Error("Failed to refresh seats", "term", term, "error", err)
+		}
+	}()
+}
+
+// buildTerm reads a term from the database and builds its indexes without holding the lock.
+func (c *ScheduleCache) buildTerm(ctx context.Context, term string) (*TermData, error) {
+	// Taken before reading so writes racing the read are picked up by the next RefreshSeats.
+	// updated_at has second resolution, hence the truncation.
+	readStart := time.Now().UTC().Truncate(time.Second)
+
+	c.mu.RLock()
+	maskFunc := c.maskFunc
+	c.mu.RUnlock()
+
+	// Read before sections: if a scrape lands mid-read, the snapshot is tagged with
+	// the older scrape time and won't validate on restart.
+	scrapedAt, err := c.termScrapedAt(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+
 	sections, err := c.queries.GetSectionsWithInstructorByTerm(ctx, term)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	meetingTimes, err := c.queries.GetMeetingTimesByTerm(ctx, term)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Index by section ID to avoid N+1 queries when building courses
@@ -99,14 +318,7 @@ func (c *ScheduleCache) LoadTerm(ctx context.Context, term string) error {
 		meetingsBySection[m.SectionID] = append(meetingsBySection[m.SectionID], m)
 	}
 
-	termData := &TermData{
-		Term:         term,
-		LoadedAt:     time.Now(),
-		Courses:      make(map[string]*Course, len(sections)),
-		BySubject:    make(map[string][]*Course),
-		ByCourseCode: make(map[string][]*Course),
-	}
-
+	courses := make([]*Course, 0, len(sections))
 	for _, s := range sections {
 		course := &Course{
 			ID:                  s.ID,
@@ -148,6 +360,31 @@ func (c *ScheduleCache) LoadTerm(ctx context.Context, term string) error {
 			course.MeetingTimes = append(course.MeetingTimes, mt)
 		}
 
+		if maskFunc != nil {
+			course.Mask = maskFunc(course.MeetingTimes)
+		}
+		courses = append(courses, course)
+	}
+
+	return c.newTermData(term, courses, readStart, scrapedAt), nil
+}
+
+// newTermData indexes courses into a new TermData and attaches grade data.
+func (c *ScheduleCache) newTermData(term string, courses []*Course, seatsSince time.Time, scrapedAt sql.NullTime) *TermData {
+	now := time.Now()
+	termData := &TermData{
+		Term:             term,
+		LoadedAt:         now,
+		SeatsRefreshedAt: now,
+		Version:          c.version.Add(1),
+		Courses:          make(map[string]*Course, len(courses)),
+		BySubject:        make(map[string][]*Course),
+		ByCourseCode:     make(map[string][]*Course),
+		seatsSince:       seatsSince,
+		scrapedAt:        scrapedAt,
+	}
+
+	for _, course := range courses {
 		// Look up GPA and pass rate from grade data
 		if c.gradeService != nil && c.gradeService.IsLoaded() {
 			course.GPA, course.PassRate, course.GPASource = c.gradeService.LookupSectionGPA(
@@ -161,29 +398,40 @@ func (c *ScheduleCache) LoadTerm(ctx context.Context, term string) error {
 		courseCode := course.Subject + ":" + course.CourseNumber
 		termData.ByCourseCode[courseCode] = append(termData.ByCourseCode[courseCode], course)
 	}
+	termData.Bytes = estimateBytes(termData)
 
-	c.mu.Lock()
-	c.terms[term] = termData
-	// Track active terms
-	found := slices.Contains(c.activeTerms, term)
-	if !found {
-		c.activeTerms = append(c.activeTerms, term)
-	}
-	c.mu.Unlock()
+	return termData
+}
 
-	slog.Info("Loaded term into schedule cache",
-		"term", term,
-		"courses", len(termData.Courses),
-		"subjects", len(termData.BySubject),
-		"duration", time.Since(start),
-	)
+// termScrapedAt returns the term's last_scraped_at, or an invalid time for unknown terms.
+func (c *ScheduleCache) termScrapedAt(ctx context.Context, term string) (sql.NullTime, error) {
+	t, err := c.queries.GetTermByCode(ctx, term)
+	if errors.Is(err, sql.ErrNoRows) {
+		return sql.NullTime{}, nil
+	}
+	if err != nil {
+		return sql.NullTime{}, err
+	}
+	return t.LastScrapedAt, nil
+}
 
-	return nil
+// SetMaskFunc installs the function used to precompute Course.Mask for
+// conflict detection. Set it before loading terms; snapshots persist the masks.
+func (c *ScheduleCache) SetMaskFunc(fn func([]MeetingTime) [8]uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maskFunc = fn
 }
 
 // LoadTermIfNeeded loads a term if not already cached, deduplicating concurrent requests.
 // Multiple goroutines requesting the same term will share a single load operation.
 func (c *ScheduleCache) LoadTermIfNeeded(ctx context.Context, term string) error {
+	if _, ok := c.get(term); ok {
+		c.metrics.hits.Add(1)
+		return nil
+	}
+	c.metrics.misses.Add(1)
+
 	_, err, _ := c.loadGroup.Do(term, func() (any, error) {
 		if c.IsTermLoaded(term) {
 			return nil, nil
@@ -195,10 +443,7 @@ func (c *ScheduleCache) LoadTermIfNeeded(ctx context.Context, term string) error
 
 // GetCourse returns a course by CRN for a specific term.
 func (c *ScheduleCache) GetCourse(term, crn string) (*Course, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	termData, ok := c.terms[term]
+	termData, ok := c.get(term)
 	if !ok {
 		return nil, false
 	}
@@ -208,10 +453,7 @@ func (c *ScheduleCache) GetCourse(term, crn string) (*Course, bool) {
 
 // GetCoursesByCRNs returns multiple courses by their CRNs for schedule generation.
 func (c *ScheduleCache) GetCoursesByCRNs(term string, crns []string) []*Course {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	termData, ok := c.terms[term]
+	termData, ok := c.get(term)
 	if !ok {
 		return nil
 	}
@@ -227,10 +469,7 @@ func (c *ScheduleCache) GetCoursesByCRNs(term string, crns []string) []*Course {
 
 // GetCoursesBySubject returns all courses for a subject in a term.
 func (c *ScheduleCache) GetCoursesBySubject(term, subject string) []*Course {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	termData, ok := c.terms[term]
+	termData, ok := c.get(term)
 	if !ok {
 		return nil
 	}
@@ -239,10 +478,7 @@ func (c *ScheduleCache) GetCoursesBySubject(term, subject string) []*Course {
 
 // GetCoursesByCourseCode returns all sections for a course code (e.g., "CSCI:241").
 func (c *ScheduleCache) GetCoursesByCourseCode(term, courseCode string) []*Course {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	termData, ok := c.terms[term]
+	termData, ok := c.get(term)
 	if !ok {
 		return nil
 	}
@@ -251,10 +487,7 @@ func (c *ScheduleCache) GetCoursesByCourseCode(term, courseCode string) []*Cours
 
 // GetAllCourses returns all courses for a term.
 func (c *ScheduleCache) GetAllCourses(term string) []*Course {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	termData, ok := c.terms[term]
+	termData, ok := c.get(term)
 	if !ok {
 		return nil
 	}
@@ -274,6 +507,19 @@ func (c *ScheduleCache) IsTermLoaded(term string) bool {
 	return ok
 }
 
+// get returns the published TermData for a term and records the access for LRU eviction.
+// TermData is immutable, so callers may read it after the lock is released.
+func (c *ScheduleCache) get(term string) (*TermData, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	termData, ok := c.terms[term]
+	if ok {
+		c.touch(term)
+	}
+	return termData, ok
+}
+
 // GetActiveTerms returns the list of currently loaded terms.
 func (c *ScheduleCache) GetActiveTerms() []string {
 	c.mu.RLock()
@@ -283,18 +529,37 @@ func (c *ScheduleCache) GetActiveTerms() []string {
 	return result
 }
 
+// Status returns load time, version, and size for each loaded term, in load order.
+func (c *ScheduleCache) Status() []TermStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]TermStatus, 0, len(c.activeTerms))
+	for _, term := range c.activeTerms {
+		termData, ok := c.terms[term]
+		if !ok {
+			continue
+		}
+		result = append(result, TermStatus{
+			Term:             term,
+			LoadedAt:         termData.LoadedAt,
+			SeatsRefreshedAt: termData.SeatsRefreshedAt,
+			Version:          termData.Version,
+			Courses:          len(termData.Courses),
+			Bytes:            termData.Bytes,
+			LastAccess:       c.lastAccessLocked(term),
+			Pinned:           c.policy.isPinned(term),
+		})
+	}
+	return result
+}
+
 // UnloadTerm removes a term from the cache to free memory.
 func (c *ScheduleCache) UnloadTerm(term string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	delete(c.terms, term)
-	for i, t := range c.activeTerms {
-		if t == term {
-			c.activeTerms = append(c.activeTerms[:i], c.activeTerms[i+1:]...)
-			break
-		}
-	}
+	c.removeLocked(term)
 	slog.Info("Unloaded term from schedule cache", "term", term)
 }
 
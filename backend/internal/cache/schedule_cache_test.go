@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"sync"
 	"testing"
+	"time"
 
 	"schedule-optimizer/internal/testutil"
 )
@@ -431,6 +432,194 @@ func TestLoadTermIfNeeded_Concurrent(t *testing.T) {
 	}
 }
 
+func TestReloadTerm(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	testutil.SeedTestData(t, db)
+
+	cache := NewScheduleCache(queries, nil)
+	ctx := context.Background()
+	if err := cache.LoadTerm(ctx, "202520"); err != nil {
+		t.Fatalf("LoadTerm failed: %v", err)
+	}
+
+	before := cache.Status()
+	if len(before) != 1 || before[0].Term != "202520" || before[0].Courses != 3 {
+		t.Fatalf("unexpected status before reload: %+v", before)
+	}
+	stale, _ := cache.GetCourse("202520", "20002")
+
+	if _, err := db.Exec(`UPDATE sections SET seats_available = 3, is_open = 1 WHERE crn = '20002'`); err != nil {
+		t.Fatalf("update section: %v", err)
+	}
+
+	cache.ReloadTerm("202520")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for cache.Status()[0].Version == before[0].Version {
+		if time.Now().After(deadline) {
+			t.Fatal("term was not reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	course, _ := cache.GetCourse("202520", "20002")
+	if course.SeatsAvailable != 3 || !course.IsOpen {
+		t.Errorf("reloaded course = %d seats, open %v; want 3 seats, open", course.SeatsAvailable, course.IsOpen)
+	}
+	// Readers holding the previous snapshot are unaffected by the swap
+	if stale.SeatsAvailable != 0 || stale.IsOpen {
+		t.Error("previous snapshot was mutated by reload")
+	}
+}
+
+func TestReloadTerm_QueuedDuringRebuild(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	testutil.SeedTestData(t, db)
+
+	cache := NewScheduleCache(queries, nil)
+	if err := cache.LoadTerm(context.Background(), "202520"); err != nil {
+		t.Fatalf("LoadTerm failed: %v", err)
+	}
+	version := cache.Status()[0].Version
+
+	// A reload requested while one is running is queued rather than merged into it
+	cache.reloads["202520"] = false
+	cache.ReloadTerm("202520")
+	if !cache.reloads["202520"] {
+		t.Fatal("reload during a running rebuild was not queued")
+	}
+
+	cache.reloadLoop("202520")
+	if got := cache.Status()[0].Version; got != version+2 {
+		t.Errorf("version = %d, want %d after the running and queued rebuilds", got, version+2)
+	}
+	if _, running := cache.reloads["202520"]; running {
+		t.Error("reload still marked running after the loop finished")
+	}
+}
+
+func TestReloadTerm_SkipsUnloaded(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	testutil.SeedTestData(t, db)
+
+	cache := NewScheduleCache(queries, nil)
+	cache.TermScraped("202520")
+
+	time.Sleep(50 * time.Millisecond)
+	if cache.IsTermLoaded("202520") {
+		t.Error("scrape notification should not load an unloaded term")
+	}
+}
+
+func TestRefreshSeats(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	testutil.SeedTestData(t, db)
+
+	cache := NewScheduleCache(queries, nil)
+	ctx := context.Background()
+	if err := cache.LoadTerm(ctx, "202520"); err != nil {
+		t.Fatalf("LoadTerm failed: %v", err)
+	}
+
+	t.Run("no changes patches nothing", func(t *testing.T) {
+		version := cache.Status()[0].Version
+		patched, err := cache.RefreshSeats(ctx, "202520")
+		if err != nil {
+			t.Fatalf("RefreshSeats failed: %v", err)
+		}
+		if patched != 0 {
+			t.Errorf("patched = %d, want 0", patched)
+		}
+		if cache.Status()[0].Version != version {
+			t.Error("version should not change when nothing was patched")
+		}
+	})
+
+	t.Run("patches changed sections only", func(t *testing.T) {
+		stale, _ := cache.GetCourse("202520", "20002")
+		unchanged, _ := cache.GetCourse("202520", "20001")
+
+		_, err := db.Exec(`
+			UPDATE sections SET enrollment = 28, seats_available = 2, is_open = 1, updated_at = CURRENT_TIMESTAMP
+			WHERE crn = '20002';
+			UPDATE sections SET updated_at = CURRENT_TIMESTAMP WHERE crn = '20001';
+		`)
+		if err != nil {
+			t.Fatalf("update sections: %v", err)
+		}
+
+		patched, err := cache.RefreshSeats(ctx, "202520")
+		if err != nil {
+			t.Fatalf("RefreshSeats failed: %v", err)
+		}
+		if patched != 1 {
+			t.Errorf("patched = %d, want 1", patched)
+		}
+
+		course, _ := cache.GetCourse("202520", "20002")
+		if course.Enrollment != 28 || course.SeatsAvailable != 2 || !course.IsOpen {
+			t.Errorf("course not patched: %+v", course)
+		}
+		if len(course.MeetingTimes) != len(stale.MeetingTimes) || course.Instructor != stale.Instructor {
+			t.Error("non-seat fields should carry over")
+		}
+		if stale.SeatsAvailable != 0 || stale.IsOpen {
+			t.Error("previous snapshot was mutated")
+		}
+
+		// Secondary indexes see the patched course
+		for _, c := range cache.GetCoursesByCourseCode("202520", "CSCI:301") {
+			if c.SeatsAvailable != 2 {
+				t.Errorf("ByCourseCode not patched, seats = %d", c.SeatsAvailable)
+			}
+		}
+		for _, c := range cache.GetCoursesBySubject("202520", "CSCI") {
+			if c.CRN == "20002" && c.SeatsAvailable != 2 {
+				t.Errorf("BySubject not patched, seats = %d", c.SeatsAvailable)
+			}
+		}
+
+		if c, _ := cache.GetCourse("202520", "20001"); c != unchanged {
+			t.Error("unchanged course should keep its pointer")
+		}
+	})
+
+	t.Run("removed section reloads the term", func(t *testing.T) {
+		version := cache.Status()[0].Version
+		_, err := db.Exec(`UPDATE sections SET removed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE crn = '20003'`)
+		if err != nil {
+			t.Fatalf("update section: %v", err)
+		}
+		if _, err := cache.RefreshSeats(ctx, "202520"); err != nil {
+			t.Fatalf("RefreshSeats failed: %v", err)
+		}
+
+		deadline := time.Now().Add(5 * time.Second)
+		for cache.Status()[0].Version == version {
+			if time.Now().After(deadline) {
+				t.Fatal("term was not reloaded")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if _, ok := cache.GetCourse("202520", "20003"); ok {
+			t.Error("removed section should leave the cache")
+		}
+		if len(cache.GetCoursesBySubject("202520", "MATH")) != 0 {
+			t.Error("removed section should leave the subject index")
+		}
+	})
+
+	t.Run("unloaded term is a no-op", func(t *testing.T) {
+		patched, err := cache.RefreshSeats(ctx, "202510")
+		if err != nil || patched != 0 {
+			t.Errorf("RefreshSeats = (%d, %v), want (0, nil)", patched, err)
+		}
+		if cache.IsTermLoaded("202510") {
+			t.Error("refresh should not load a term")
+		}
+	})
+}
+
 // Benchmarks
 
 func BenchmarkLoadTerm(b *testing.B) {
@@ -0,0 +1,172 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"schedule-optimizer/internal/store"
+)
+
+// RefreshSeats patches Enrollment, MaxEnrollment, SeatsAvailable, WaitCount, and IsOpen
+// for sections updated since the term's last load or refresh, without reparsing
+// meeting times. Changed courses are copied rather than mutated and the new TermData
+// is swapped in under a brief write lock, so readers holding the previous snapshot
+// are never blocked or see a partial update.
+//
+// The patch keeps the base's LoadedAt and scrape time: meeting times, instructors,
+// and titles are still as of the last full build, so its snapshot must not pass
+// for one taken after the latest scrape.
+//
+// If a changed CRN isn't in the cache (a section was added or restored) or a
+// cached one was marked removed, a full reload is started instead. Returns the number of sections patched.
+func (c *ScheduleCache) RefreshSeats(ctx context.Context, term string) (int, error) {
+	v, err, _ := c.reloadGroup.Do("seats:"+term, func() (any, error) {
+		return c.refreshSeats(ctx, term)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return v.(int), nil
+}
+
+func (c *ScheduleCache) refreshSeats(ctx context.Context, term string) (int, error) {
+	start := time.Now()
+
+	c.mu.RLock()
+	base, ok := c.terms[term]
+	c.mu.RUnlock()
+	if !ok {
+		return 0, nil
+	}
+
+	rows, err := c.queries.GetSectionSeatsUpdatedSince(ctx, store.GetSectionSeatsUpdatedSinceParams{
+		Term:  term,
+		Since: base.seatsSince,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	seatsSince := base.seatsSince
+	changed := make(map[string]*Course)
+	for _, row := range rows {
+		if row.UpdatedAt.Valid && row.UpdatedAt.Time.After(seatsSince) {
+			seatsSince = row.UpdatedAt.Time
+		}
+
+		old, ok := base.Courses[row.Crn]
+		if row.RemovedAt.Valid {
+			// Removed sections are left out of the load, so dropping a
+			// cached one takes a reload; an uncached one is already gone
+			if ok {
+				slog.Info("Removed CRN in seat refresh, reloading term", "term", term, "crn", row.Crn)
+				c.ReloadTerm(term)
+				return 0, nil
+			}
+			continue
+		}
+		if !ok {
+			slog.Info("Unknown CRN in seat refresh, reloading term", "term", term, "crn", row.Crn)
+			c.ReloadTerm(term)
+			return 0, nil
+		}
+
+		enrollment := int(nullInt(row.Enrollment))
+		maxEnrollment := int(nullInt(row.MaxEnrollment))
+		seats := int(nullInt(row.SeatsAvailable))
+		waitCount := int(nullInt(row.WaitCount))
+		isOpen := nullInt(row.IsOpen) == 1
+		if old.Enrollment == enrollment && old.MaxEnrollment == maxEnrollment &&
+			old.SeatsAvailable == seats && old.WaitCount == waitCount && old.IsOpen == isOpen {
+			continue
+		}
+
+		patched := *old
+		patched.Enrollment = enrollment
+		patched.MaxEnrollment = maxEnrollment
+		patched.SeatsAvailable = seats
+		patched.WaitCount = waitCount
+		patched.IsOpen = isOpen
+		changed[row.Crn] = &patched
+	}
+
+	next := base.withCourses(changed)
+	next.SeatsRefreshedAt = time.Now()
+	next.seatsSince = seatsSince
+	if len(changed) > 0 {
+		next.Version = c.version.Add(1)
+	}
+
+	c.mu.Lock()
+	// A full reload may have landed while we were reading; it is at least as fresh
+	swapped := c.terms[term] == base
+	if swapped {
+		c.terms[term] = next
+	}
+	c.mu.Unlock()
+
+	if !swapped {
+		return 0, nil
+	}
+	c.metrics.seatRefreshes.Add(1)
+	c.writeSnapshot(next)
+
+	slog.Info("Refreshed seat counts in schedule cache",
+		"term", term,
+		"checked", len(rows),
+		"patched", len(changed),
+		"duration", time.Since(start),
+	)
+	return len(changed), nil
+}
+
+// withCourses returns a shallow copy of t with the given courses replaced by CRN.
+// Index slices are only copied for subjects and course codes that contain a change.
+func (t *TermData) withCourses(changed map[string]*Course) *TermData {
+	next := *t
+	if len(changed) == 0 {
+		return &next
+	}
+
+	next.Courses = make(map[string]*Course, len(t.Courses))
+	for crn, course := range t.Courses {
+		if patched, ok := changed[crn]; ok {
+			course = patched
+		}
+		next.Courses[crn] = course
+	}
+
+	next.BySubject = replaceInIndex(t.BySubject, changed, func(c *Course) string {
+		return c.Subject
+	})
+	next.ByCourseCode = replaceInIndex(t.ByCourseCode, changed, func(c *Course) string {
+		return c.Subject + ":" + c.CourseNumber
+	})
+	return &next
+}
+
+// replaceInIndex copies index, rebuilding only the slices that hold a changed course.
+func replaceInIndex(index map[string][]*Course, changed map[string]*Course, key func(*Course) string) map[string][]*Course {
+	dirty := make(map[string]bool)
+	for _, c := range changed {
+		dirty[key(c)] = true
+	}
+
+	result := make(map[string][]*Course, len(index))
+	for k, courses := range index {
+		if !dirty[k] {
+			result[k] = courses
+			continue
+		}
+		patched := make([]*Course, len(courses))
+		for i, c := range courses {
+			if p, ok := changed[c.CRN]; ok {
+				c = p
+			}
+			patched[i] = c
+		}
+		result[k] = patched
+	}
+	return result
+}
@@ -0,0 +1,263 @@
+package cache
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// snapshotMagic prefixes every snapshot file. The trailing byte is the format
+// version; bump it whenever Course or snapshot changes shape so stale files
+// are ignored rather than misread.
+var snapshotMagic = [8]byte{'S', 'O', 'C', 'A', 'C', 'H', 'E', 2}
+
+const snapshotExt = ".snap"
+
+var (
+	errSnapshotCorrupt = errors.New("snapshot corrupt")
+	errSnapshotStale   = errors.New("snapshot out of date")
+)
+
+// snapshot is the gob-encoded body of a snapshot file.
+type snapshot struct {
+	Term       string
+	ScrapedAt  time.Time // Term's last_scraped_at when the data was read
+	LoadedAt   time.Time // Last full rebuild, so a restart doesn't postpone the next one
+	SeatsSince time.Time
+	Masked     bool // Course.Mask values were computed
+	Courses    []*Course
+}
+
+// snapshotWriter serializes a term's snapshot writes.
+type snapshotWriter struct {
+	mu      sync.Mutex
+	version uint64 // TermData.Version last written
+}
+
+// SetSnapshotDir enables per-term snapshots in dir. Each load, reload, and
+// seat refresh rewrites the term's snapshot; a cold LoadTerm reads it back
+// instead of querying SQLite when the term hasn't been scraped since.
+func (c *ScheduleCache) SetSnapshotDir(dir string) error {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("create snapshot dir: %w", err)
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.snapshotDir = dir
+	return nil
+}
+
+// WarmFromSnapshots loads every term with a snapshot on disk, skipping
+// unpinned terms when the policy pins any. Terms whose snapshot is stale are
+// loaded from the database instead. Returns the number of terms loaded.
+func (c *ScheduleCache) WarmFromSnapshots(ctx context.Context) int {
+	c.mu.RLock()
+	dir, policy := c.snapshotDir, c.policy
+	c.mu.RUnlock()
+	if dir == "" {
+		return 0
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		slog.Warn("Failed to read snapshot dir", "dir", dir, "error", err)
+		return 0
+	}
+
+	loaded := 0
+	for _, e := range entries {
+		term, ok := strings.CutSuffix(e.Name(), snapshotExt)
+		if !ok || e.IsDir() {
+			continue
+		}
+		if policy.IsPinned != nil && !policy.IsPinned(term) {
+			continue
+		}
+		if err := c.LoadTermIfNeeded(ctx, term); err != nil {
+			slog.Warn("Failed to warm term", "term", term, "error", err)
+			continue
+		}
+		loaded++
+	}
+	return loaded
+}
+
+// loadSnapshot returns the term from its snapshot, or nil if there's no valid
+// snapshot matching the term's current last_scraped_at.
+func (c *ScheduleCache) loadSnapshot(ctx context.Context, term string) *TermData {
+	c.mu.RLock()
+	dir, maskFunc := c.snapshotDir, c.maskFunc
+	c.mu.RUnlock()
+	if dir == "" {
+		return nil
+	}
+
+	path := filepath.Join(dir, term+snapshotExt)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		slog.Warn("Failed to read snapshot", "term", term, "error", err)
+		return nil
+	}
+
+	scrapedAt, err := c.termScrapedAt(ctx, term)
+	if err != nil {
+		slog.Warn("Failed to validate snapshot", "term", term, "error", err)
+		return nil
+	}
+
+	snap, err := decodeSnapshot(data, term, scrapedAt)
+	if err != nil {
+		slog.Info("Ignoring snapshot", "term", term, "reason", err)
+		if errors.Is(err, errSnapshotCorrupt) {
+			os.Remove(path)
+		}
+		return nil
+	}
+
+	if maskFunc != nil && !snap.Masked {
+		for _, course := range snap.Courses {
+			course.Mask = maskFunc(course.MeetingTimes)
+		}
+	}
+
+	termData := c.newTermData(term, snap.Courses, snap.SeatsSince, scrapedAt)
+	if !snap.LoadedAt.IsZero() {
+		termData.LoadedAt = snap.LoadedAt
+	}
+	return termData
+}
+
+// writeSnapshot persists a term. Failures are logged, never returned: the
+// snapshot only speeds up cold starts. Writes for a term are serialized, and
+// a TermData older than the last one written is skipped, since a reload and a
+// seat refresh can finish out of order.
+func (c *ScheduleCache) writeSnapshot(t *TermData) {
+	c.mu.RLock()
+	dir, masked := c.snapshotDir, c.maskFunc != nil
+	c.mu.RUnlock()
+	if dir == "" || !t.scrapedAt.Valid {
+		return
+	}
+
+	w := c.snapshotWriter(t.Term)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if t.Version <= w.version {
+		return
+	}
+
+	data, err := encodeSnapshot(t, masked)
+	if err != nil {
+		slog.Warn("Failed to encode snapshot", "term", t.Term, "error", err)
+		return
+	}
+
+	// Write to a temp file and rename so a crash never leaves a partial snapshot
+	tmp, err := os.CreateTemp(dir, t.Term+".*.tmp")
+	if err != nil {
+		slog.Warn("Failed to write snapshot", "term", t.Term, "error", err)
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		slog.Warn("Failed to write snapshot", "term", t.Term, "error", err)
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		slog.Warn("Failed to write snapshot", "term", t.Term, "error", err)
+		return
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, t.Term+snapshotExt)); err != nil {
+		slog.Warn("Failed to write snapshot", "term", t.Term, "error", err)
+		return
+	}
+	w.version = t.Version
+}
+
+// snapshotWriter returns the term's writer, creating it on first use.
+func (c *ScheduleCache) snapshotWriter(term string) *snapshotWriter {
+	c.snapshotMu.Lock()
+	defer c.snapshotMu.Unlock()
+	w, ok := c.snapshotWriters[term]
+	if !ok {
+		w = &snapshotWriter{}
+		c.snapshotWriters[term] = w
+	}
+	return w
+}
+
+// encodeSnapshot lays out a snapshot as magic, CRC-32 of the body, then the gob body.
+func encodeSnapshot(t *TermData, masked bool) ([]byte, error) {
+	courses := make([]*Course, 0, len(t.Courses))
+	for _, course := range t.Courses {
+		courses = append(courses, course)
+	}
+	// Section ID order, as buildTerm reads them, so indexes rebuilt from a
+	// snapshot list courses in the same order as a database load
+	slices.SortFunc(courses, func(a, b *Course) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	var body bytes.Buffer
+	err := gob.NewEncoder(&body).Encode(snapshot{
+		Term:       t.Term,
+		ScrapedAt:  t.scrapedAt.Time,
+		LoadedAt:   t.LoadedAt,
+		SeatsSince: t.seatsSince,
+		Masked:     masked,
+		Courses:    courses,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(snapshotMagic)+4+body.Len())
+	out = append(out, snapshotMagic[:]...)
+	out = binary.BigEndian.AppendUint32(out, crc32.ChecksumIEEE(body.Bytes()))
+	return append(out, body.Bytes()...), nil
+}
+
+// decodeSnapshot verifies and decodes a snapshot for term, rejecting it unless
+// it was taken at the given scrape time.
+func decodeSnapshot(data []byte, term string, scrapedAt sql.NullTime) (*snapshot, error) {
+	header := len(snapshotMagic) + 4
+	if len(data) < header || !bytes.Equal(data[:len(snapshotMagic)], snapshotMagic[:]) {
+		return nil, fmt.Errorf("%w: bad header", errSnapshotCorrupt)
+	}
+	body := data[header:]
+	if binary.BigEndian.Uint32(data[len(snapshotMagic):header]) != crc32.ChecksumIEEE(body) {
+		return nil, fmt.Errorf("%w: checksum mismatch", errSnapshotCorrupt)
+	}
+
+	var snap snapshot
+	if err := gob.NewDecoder(bytes.NewReader(body)).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("%w: %v", errSnapshotCorrupt, err)
+	}
+	if snap.Term != term {
+		return nil, fmt.Errorf("%w: snapshot is for term %s", errSnapshotCorrupt, snap.Term)
+	}
+	if !scrapedAt.Valid || !snap.ScrapedAt.Equal(scrapedAt.Time) {
+		return nil, errSnapshotStale
+	}
+	return &snap, nil
+}
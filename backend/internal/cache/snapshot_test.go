@@ -0,0 +1,238 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"schedule-optimizer/internal/testutil"
+)
+
+// setupSnapshotCache seeds a scraped term and returns a cache writing snapshots to a temp dir.
+func setupSnapshotCache(t *testing.T) (*sql.DB, *ScheduleCache, string) {
+	t.Helper()
+	db, queries := testutil.SetupTestDB(t)
+	testutil.SeedTestData(t, db)
+	if _, err := db.Exec(`UPDATE terms SET last_scraped_at = '2025-03-01 08:00:00' WHERE code = '202520'`); err != nil {
+		t.Fatalf("set last_scraped_at: %v", err)
+	}
+
+	dir := t.TempDir()
+	cache := NewScheduleCache(queries, nil)
+	if err := cache.SetSnapshotDir(dir); err != nil {
+		t.Fatalf("SetSnapshotDir: %v", err)
+	}
+	return db, cache, dir
+}
+
+func testMask(meetings []MeetingTime) [8]uint64 {
+	var m [8]uint64
+	for _, mt := range meetings {
+		for d, on := range mt.Days {
+			if on {
+				m[d] = 1
+			}
+		}
+	}
+	return m
+}
+
+func TestSnapshot_RoundTrip(t *testing.T) {
+	db, cache, dir := setupSnapshotCache(t)
+	cache.SetMaskFunc(testMask)
+	ctx := context.Background()
+
+	if err := cache.LoadTerm(ctx, "202520"); err != nil {
+		t.Fatalf("LoadTerm failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "202520.snap")); err != nil {
+		t.Fatalf("snapshot not written: %v", err)
+	}
+
+	// Remove sections from the DB; a snapshot-backed load must not need them
+	if _, err := db.Exec(`DELETE FROM meeting_times; DELETE FROM instructors; DELETE FROM sections WHERE term = '202520'`); err != nil {
+		t.Fatalf("delete sections: %v", err)
+	}
+
+	restored := NewScheduleCache(cache.queries, nil)
+	restored.SetSnapshotDir(dir)
+	if n := restored.WarmFromSnapshots(ctx); n != 1 {
+		t.Fatalf("WarmFromSnapshots = %d, want 1", n)
+	}
+
+	course, ok := restored.GetCourse("202520", "20001")
+	if !ok {
+		t.Fatal("course 20001 not restored from snapshot")
+	}
+	if course.Instructor != "Dr. Smith" || len(course.MeetingTimes) != 1 {
+		t.Errorf("restored course incomplete: %+v", course)
+	}
+	if course.Mask == ([8]uint64{}) {
+		t.Error("precomputed mask should be persisted")
+	}
+	if len(restored.GetCoursesByCourseCode("202520", "CSCI:247")) != 1 {
+		t.Error("indexes not rebuilt from snapshot")
+	}
+}
+
+func TestSnapshot_StaleFallsBackToDB(t *testing.T) {
+	db, cache, dir := setupSnapshotCache(t)
+	ctx := context.Background()
+
+	if err := cache.LoadTerm(ctx, "202520"); err != nil {
+		t.Fatalf("LoadTerm failed: %v", err)
+	}
+
+	// A newer scrape invalidates the snapshot
+	_, err := db.Exec(`
+		UPDATE terms SET last_scraped_at = '2025-03-02 08:00:00' WHERE code = '202520';
+		UPDATE sections SET seats_available = 9 WHERE crn = '20002';
+	`)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	fresh := NewScheduleCache(cache.queries, nil)
+	fresh.SetSnapshotDir(dir)
+	if err := fresh.LoadTerm(ctx, "202520"); err != nil {
+		t.Fatalf("LoadTerm failed: %v", err)
+	}
+
+	course, _ := fresh.GetCourse("202520", "20002")
+	if course.SeatsAvailable != 9 {
+		t.Errorf("SeatsAvailable = %d, want 9 from the database", course.SeatsAvailable)
+	}
+}
+
+func TestSnapshot_CorruptFallsBackToDB(t *testing.T) {
+	_, cache, dir := setupSnapshotCache(t)
+	ctx := context.Background()
+
+	path := filepath.Join(dir, "202520.snap")
+	if err := os.WriteFile(path, []byte("SOCACHE\x01garbage"), 0o644); err != nil {
+		t.Fatalf("write corrupt snapshot: %v", err)
+	}
+
+	if err := cache.LoadTerm(ctx, "202520"); err != nil {
+		t.Fatalf("LoadTerm failed: %v", err)
+	}
+	if len(cache.GetAllCourses("202520")) != 3 {
+		t.Error("expected term loaded from database")
+	}
+
+	// The corrupt file is replaced by a valid snapshot of the DB load
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read snapshot: %v", err)
+	}
+	if _, err := decodeSnapshot(data, "202520", cache.terms["202520"].scrapedAt); err != nil {
+		t.Errorf("snapshot not rewritten: %v", err)
+	}
+}
+
+func TestSnapshot_SkippedForUnscrapedTerm(t *testing.T) {
+	_, cache, dir := setupSnapshotCache(t)
+
+	if err := cache.LoadTerm(context.Background(), "202510"); err != nil {
+		t.Fatalf("LoadTerm failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "202510.snap")); !os.IsNotExist(err) {
+		t.Error("terms never scraped should not be snapshotted")
+	}
+}
+
+func TestSnapshot_OrderAndStaleWrites(t *testing.T) {
+	_, cache, dir := setupSnapshotCache(t)
+	ctx := context.Background()
+
+	if err := cache.LoadTerm(ctx, "202520"); err != nil {
+		t.Fatalf("LoadTerm failed: %v", err)
+	}
+	path := filepath.Join(dir, "202520.snap")
+	readSnapshot := func() *snapshot {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read snapshot: %v", err)
+		}
+		snap, err := decodeSnapshot(data, "202520", cache.terms["202520"].scrapedAt)
+		if err != nil {
+			t.Fatalf("decode snapshot: %v", err)
+		}
+		return snap
+	}
+
+	// Courses are stored in section ID order, as the database returns them
+	snap := readSnapshot()
+	for i := 1; i < len(snap.Courses); i++ {
+		if snap.Courses[i-1].ID >= snap.Courses[i].ID {
+			t.Fatalf("snapshot courses out of ID order at %d", i)
+		}
+	}
+
+	// Data older than the last write doesn't overwrite it
+	older := *cache.terms["202520"]
+	older.Version--
+	older.Courses = map[string]*Course{}
+	cache.writeSnapshot(&older)
+	if n := len(readSnapshot().Courses); n != 3 {
+		t.Errorf("snapshot has %d courses after an older write, want 3", n)
+	}
+}
+
+func TestSnapshot_SeatPatchNotCurrentAfterRestart(t *testing.T) {
+	db, cache, dir := setupSnapshotCache(t)
+	ctx := context.Background()
+
+	if err := cache.LoadTerm(ctx, "202520"); err != nil {
+		t.Fatalf("LoadTerm failed: %v", err)
+	}
+	loadedAt := cache.terms["202520"].LoadedAt
+
+	// A scrape changes a meeting time and seat counts; only the seats are patched
+	_, err := db.Exec(`
+		UPDATE terms SET last_scraped_at = '2025-03-02 08:00:00' WHERE code = '202520';
+		UPDATE meeting_times SET start_time = '1100', end_time = '1150' WHERE section_id = 1;
+		UPDATE sections SET seats_available = 4, updated_at = datetime('now', '+1 second') WHERE crn = '20001';
+	`)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if patched, err := cache.RefreshSeats(ctx, "202520"); err != nil || patched != 1 {
+		t.Fatalf("RefreshSeats = %d, %v; want 1 patched", patched, err)
+	}
+	if got := cache.terms["202520"].LoadedAt; !got.Equal(loadedAt) {
+		t.Errorf("seat patch moved LoadedAt from %v to %v", loadedAt, got)
+	}
+
+	restarted := NewScheduleCache(cache.queries, nil)
+	restarted.SetSnapshotDir(dir)
+	if n := restarted.WarmFromSnapshots(ctx); n != 1 {
+		t.Fatalf("WarmFromSnapshots = %d, want 1", n)
+	}
+	course, _ := restarted.GetCourse("202520", "20001")
+	if course.MeetingTimes[0].StartTime != "1100" || course.SeatsAvailable != 4 {
+		t.Errorf("restarted course = start %s, %d seats; want 1100, 4", course.MeetingTimes[0].StartTime, course.SeatsAvailable)
+	}
+}
+
+func TestSnapshot_KeepsLoadedAt(t *testing.T) {
+	_, cache, dir := setupSnapshotCache(t)
+	ctx := context.Background()
+
+	if err := cache.LoadTerm(ctx, "202520"); err != nil {
+		t.Fatalf("LoadTerm failed: %v", err)
+	}
+	loadedAt := cache.terms["202520"].LoadedAt
+
+	restored := NewScheduleCache(cache.queries, nil)
+	restored.SetSnapshotDir(dir)
+	if n := restored.WarmFromSnapshots(ctx); n != 1 {
+		t.Fatalf("WarmFromSnapshots = %d, want 1", n)
+	}
+	if got := restored.terms["202520"].LoadedAt; !got.Equal(loadedAt) {
+		t.Errorf("LoadedAt = %v, want %v from the snapshot", got, loadedAt)
+	}
+}
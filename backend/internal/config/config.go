@@ -12,11 +12,18 @@ import (
 )
 
 type Config struct {
-	Port               string
-	Environment        string
-	CORSAllowedOrigins []string
-	DatabasePath       string
-	ScraperConcurrency int
+	Port                     string
+	Environment              string
+	CORSAllowedOrigins       []string
+	DatabasePath             string
+	ScraperConcurrency       int
+	ScraperRequestsPerMinute int    // Banner request rate across scrape workers; 0 = unlimited
+	BannerMode               string // live, record (save responses as fixtures), or replay (fixtures only)
+	BannerFixturesDir        string // Where record and replay modes keep Banner responses
+	CatalogSourcePath        string // CSV or JSON catalog export to scrape instead of Banner; Banner when empty
+	ScraperDetails           bool   // Fetch course descriptions, prerequisites, restrictions, fees, and linked sections
+	ScraperDetailsMaxAge     int    // Hours before fetched details are refetched
+	ScraperDetailsBatch      int    // Courses whose details are fetched per hourly detail job run
 
 	// Jobs scheduler config
 	JobsEnabled       bool
@@ -26,6 +33,16 @@ type Config struct {
 	PastTermYears     int // Years of past terms to scrape
 
 	GradeDataPath string // Path to PRR Excel file for grade data
+
+	// Schedule cache limits (0 = unlimited)
+	CacheMaxTerms int // Maximum terms resident in the schedule cache
+	CacheMaxMB    int // Approximate memory budget for the schedule cache
+
+	CacheSnapshotDir string // Directory for per-term cache snapshots; disabled when empty
+
+	AdminToken string // Bearer token for /api/admin; admin routes are disabled when empty
+
+	SearchSynonymsPath string // Synonyms file for search; built-in dictionary when empty
 }
 
 // Load reads environment variables and returns a Config struct.
@@ -39,6 +56,13 @@ func Load() *Config {
 	corsOrigins := parseCORSOrigins(getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:5173"))
 	databasePath := getEnv("DATABASE_PATH", "data/schedule.db")
 	scraperConcurrency := getEnvInt("SCRAPER_CONCURRENCY", 4)
+	scraperRequestsPerMinute := getEnvInt("SCRAPER_REQUESTS_PER_MINUTE", 120)
+	bannerMode := strings.ToLower(getEnv("BANNER_MODE", "live"))
+	bannerFixturesDir := getEnv("BANNER_FIXTURES_DIR", "data/fixtures")
+	catalogSourcePath := getEnv("CATALOG_SOURCE_PATH", "")
+	scraperDetails := getEnvBool("SCRAPER_DETAILS", false)
+	scraperDetailsMaxAge := getEnvInt("SCRAPER_DETAILS_MAX_AGE_HOURS", 168)
+	scraperDetailsBatch := getEnvInt("SCRAPER_DETAILS_BATCH", 200)
 
 	// Jobs scheduler config
 	jobsEnabled := getEnvBool("JOBS_ENABLED", true)
@@ -48,32 +72,62 @@ func Load() *Config {
 	pastTermYears := getEnvInt("JOBS_PAST_TERM_YEARS", 5)
 	gradeDataPath := getEnv("GRADE_DATA_PATH", "data/PRR-S002163-020326.xlsx")
 
+	cacheMaxTerms := getEnvInt("CACHE_MAX_TERMS", 8)
+	cacheMaxMB := getEnvInt("CACHE_MAX_MB", 256)
+	cacheSnapshotDir := getEnv("CACHE_SNAPSHOT_DIR", "data/snapshots")
+	adminToken := getEnv("ADMIN_TOKEN", "")
+	searchSynonymsPath := getEnv("SEARCH_SYNONYMS_PATH", "")
+
 	slog.Info("Configuration loaded",
 		"port", port,
 		"environment", environment,
 		"cors_origins", corsOrigins,
 		"database_path", databasePath,
 		"scraper_concurrency", scraperConcurrency,
+		"scraper_requests_per_minute", scraperRequestsPerMinute,
+		"banner_mode", bannerMode,
+		"banner_fixtures_dir", bannerFixturesDir,
+		"catalog_source_path", catalogSourcePath,
+		"scraper_details", scraperDetails,
+		"scraper_details_max_age_hours", scraperDetailsMaxAge,
+		"scraper_details_batch", scraperDetailsBatch,
 		"jobs_enabled", jobsEnabled,
 		"active_scrape_hours", activeScrapeHours,
 		"daily_scrape_hour", dailyScrapeHour,
 		"log_retention_days", logRetentionDays,
 		"past_term_years", pastTermYears,
 		"grade_data_path", gradeDataPath,
+		"cache_max_terms", cacheMaxTerms,
+		"cache_max_mb", cacheMaxMB,
+		"cache_snapshot_dir", cacheSnapshotDir,
+		"admin_enabled", adminToken != "",
+		"search_synonyms_path", searchSynonymsPath,
 	)
 
 	return &Config{
-		Port:               port,
-		Environment:        environment,
-		CORSAllowedOrigins: corsOrigins,
-		DatabasePath:       databasePath,
-		ScraperConcurrency: scraperConcurrency,
-		JobsEnabled:        jobsEnabled,
-		ActiveScrapeHours:  activeScrapeHours,
-		DailyScrapeHour:    dailyScrapeHour,
-		LogRetentionDays:   logRetentionDays,
-		PastTermYears:      pastTermYears,
-		GradeDataPath:      gradeDataPath,
+		Port:                     port,
+		Environment:              environment,
+		CORSAllowedOrigins:       corsOrigins,
+		DatabasePath:             databasePath,
+		ScraperConcurrency:       scraperConcurrency,
+		ScraperRequestsPerMinute: scraperRequestsPerMinute,
+		BannerMode:               bannerMode,
+		BannerFixturesDir:        bannerFixturesDir,
+		CatalogSourcePath:        catalogSourcePath,
+		ScraperDetails:           scraperDetails,
+		ScraperDetailsMaxAge:     scraperDetailsMaxAge,
+		ScraperDetailsBatch:      scraperDetailsBatch,
+		JobsEnabled:              jobsEnabled,
+		ActiveScrapeHours:        activeScrapeHours,
+		DailyScrapeHour:          dailyScrapeHour,
+		LogRetentionDays:         logRetentionDays,
+		PastTermYears:            pastTermYears,
+		GradeDataPath:            gradeDataPath,
+		CacheMaxTerms:            cacheMaxTerms,
+		CacheMaxMB:               cacheMaxMB,
+		CacheSnapshotDir:         cacheSnapshotDir,
+		AdminToken:               adminToken,
+		SearchSynonymsPath:       searchSynonymsPath,
 	}
 }
 
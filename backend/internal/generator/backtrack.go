@@ -9,8 +9,10 @@ import (
 // backtrackParams configures the backtracking algorithm.
 type backtrackParams struct {
 	groups      []courseGroup
-	numRequired int // First N groups are required (must all be in every schedule)
-	minCourses  int
+	numRequired int            // First N groups are required (must all be in every schedule)
+	pinned      []*sectionData // Pinned sections, prepended to every schedule
+	initialMask TimeMask       // Time already occupied by pinned sections
+	minCourses  int            // Bounds on courses chosen from groups (pinned excluded)
 	maxCourses  int
 	limit       int
 }
@@ -18,12 +20,15 @@ type backtrackParams struct {
 // backtrack finds all valid schedule combinations using recursive backtracking.
 // The first numRequired groups are required (must all be in every schedule).
 // Remaining groups are optional. It explores groups in order, pruning branches
-// that cannot lead to valid schedules.
+// that cannot lead to valid schedules. Pinned sections seed every schedule and
+// their mask is the starting point for conflict checks.
 func backtrack(ctx context.Context, p backtrackParams) []Schedule {
 	initialCap := min(p.limit, 100)
 	results := make([]Schedule, 0, initialCap)
-	current := make([]*sectionData, 0, p.maxCourses)
-	var currentMask TimeMask
+	current := make([]*sectionData, 0, len(p.pinned)+p.maxCourses)
+	current = append(current, p.pinned...)
+	currentMask := p.initialMask
+	base := len(p.pinned)
 
 	var generate func(groupIdx int)
 	generate = func(groupIdx int) {
@@ -53,18 +58,19 @@ func backtrack(ctx context.Context, p backtrackParams) []Schedule {
 
 		// We've filled all required groups, now handle optional groups
 		// Record valid schedule if we have enough courses
-		if len(current) >= p.minCourses {
+		chosen := len(current) - base
+		if chosen >= p.minCourses {
 			results = append(results, buildSchedule(current))
 		}
 
 		// Stop if we've reached max courses or exhausted all groups
-		if len(current) >= p.maxCourses || groupIdx >= len(p.groups) {
+		if chosen >= p.maxCourses || groupIdx >= len(p.groups) {
 			return
 		}
 
 		// Pruning: can we still reach the minimum?
 		remaining := len(p.groups) - groupIdx
-		if len(current)+remaining < p.minCourses {
+		if chosen+remaining < p.minCourses {
 			return
 		}
 
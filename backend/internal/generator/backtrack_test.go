@@ -367,3 +367,71 @@ func TestBacktrack_MultipleRequiredCourses(t *testing.T) {
 		}
 	}
 }
+
+func TestBacktrack_PinnedSections(t *testing.T) {
+	ctx := context.Background()
+
+	// Pinned CSCI 247 on Monday 9-10am
+	pinnedSection := makeTestSection(1, "20001", []cache.MeetingTime{
+		{Days: [7]bool{false, true, false, false, false, false, false}, StartTime: "0900", EndTime: "1000"},
+	})
+	// MATH 204: one section overlaps the pinned one, one doesn't
+	conflicting := makeTestSection(2, "20002", []cache.MeetingTime{
+		{Days: [7]bool{false, true, false, false, false, false, false}, StartTime: "0930", EndTime: "1030"},
+	})
+	free := makeTestSection(3, "20003", []cache.MeetingTime{
+		{Days: [7]bool{false, true, false, false, false, false, false}, StartTime: "1100", EndTime: "1200"},
+	})
+
+	pinned := []*sectionData{{course: pinnedSection, mask: FromMeetingTimes(pinnedSection.MeetingTimes)}}
+	groups := []courseGroup{
+		{courseKey: "MATH:204", sections: []*sectionData{
+			{course: conflicting, mask: FromMeetingTimes(conflicting.MeetingTimes)},
+			{course: free, mask: FromMeetingTimes(free.MeetingTimes)},
+		}},
+	}
+
+	schedules := backtrack(ctx, backtrackParams{
+		groups:      groups,
+		numRequired: 1,
+		pinned:      pinned,
+		initialMask: pinned[0].mask,
+		minCourses:  1,
+		maxCourses:  1,
+		limit:       100,
+	})
+
+	// Only the non-conflicting MATH section fits around the pinned section
+	if len(schedules) != 1 {
+		t.Fatalf("Expected 1 schedule, got %d", len(schedules))
+	}
+	courses := schedules[0].Courses
+	if len(courses) != 2 {
+		t.Fatalf("Expected 2 courses (pinned + chosen), got %d", len(courses))
+	}
+	if courses[0].CRN != "20001" {
+		t.Errorf("Expected pinned section first, got %s", courses[0].CRN)
+	}
+	if courses[1].CRN != "20003" {
+		t.Errorf("Expected non-conflicting section 20003, got %s", courses[1].CRN)
+	}
+}
+
+func TestBacktrack_PinnedOnly(t *testing.T) {
+	ctx := context.Background()
+	section := makeTestSection(1, "20001", []cache.MeetingTime{
+		{Days: [7]bool{false, true, false, false, false, false, false}, StartTime: "0900", EndTime: "0950"},
+	})
+	pinned := []*sectionData{{course: section, mask: FromMeetingTimes(section.MeetingTimes)}}
+
+	schedules := backtrack(ctx, backtrackParams{
+		pinned:      pinned,
+		initialMask: pinned[0].mask,
+		minCourses:  0,
+		maxCourses:  0,
+		limit:       100,
+	})
+	if len(schedules) != 1 || len(schedules[0].Courses) != 1 {
+		t.Errorf("Expected a single schedule containing only the pinned section, got %v", schedules)
+	}
+}
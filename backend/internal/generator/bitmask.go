@@ -117,6 +117,21 @@ func FromMeetingTimes(meetings []cache.MeetingTime) TimeMask {
 	return mask
 }
 
+// MeetingMask adapts FromMeetingTimes for cache.ScheduleCache.SetMaskFunc so
+// masks are computed once per load (and persisted in snapshots) rather than per request.
+func MeetingMask(meetings []cache.MeetingTime) [8]uint64 {
+	return FromMeetingTimes(meetings)
+}
+
+// CourseMask returns the cache's precomputed mask, falling back to computing it.
+// A zero mask is recomputed since it's also what async/TBD sections produce.
+func CourseMask(c *cache.Course) TimeMask {
+	if c.Mask != ([8]uint64{}) {
+		return TimeMask(c.Mask)
+	}
+	return FromMeetingTimes(c.MeetingTimes)
+}
+
 // FromBlockedTimes builds a TimeMask from user-specified blocked times.
 func FromBlockedTimes(blocked []BlockedTime) TimeMask {
 	var mask TimeMask
@@ -186,3 +186,23 @@ func TestFromMeetingTimes_EmptyOrTBD(t *testing.T) {
 		t.Error("TBD meetings should return empty mask")
 	}
 }
+
+func TestCourseMask(t *testing.T) {
+	meetings := []cache.MeetingTime{
+		{Days: [7]bool{false, true, false, true, false, false, false}, StartTime: "1000", EndTime: "1050"},
+	}
+	want := FromMeetingTimes(meetings)
+
+	// Without a precomputed mask, it's derived from meeting times
+	course := &cache.Course{MeetingTimes: meetings}
+	if got := CourseMask(course); got != want {
+		t.Error("CourseMask should compute the mask when none is cached")
+	}
+
+	// A precomputed mask from the cache is used as-is
+	course.Mask = MeetingMask(meetings)
+	course.MeetingTimes = nil
+	if got := CourseMask(course); got != want {
+		t.Error("CourseMask should use the cached mask")
+	}
+}
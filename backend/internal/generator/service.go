@@ -3,6 +3,8 @@ package generator
 import (
 	"cmp"
 	"context"
+	"errors"
+	"fmt"
 	"slices"
 	"time"
 
@@ -10,10 +12,22 @@ import (
 	"schedule-optimizer/internal/store"
 )
 
+var (
+	ErrPinnedNotFound = errors.New("pinned section not found in term")
+	ErrPinnedConflict = errors.New("pinned sections conflict")
+)
+
+// PrerequisiteChecker reports which of a term's courses have prerequisites
+// that completed courses don't meet, keyed like "CSCI 145".
+type PrerequisiteChecker interface {
+	Unmet(ctx context.Context, term string, completed []string) (map[string]bool, error)
+}
+
 // Service handles schedule generation using bitmask-based conflict detection.
 type Service struct {
 	cache   *cache.ScheduleCache
 	queries *store.Queries
+	prereqs PrerequisiteChecker
 }
 
 // NewService creates a new schedule generator service.
@@ -21,15 +35,38 @@ func NewService(c *cache.ScheduleCache, q *store.Queries) *Service {
 	return &Service{cache: c, queries: q}
 }
 
+// SetPrerequisites enables GenerateRequest.CheckPrerequisites. Without a
+// checker, the option is ignored.
+func (s *Service) SetPrerequisites(p PrerequisiteChecker) {
+	s.prereqs = p
+}
+
 // Generate produces all valid schedule combinations for the requested courses.
 func (s *Service) Generate(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
 	start := time.Now()
 
 	blockedMask := FromBlockedTimes(req.BlockedTimes)
 
-	// Separate required vs optional specs
+	pinned, pinnedAsyncs, pinnedResults, err := s.resolvePinned(req.Term, req.PinnedCRNs)
+	if err != nil {
+		return nil, err
+	}
+	var pinnedMask TimeMask
+	pinnedCourses := make(map[string]bool, len(pinned)+len(pinnedAsyncs))
+	for _, p := range pinned {
+		pinnedMask = pinnedMask.Merge(p.mask)
+		pinnedCourses[p.course.Subject+":"+p.course.CourseNumber] = true
+	}
+	for _, c := range pinnedAsyncs {
+		pinnedCourses[c.Subject+":"+c.CourseNumber] = true
+	}
+
+	// Separate required vs optional specs, skipping courses already satisfied by a pinned section
 	var requiredSpecs, optionalSpecs []CourseSpec
 	for _, spec := range req.CourseSpecs {
+		if pinnedCourses[spec.Subject+":"+spec.CourseNumber] {
+			continue
+		}
 		if spec.Required {
 			requiredSpecs = append(requiredSpecs, spec)
 		} else {
@@ -37,17 +74,27 @@ func (s *Service) Generate(ctx context.Context, req GenerateRequest) (*GenerateR
 		}
 	}
 
+	var unmet map[string]bool
+	if req.CheckPrerequisites && s.prereqs != nil {
+		if unmet, err = s.prereqs.Unmet(ctx, req.Term, req.CompletedCourses); err != nil {
+			return nil, err
+		}
+	}
+
 	// Build course groups for all specs
-	requiredGroups, reqAsyncs, reqResults := s.buildCourseGroups(ctx, req.Term, requiredSpecs, blockedMask)
-	optionalGroups, optAsyncs, optResults := s.buildCourseGroups(ctx, req.Term, optionalSpecs, blockedMask)
+	requiredGroups, reqAsyncs, reqResults := s.buildCourseGroups(ctx, req.Term, requiredSpecs, blockedMask, pinnedMask, unmet)
+	optionalGroups, optAsyncs, optResults := s.buildCourseGroups(ctx, req.Term, optionalSpecs, blockedMask, pinnedMask, unmet)
+
+	asyncs := slices.Concat(pinnedAsyncs, reqAsyncs, optAsyncs)
+	courseResults := slices.Concat(pinnedResults, reqResults, optResults)
 
 	// Check if any required course has no valid sections
 	if len(requiredGroups) < len(requiredSpecs) {
 		// At least one required course has no scheduleable sections - no valid schedules
 		return &GenerateResponse{
 			Schedules:     nil,
-			Asyncs:        append(reqAsyncs, optAsyncs...),
-			CourseResults: append(reqResults, optResults...),
+			Asyncs:        asyncs,
+			CourseResults: courseResults,
 			Stats: GenerateStats{
 				TotalGenerated: 0,
 				TimeMs:         float64(time.Since(start).Microseconds()) / 1000,
@@ -68,10 +115,41 @@ func (s *Service) Generate(ctx context.Context, req GenerateRequest) (*GenerateR
 	numRequired := len(requiredGroups)
 	totalCourses := len(allGroups)
 
+	// Min/max count pinned courses, async ones included; convert them to bounds on the courses added around them
+	numPinned := len(pinnedCourses)
+	minReq, maxReq := req.MinCourses, req.MaxCourses
+	userSetMin := minReq > 0
+	if maxReq > 0 && numPinned >= maxReq {
+		// Pinned courses fill the schedule, so the pinned sections are the
+		// only schedule, and only if no other course is required
+		var schedules []Schedule
+		if len(pinned) > 0 && numRequired == 0 {
+			schedule := buildSchedule(pinned)
+			scoreSchedule(&schedule)
+			schedules = append(schedules, schedule)
+		}
+		return &GenerateResponse{
+			Schedules:     schedules,
+			Asyncs:        asyncs,
+			CourseResults: courseResults,
+			Stats: GenerateStats{
+				TotalGenerated: len(schedules),
+				TimeMs:         float64(time.Since(start).Microseconds()) / 1000,
+			},
+		}, nil
+	}
+	if numPinned > 0 {
+		if minReq > 0 {
+			minReq = max(minReq-numPinned, 0)
+		}
+		if maxReq > 0 {
+			maxReq -= numPinned
+		}
+	}
+
 	// Default minCourses to totalCourses if not specified (0), but at least numRequired
-	effectiveMin := req.MinCourses
-	userSetMin := effectiveMin > 0
-	if effectiveMin == 0 {
+	effectiveMin := minReq
+	if !userSetMin {
 		effectiveMin = totalCourses
 	}
 	effectiveMin = max(effectiveMin, numRequired)
@@ -82,11 +160,17 @@ func (s *Service) Generate(ctx context.Context, req GenerateRequest) (*GenerateR
 		fallbackMin = effectiveMin - 1
 	}
 
-	minCourses, maxCourses := clampBounds(fallbackMin, req.MaxCourses, totalCourses)
+	minCourses, maxCourses := clampBounds(fallbackMin, maxReq, totalCourses)
+	if userSetMin && effectiveMin == 0 && len(pinned) > 0 {
+		// Pinned courses already meet the minimum, so they can stand alone
+		minCourses = 0
+	}
 
 	schedules := backtrack(ctx, backtrackParams{
 		groups:      allGroups,
 		numRequired: numRequired,
+		pinned:      pinned,
+		initialMask: pinnedMask,
 		minCourses:  minCourses,
 		maxCourses:  maxCourses,
 		limit:       MaxSchedulesToGenerate,
@@ -104,13 +188,13 @@ func (s *Service) Generate(ctx context.Context, req GenerateRequest) (*GenerateR
 	if !userSetMin && fallbackMin < effectiveMin && len(schedules) > 0 {
 		fullCount := 0
 		for _, s := range schedules {
-			if len(s.Courses) >= effectiveMin {
+			if len(s.Courses)-len(pinned) >= effectiveMin {
 				fullCount++
 			}
 		}
 		if fullCount > 0 {
 			schedules = slices.DeleteFunc(schedules, func(s Schedule) bool {
-				return len(s.Courses) < effectiveMin
+				return len(s.Courses)-len(pinned) < effectiveMin
 			})
 		}
 	}
@@ -124,8 +208,8 @@ func (s *Service) Generate(ctx context.Context, req GenerateRequest) (*GenerateR
 
 	return &GenerateResponse{
 		Schedules:     schedules,
-		Asyncs:        append(reqAsyncs, optAsyncs...),
-		CourseResults: append(reqResults, optResults...),
+		Asyncs:        asyncs,
+		CourseResults: courseResults,
 		Stats: GenerateStats{
 			TotalGenerated: totalGenerated,
 			TimeMs:         float64(time.Since(start).Microseconds()) / 1000,
@@ -133,8 +217,54 @@ func (s *Service) Generate(ctx context.Context, req GenerateRequest) (*GenerateR
 	}, nil
 }
 
-// buildCourseGroups fetches sections from cache, filters by blocked times and allowed CRNs, and groups by course.
-func (s *Service) buildCourseGroups(ctx context.Context, term string, specs []CourseSpec, blockedMask TimeMask) ([]courseGroup, []*cache.Course, []CourseResult) {
+// resolvePinned looks up pinned CRNs and validates that their meeting times don't overlap.
+// Pinned sections take precedence over blocked times since the user is already registered.
+// Returns timed sections with precomputed masks, async/TBD sections, and a result per pinned section.
+func (s *Service) resolvePinned(term string, crns []string) ([]*sectionData, []*cache.Course, []CourseResult, error) {
+	var pinned []*sectionData
+	var asyncs []*cache.Course
+	var results []CourseResult
+	seen := make(map[string]bool, len(crns))
+
+	for _, crn := range crns {
+		if seen[crn] {
+			continue
+		}
+		seen[crn] = true
+
+		course, ok := s.cache.GetCourse(term, crn)
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("%w: %s", ErrPinnedNotFound, crn)
+		}
+		results = append(results, CourseResult{
+			Name:   course.Subject + " " + course.CourseNumber,
+			Status: StatusPinned,
+			Count:  1,
+			CRN:    crn,
+		})
+
+		if isAsyncOrTBD(course) {
+			asyncs = append(asyncs, course)
+			continue
+		}
+
+		mask := CourseMask(course)
+		for _, other := range pinned {
+			if other.mask.Conflicts(mask) {
+				return nil, nil, nil, fmt.Errorf("%w: %s (%s) overlaps %s (%s)", ErrPinnedConflict,
+					course.Subject+" "+course.CourseNumber, crn,
+					other.course.Subject+" "+other.course.CourseNumber, other.course.CRN)
+			}
+		}
+		pinned = append(pinned, &sectionData{course: course, mask: mask})
+	}
+
+	return pinned, asyncs, results, nil
+}
+
+// buildCourseGroups fetches sections from cache, filters by blocked times, pinned sections and allowed CRNs, and groups by course.
+// Courses in unmet, keyed by display name, are dropped for unmet prerequisites.
+func (s *Service) buildCourseGroups(ctx context.Context, term string, specs []CourseSpec, blockedMask, pinnedMask TimeMask, unmet map[string]bool) ([]courseGroup, []*cache.Course, []CourseResult) {
 	var groups []courseGroup
 	var asyncs []*cache.Course
 	var results []CourseResult
@@ -156,6 +286,10 @@ func (s *Service) buildCourseGroups(ctx context.Context, term string, specs []Co
 			}
 			continue
 		}
+		if unmet[displayName] {
+			results = append(results, CourseResult{Name: displayName, Status: StatusPrereqsUnmet})
+			continue
+		}
 
 		// Build allowed CRN set if specified
 		var allowedCRNs map[string]bool
@@ -168,7 +302,7 @@ func (s *Service) buildCourseGroups(ctx context.Context, term string, specs []Co
 
 		var group courseGroup
 		group.courseKey = courseKey
-		var asyncCount, blockedCount, filteredCount int
+		var asyncCount, blockedCount, pinnedConflictCount, filteredCount int
 
 		for _, sec := range sections {
 			// Filter by allowed CRNs if specified
@@ -183,12 +317,16 @@ func (s *Service) buildCourseGroups(ctx context.Context, term string, specs []Co
 				continue
 			}
 
-			mask := FromMeetingTimes(sec.MeetingTimes)
+			mask := CourseMask(sec)
 
 			if blockedMask.Conflicts(mask) {
 				blockedCount++
 				continue
 			}
+			if pinnedMask.Conflicts(mask) {
+				pinnedConflictCount++
+				continue
+			}
 
 			group.sections = append(group.sections, &sectionData{
 				course: sec,
@@ -205,11 +343,13 @@ func (s *Service) buildCourseGroups(ctx context.Context, term string, specs []Co
 			})
 		} else if asyncCount > 0 {
 			results = append(results, CourseResult{Name: displayName, Status: StatusAsyncOnly})
-		} else if filteredCount > 0 && blockedCount == 0 {
+		} else if filteredCount > 0 && blockedCount == 0 && pinnedConflictCount == 0 {
 			// All sections filtered by AllowedCRNs (none of the specified CRNs exist)
 			results = append(results, CourseResult{Name: displayName, Status: StatusCRNFiltered})
 		} else if blockedCount > 0 {
 			results = append(results, CourseResult{Name: displayName, Status: StatusBlocked})
+		} else if pinnedConflictCount > 0 {
+			results = append(results, CourseResult{Name: displayName, Status: StatusPinnedConflict})
 		}
 	}
 
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"schedule-optimizer/internal/cache"
+	"schedule-optimizer/internal/testutil"
 )
 
 // normalizeCourseKey converts various formats to "SUBJECT:NUMBER".
@@ -520,3 +521,166 @@ func TestIsAsyncOrTBD(t *testing.T) {
 		})
 	}
 }
+
+type fakePrereqs map[string]bool
+
+func (f fakePrereqs) Unmet(ctx context.Context, term string, completed []string) (map[string]bool, error) {
+	if slices.Contains(completed, "CSCI 247") {
+		return nil, nil
+	}
+	return f, nil
+}
+
+func TestGenerate_CheckPrerequisites(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	testutil.SeedTestData(t, db)
+	defer db.Close()
+
+	ctx := context.Background()
+	scheduleCache := cache.NewScheduleCache(queries, nil)
+	scheduleCache.SetMaskFunc(MeetingMask)
+	if err := scheduleCache.LoadTerm(ctx, "202520"); err != nil {
+		t.Fatalf("LoadTerm failed: %v", err)
+	}
+	service := NewService(scheduleCache, queries)
+	service.SetPrerequisites(fakePrereqs{"CSCI 301": true})
+
+	req := GenerateRequest{
+		Term: "202520",
+		CourseSpecs: []CourseSpec{
+			{Subject: "CSCI", CourseNumber: "301"},
+			{Subject: "MATH", CourseNumber: "204"},
+		},
+	}
+	statuses := func(resp *GenerateResponse) map[string]CourseStatus {
+		m := make(map[string]CourseStatus)
+		for _, r := range resp.CourseResults {
+			m[r.Name] = r.Status
+		}
+		return m
+	}
+
+	// Prerequisites are only checked when asked
+	resp, err := service.Generate(ctx, req)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if got := statuses(resp)["CSCI 301"]; got != StatusFound {
+		t.Errorf("unchecked CSCI 301 status = %q, want %q", got, StatusFound)
+	}
+
+	req.CheckPrerequisites = true
+	resp, err = service.Generate(ctx, req)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if got := statuses(resp); got["CSCI 301"] != StatusPrereqsUnmet || got["MATH 204"] != StatusFound {
+		t.Errorf("statuses = %v, want CSCI 301 %q", got, StatusPrereqsUnmet)
+	}
+	for _, s := range resp.Schedules {
+		for _, c := range s.Courses {
+			if c.Subject == "CSCI" {
+				t.Errorf("schedule includes %s %s", c.Subject, c.CourseNumber)
+			}
+		}
+	}
+
+	req.CompletedCourses = []string{"CSCI 247"}
+	if resp, err = service.Generate(ctx, req); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if got := statuses(resp)["CSCI 301"]; got != StatusFound {
+		t.Errorf("with prerequisites met, CSCI 301 status = %q, want %q", got, StatusFound)
+	}
+}
+
+func TestGenerate_PinnedFillMaxCourses(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	testutil.SeedTestData(t, db)
+	defer db.Close()
+
+	ctx := context.Background()
+	scheduleCache := cache.NewScheduleCache(queries, nil)
+	scheduleCache.SetMaskFunc(MeetingMask)
+	if err := scheduleCache.LoadTerm(ctx, "202520"); err != nil {
+		t.Fatalf("LoadTerm failed: %v", err)
+	}
+	service := NewService(scheduleCache, queries)
+
+	req := GenerateRequest{
+		Term:       "202520",
+		PinnedCRNs: []string{"20001"},
+		CourseSpecs: []CourseSpec{
+			{Subject: "MATH", CourseNumber: "204"},
+		},
+		MaxCourses: 1,
+	}
+	resp, err := service.Generate(ctx, req)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if len(resp.Schedules) != 1 || len(resp.Schedules[0].Courses) != 1 || resp.Schedules[0].Courses[0].CRN != "20001" {
+		t.Errorf("schedules = %+v, want only the pinned section", resp.Schedules)
+	}
+
+	// A required course can't fit alongside them
+	req.CourseSpecs[0].Required = true
+	if resp, err = service.Generate(ctx, req); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if len(resp.Schedules) != 0 {
+		t.Errorf("schedules = %+v, want none", resp.Schedules)
+	}
+
+	// With room for one more, MATH 204 is added
+	req.MaxCourses = 2
+	if resp, err = service.Generate(ctx, req); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if len(resp.Schedules) != 1 || len(resp.Schedules[0].Courses) != 2 {
+		t.Errorf("schedules = %+v, want pinned section and MATH 204", resp.Schedules)
+	}
+}
+
+func TestGenerate_PinnedMeetMinCourses(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	testutil.SeedTestData(t, db)
+	defer db.Close()
+
+	ctx := context.Background()
+	scheduleCache := cache.NewScheduleCache(queries, nil)
+	scheduleCache.SetMaskFunc(MeetingMask)
+	if err := scheduleCache.LoadTerm(ctx, "202520"); err != nil {
+		t.Fatalf("LoadTerm failed: %v", err)
+	}
+	service := NewService(scheduleCache, queries)
+
+	req := GenerateRequest{
+		Term:       "202520",
+		PinnedCRNs: []string{"20001"},
+		CourseSpecs: []CourseSpec{
+			{Subject: "MATH", CourseNumber: "204"},
+		},
+		MinCourses: 1,
+	}
+	resp, err := service.Generate(ctx, req)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	sizes := make(map[int]bool)
+	for _, s := range resp.Schedules {
+		sizes[len(s.Courses)] = true
+	}
+	if len(resp.Schedules) != 2 || !sizes[1] || !sizes[2] {
+		t.Errorf("schedules = %+v, want the pinned section alone and with MATH 204", resp.Schedules)
+	}
+
+	// A minimum above the pinned count still requires the extra course
+	req.MinCourses = 2
+	if resp, err = service.Generate(ctx, req); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if len(resp.Schedules) != 1 || len(resp.Schedules[0].Courses) != 2 {
+		t.Errorf("schedules = %+v, want pinned section and MATH 204", resp.Schedules)
+	}
+}
@@ -26,6 +26,12 @@ type GenerateRequest struct {
 	BlockedTimes []BlockedTime `json:"blockedTimes,omitempty"`
 	MinCourses   int           `json:"minCourses"`
 	MaxCourses   int           `json:"maxCourses"`
+	PinnedCRNs   []string      `json:"pinnedCrns,omitempty"` // Sections already registered; included in every schedule
+
+	// When CheckPrerequisites is set, courses whose prerequisites
+	// CompletedCourses doesn't meet are dropped
+	CheckPrerequisites bool     `json:"checkPrerequisites,omitempty"`
+	CompletedCourses   []string `json:"completedCourses,omitempty"` // Course codes, such as "CSCI 145"
 }
 
 // BlockedTime represents a single time block the user cannot attend.
@@ -48,18 +54,22 @@ type CourseResult struct {
 	Name   string       `json:"name"`
 	Status CourseStatus `json:"status"`
 	Count  int          `json:"count,omitempty"`
+	CRN    string       `json:"crn,omitempty"` // Set for pinned sections
 }
 
 // CourseStatus indicates the outcome of looking up a requested course.
 type CourseStatus string
 
 const (
-	StatusFound       CourseStatus = "found"        // Has scheduleable sections
-	StatusAsyncOnly   CourseStatus = "async_only"   // Only async/TBD sections exist
-	StatusBlocked     CourseStatus = "blocked"      // All sections filtered by user's blocked times
-	StatusCRNFiltered CourseStatus = "crn_filtered" // All sections filtered by AllowedCRNs (none matched)
-	StatusNotOffered  CourseStatus = "not_offered"  // Valid course, not offered this term
-	StatusNotExists   CourseStatus = "not_exists"   // Course code doesn't exist at all
+	StatusFound          CourseStatus = "found"           // Has scheduleable sections
+	StatusPinned         CourseStatus = "pinned"          // Section pinned by the user via PinnedCRNs
+	StatusAsyncOnly      CourseStatus = "async_only"      // Only async/TBD sections exist
+	StatusBlocked        CourseStatus = "blocked"         // All sections filtered by user's blocked times
+	StatusPinnedConflict CourseStatus = "pinned_conflict" // All sections conflict with pinned sections
+	StatusCRNFiltered    CourseStatus = "crn_filtered"    // All sections filtered by AllowedCRNs (none matched)
+	StatusPrereqsUnmet   CourseStatus = "prereqs_unmet"   // Prerequisites not met by CompletedCourses
+	StatusNotOffered     CourseStatus = "not_offered"     // Valid course, not offered this term
+	StatusNotExists      CourseStatus = "not_exists"      // Course code doesn't exist at all
 )
 
 // Schedule represents a valid combination of course sections.
@@ -0,0 +1,107 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"schedule-optimizer/internal/scraper"
+	"schedule-optimizer/internal/store"
+)
+
+const (
+	// detailScrapeInterval is how often DetailScrapeJob fetches a batch.
+	detailScrapeInterval = time.Hour
+
+	defaultDetailBatchSize = 200
+)
+
+// DetailListener is notified after course details for a term have been stored.
+// Implementations must return quickly since jobs run sequentially.
+type DetailListener interface {
+	DetailsScraped(term string)
+}
+
+// DetailScrapeJob fetches course descriptions, prerequisites, restrictions,
+// fees, and linked sections for scraped terms, newest terms first. Each hourly
+// run fetches at most batchSize courses, so a new term's hours of requests are
+// spread out instead of holding up the scrape jobs. Courses never fetched go
+// first, then the oldest (course_details.fetched_at), so each run resumes
+// where the last stopped.
+type DetailScrapeJob struct {
+	queries       *store.Queries
+	scraper       *scraper.Scraper
+	pastTermYears int
+	batchSize     int
+	maxAge        time.Duration
+	lastRun       time.Time
+	listeners     []DetailListener
+}
+
+func NewDetailScrapeJob(queries *store.Queries, scraper *scraper.Scraper, pastTermYears, batchSize int, maxAge time.Duration) *DetailScrapeJob {
+	if batchSize < 1 {
+		batchSize = defaultDetailBatchSize
+	}
+	return &DetailScrapeJob{
+		queries:       queries,
+		scraper:       scraper,
+		pastTermYears: pastTermYears,
+		batchSize:     batchSize,
+		maxAge:        maxAge,
+	}
+}
+
+func (j *DetailScrapeJob) Name() string { return "detail-scrape" }
+
+func (j *DetailScrapeJob) ShouldRun(now time.Time) bool {
+	return j.lastRun.IsZero() || now.Sub(j.lastRun) >= detailScrapeInterval
+}
+
+func (j *DetailScrapeJob) NextRun(now time.Time) time.Time {
+	if j.lastRun.IsZero() {
+		return now
+	}
+	return j.lastRun.Add(detailScrapeInterval)
+}
+
+func (j *DetailScrapeJob) Run(ctx context.Context, now time.Time) error {
+	j.lastRun = now
+	cutoff := GetPastTermCutoff(now, j.pastTermYears)
+
+	terms, err := j.queries.GetTerms(ctx)
+	if err != nil {
+		return err
+	}
+
+	budget := j.batchSize
+	var errs []error
+	for _, term := range terms {
+		if budget <= 0 || ctx.Err() != nil {
+			break
+		}
+		if !IsTermInRange(term.Code, cutoff) {
+			continue
+		}
+
+		result, err := j.scraper.ScrapeDetails(ctx, term.Code, j.maxAge, budget)
+		if errors.Is(err, scraper.ErrNoDetails) {
+			slog.Info("Skipping course details: source has none")
+			return nil
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("scrape details for %s: %w", term.Code, err))
+			continue
+		}
+		budget -= result.Stored + result.Failed
+
+		if result.Stored > 0 {
+			slog.Info("Course details stored", "term", term.Code, "stored", result.Stored, "failed", result.Failed, "remaining", result.Remaining)
+			for _, l := range j.listeners {
+				l.DetailsScraped(term.Code)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
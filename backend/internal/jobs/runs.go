@@ -0,0 +1,115 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"schedule-optimizer/internal/scraper"
+	"schedule-optimizer/internal/store"
+)
+
+// Scrape run statuses, as stored in scrape_runs.
+const (
+	RunRunning   = "running"
+	RunSuccess   = "success"   // Every page fetched and every section stored
+	RunPartial   = "partial"   // Some sections stored, but pages or sections failed or came up short
+	RunFailed    = "failed"    // Nothing stored
+	RunCancelled = "cancelled" // Stopped by Pause or shutdown; nothing stored
+)
+
+// runStatus classifies a finished scrape. A scrape that failed after ctx was
+// done was stopped rather than broken.
+func runStatus(ctx context.Context, result scraper.Result, err error) string {
+	switch {
+	case err != nil && ctx.Err() != nil:
+		return RunCancelled
+	case err != nil:
+		return RunFailed
+	case result.Complete():
+		return RunSuccess
+	}
+	return RunPartial
+}
+
+// scrapeTerm scrapes a term for the named job, recording the run in
+// scrape_runs. Listeners are notified if anything was stored. Failing to
+// record the run is logged but doesn't stop the scrape.
+func scrapeTerm(ctx context.Context, queries *store.Queries, sc *scraper.Scraper, job, term string, listeners []ScrapeListener) (scraper.Result, error) {
+	// Runs cut short by Pause or shutdown are still recorded, as cancelled
+	record := context.WithoutCancel(ctx)
+
+	id, err := queries.CreateScrapeRun(record, store.CreateScrapeRunParams{Term: term, Job: job})
+	if err != nil {
+		slog.Warn("Failed to record scrape run", "term", term, "job", job, "error", err)
+	}
+
+	result, scrapeErr := sc.ScrapeTerm(ctx, term)
+
+	if id != 0 {
+		var errText sql.NullString
+		if scrapeErr != nil {
+			errText = sql.NullString{String: scrapeErr.Error(), Valid: true}
+		}
+		if err := queries.FinishScrapeRun(record, store.FinishScrapeRunParams{
+			Status:     runStatus(ctx, result, scrapeErr),
+			Stored:     int64(result.Stored),
+			Expected:   int64(result.Expected),
+			PageErrors: int64(result.PageErrors),
+			SaveErrors: int64(result.SaveErrors),
+			Removed:    int64(result.Removed),
+			Error:      errText,
+			ID:         id,
+		}); err != nil {
+			slog.Warn("Failed to finish scrape run", "id", id, "term", term, "error", err)
+		}
+	}
+
+	if scrapeErr != nil {
+		return result, scrapeErr
+	}
+	notifyScraped(listeners, term)
+	return result, nil
+}
+
+// lastSuccessfulScrape returns when the term's last successful run finished,
+// or the zero time if it has none.
+func lastSuccessfulScrape(ctx context.Context, queries *store.Queries, term string) (time.Time, error) {
+	run, err := queries.GetLastSuccessfulScrapeRun(ctx, term)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return run.FinishedAt.Time, nil
+}
+
+// scrapeDue reports whether a term's last successful run is at least interval
+// (less scrapeTolerance) old. Partial and failed runs don't count, so a term
+// keeps being retried until a scrape gets every section.
+func scrapeDue(ctx context.Context, queries *store.Queries, term string, interval time.Duration, now time.Time) (bool, error) {
+	last, err := lastSuccessfulScrape(ctx, queries, term)
+	if err != nil {
+		return false, err
+	}
+	if last.IsZero() {
+		return true, nil
+	}
+	return now.Sub(last) >= interval-scrapeTolerance, nil
+}
+
+// failUnfinishedRuns closes out runs left running by a previous process that
+// stopped mid-scrape.
+func failUnfinishedRuns(ctx context.Context, queries *store.Queries) {
+	n, err := queries.FailUnfinishedScrapeRuns(ctx, sql.NullString{String: "interrupted", Valid: true})
+	if err != nil {
+		slog.Warn("Failed to close out unfinished scrape runs", "error", err)
+		return
+	}
+	if n > 0 {
+		slog.Info("Marked interrupted scrape runs as failed", "count", n)
+	}
+}
@@ -0,0 +1,233 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"schedule-optimizer/internal/scraper"
+	"schedule-optimizer/internal/store"
+	"schedule-optimizer/internal/testutil"
+)
+
+const testRunsCatalog = `term,crn,subject,course_number,title
+202520,20001,CSCI,247,Data Structures
+202520,20002,MATH,204,Linear Algebra
+`
+
+type recordingListener struct{ terms []string }
+
+func (l *recordingListener) TermScraped(term string) { l.terms = append(l.terms, term) }
+
+func newCatalogScraper(t *testing.T) (*scraper.Scraper, *store.Queries) {
+	t.Helper()
+	db, queries := testutil.SetupTestDB(t)
+	t.Cleanup(func() { db.Close() })
+
+	path := filepath.Join(t.TempDir(), "catalog.csv")
+	if err := os.WriteFile(path, []byte(testRunsCatalog), 0644); err != nil {
+		t.Fatalf("failed to write catalog: %v", err)
+	}
+	src, err := scraper.NewCatalogSource(path)
+	if err != nil {
+		t.Fatalf("NewCatalogSource failed: %v", err)
+	}
+	sc, err := scraper.NewScraper(db, queries, 1, 0)
+	if err != nil {
+		t.Fatalf("NewScraper failed: %v", err)
+	}
+	sc.SetSource(src)
+	return sc, queries
+}
+
+func TestRunStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		result scraper.Result
+		err    error
+		want   string
+	}{
+		{"complete", scraper.Result{Stored: 2, Expected: 2}, nil, RunSuccess},
+		{"empty term", scraper.Result{}, nil, RunSuccess},
+		{"page errors", scraper.Result{Stored: 500, Expected: 600, PageErrors: 1}, nil, RunPartial},
+		{"short", scraper.Result{Stored: 1, Expected: 2}, nil, RunPartial},
+		{"save errors", scraper.Result{Stored: 1, Expected: 2, SaveErrors: 1}, nil, RunPartial},
+		{"error", scraper.Result{Expected: 2, PageErrors: 1}, errors.New("all pages failed"), RunFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runStatus(context.Background(), tt.result, tt.err); got != tt.want {
+				t.Errorf("runStatus = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// An error after cancellation is a stop, not a failure, but a scrape that
+	// finished anyway keeps its status
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got := runStatus(ctx, scraper.Result{Expected: 2}, context.Canceled); got != RunCancelled {
+		t.Errorf("cancelled runStatus = %q, want %q", got, RunCancelled)
+	}
+	if got := runStatus(ctx, scraper.Result{Stored: 2, Expected: 2}, nil); got != RunSuccess {
+		t.Errorf("cancelled after finishing runStatus = %q, want %q", got, RunSuccess)
+	}
+}
+
+func TestScrapeTerm_RecordsRun(t *testing.T) {
+	sc, queries := newCatalogScraper(t)
+	ctx := context.Background()
+	listener := &recordingListener{}
+
+	result, err := scrapeTerm(ctx, queries, sc, "active-scrape", "202520", []ScrapeListener{listener})
+	if err != nil {
+		t.Fatalf("scrapeTerm failed: %v", err)
+	}
+	if result.Stored != 2 {
+		t.Errorf("stored = %d, want 2", result.Stored)
+	}
+	if len(listener.terms) != 1 {
+		t.Errorf("listener notified for %v, want [202520]", listener.terms)
+	}
+
+	runs, err := queries.GetScrapeRuns(ctx, store.GetScrapeRunsParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetScrapeRuns failed: %v", err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("runs = %d, want 1", len(runs))
+	}
+	run := runs[0]
+	if run.Term != "202520" || run.Job != "active-scrape" || run.Status != RunSuccess ||
+		run.Stored != 2 || run.Expected != 2 || run.Error.Valid || !run.FinishedAt.Valid {
+		t.Errorf("run = %+v", run)
+	}
+}
+
+func TestScrapeTerm_RecordsCancellation(t *testing.T) {
+	sc, queries := newCatalogScraper(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	listener := &recordingListener{}
+
+	if _, err := scrapeTerm(ctx, queries, sc, "daily-scrape", "202520", []ScrapeListener{listener}); err == nil {
+		t.Fatal("expected scrape with cancelled context to fail")
+	}
+	if len(listener.terms) != 0 {
+		t.Errorf("listener notified for cancelled scrape: %v", listener.terms)
+	}
+
+	runs, err := queries.GetScrapeRuns(context.Background(), store.GetScrapeRunsParams{Term: "202520", Limit: 10})
+	if err != nil {
+		t.Fatalf("GetScrapeRuns failed: %v", err)
+	}
+	if len(runs) != 1 || runs[0].Status != RunCancelled || !runs[0].Error.Valid {
+		t.Errorf("runs = %+v, want one cancelled run with error text", runs)
+	}
+}
+
+func TestScrapeDue(t *testing.T) {
+	sc, queries := newCatalogScraper(t)
+	ctx := context.Background()
+	interval := 8 * time.Hour
+
+	due, err := scrapeDue(ctx, queries, "202520", interval, time.Now())
+	if err != nil || !due {
+		t.Fatalf("never scraped: due = %v, err = %v; want due", due, err)
+	}
+
+	// A partial run doesn't count as fresh
+	id, err := queries.CreateScrapeRun(ctx, store.CreateScrapeRunParams{Term: "202520", Job: "active-scrape"})
+	if err != nil {
+		t.Fatalf("CreateScrapeRun failed: %v", err)
+	}
+	if err := queries.FinishScrapeRun(ctx, store.FinishScrapeRunParams{
+		Status: RunPartial, Stored: 1, Expected: 2, PageErrors: 1, ID: id,
+	}); err != nil {
+		t.Fatalf("FinishScrapeRun failed: %v", err)
+	}
+	if due, _ := scrapeDue(ctx, queries, "202520", interval, time.Now()); !due {
+		t.Error("after partial run: want due")
+	}
+
+	if _, err := scrapeTerm(ctx, queries, sc, "active-scrape", "202520", nil); err != nil {
+		t.Fatalf("scrapeTerm failed: %v", err)
+	}
+	if due, _ := scrapeDue(ctx, queries, "202520", interval, time.Now()); due {
+		t.Error("just scraped: want not due")
+	}
+	if due, _ := scrapeDue(ctx, queries, "202520", interval, time.Now().Add(interval)); !due {
+		t.Error("an interval later: want due")
+	}
+}
+
+func TestFailUnfinishedRuns(t *testing.T) {
+	_, queries := newCatalogScraper(t)
+	ctx := context.Background()
+
+	if _, err := queries.CreateScrapeRun(ctx, store.CreateScrapeRunParams{Term: "202520", Job: "active-scrape"}); err != nil {
+		t.Fatalf("CreateScrapeRun failed: %v", err)
+	}
+	failUnfinishedRuns(ctx, queries)
+
+	runs, _ := queries.GetScrapeRuns(ctx, store.GetScrapeRunsParams{Limit: 10})
+	if len(runs) != 1 || runs[0].Status != RunFailed || runs[0].Error.String != "interrupted" || !runs[0].FinishedAt.Valid {
+		t.Errorf("runs = %+v, want one interrupted run", runs)
+	}
+}
+
+func TestTermScrapeJob(t *testing.T) {
+	sc, queries := newCatalogScraper(t)
+	ctx := context.Background()
+	listener := &recordingListener{}
+	job := NewTermScrapeJob(queries, sc)
+	job.listeners = []ScrapeListener{listener}
+
+	if job.ShouldRun(time.Now()) {
+		t.Fatal("job with nothing queued should not run")
+	}
+	job.Enqueue("202520")
+	job.Enqueue("202520")
+	if !job.ShouldRun(time.Now()) {
+		t.Fatal("job with a queued term should run")
+	}
+	if err := job.Run(ctx, time.Now()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if job.ShouldRun(time.Now()) {
+		t.Error("queue should be empty after a run")
+	}
+
+	// Terms are scraped even when they aren't due
+	job.Enqueue("202520")
+	if err := job.Run(ctx, time.Now()); err != nil {
+		t.Fatalf("second Run failed: %v", err)
+	}
+	runs, _ := queries.GetScrapeRuns(ctx, store.GetScrapeRunsParams{Term: "202520", Limit: 10})
+	if len(runs) != 2 || runs[0].Job != "term-scrape" || runs[0].Status != RunSuccess {
+		t.Errorf("runs = %+v, want two successful term-scrape runs", runs)
+	}
+	if len(listener.terms) != 2 {
+		t.Errorf("listener notified for %v, want two scrapes", listener.terms)
+	}
+}
+
+func TestDetailScrapeJob_NoDetails(t *testing.T) {
+	sc, queries := newCatalogScraper(t)
+	ctx := context.Background()
+	if _, err := sc.ScrapeTerm(ctx, "202520"); err != nil {
+		t.Fatalf("ScrapeTerm failed: %v", err)
+	}
+
+	// Catalogs have no details; the run is skipped rather than failed
+	job := NewDetailScrapeJob(queries, sc, 100, 10, time.Hour)
+	if err := job.Run(ctx, time.Now()); err != nil {
+		t.Errorf("Run = %v, want nil for a source without details", err)
+	}
+	if job.ShouldRun(time.Now()) {
+		t.Error("expected the job to wait an hour after running")
+	}
+}
@@ -2,7 +2,11 @@ package jobs
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
+	"sync"
 	"time"
 
 	"schedule-optimizer/internal/scraper"
@@ -14,6 +18,15 @@ import (
 // check to fail by N minutes, potentially skipping every other cycle.
 const scrapeTolerance = 30 * time.Minute
 
+// runOnce is NextRun for jobs that run once on startup. A run cancelled by
+// Pause or shutdown doesn't count, so the job runs again after Resume.
+func runOnce(hasRun bool, now time.Time) time.Time {
+	if hasRun {
+		return time.Time{}
+	}
+	return now
+}
+
 // BootstrapJob initializes term data and runs any other jobs that need to catch up.
 // Runs once on startup to ensure the system has current data immediately.
 type BootstrapJob struct {
@@ -35,14 +48,25 @@ func (j *BootstrapJob) ShouldRun(now time.Time) bool {
 	return !j.hasRun
 }
 
+func (j *BootstrapJob) NextRun(now time.Time) time.Time {
+	return runOnce(j.hasRun, now)
+}
+
 func (j *BootstrapJob) Run(ctx context.Context, now time.Time) error {
-	j.hasRun = true
+	defer func() {
+		if ctx.Err() == nil {
+			j.hasRun = true
+		}
+	}()
 
 	if _, err := j.scraper.ScrapeTerms(ctx); err != nil {
 		slog.Error("Failed to fetch available terms", "error", err)
 	}
 
 	for _, job := range j.otherJobs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		if job.ShouldRun(now) {
 			slog.Info("Bootstrap triggering job", "job", job.Name())
 			if err := job.Run(ctx, now); err != nil {
@@ -54,13 +78,15 @@ func (j *BootstrapJob) Run(ctx context.Context, now time.Time) error {
 	return nil
 }
 
-// PastTermBackfillJob scrapes past terms that have never been scraped.
-// Runs once on startup to backfill historical data.
+// PastTermBackfillJob scrapes past terms that have never been scraped
+// completely. Runs once on startup to backfill historical data; partial runs
+// leave last_scraped_at unset, so the next start retries them.
 type PastTermBackfillJob struct {
 	queries       *store.Queries
 	scraper       *scraper.Scraper
 	pastTermYears int
 	hasRun        bool
+	listeners     []ScrapeListener
 }
 
 func NewPastTermBackfillJob(queries *store.Queries, scraper *scraper.Scraper, pastTermYears int) *PastTermBackfillJob {
@@ -77,8 +103,16 @@ func (j *PastTermBackfillJob) ShouldRun(now time.Time) bool {
 	return !j.hasRun
 }
 
+func (j *PastTermBackfillJob) NextRun(now time.Time) time.Time {
+	return runOnce(j.hasRun, now)
+}
+
 func (j *PastTermBackfillJob) Run(ctx context.Context, now time.Time) error {
-	j.hasRun = true
+	defer func() {
+		if ctx.Err() == nil {
+			j.hasRun = true
+		}
+	}()
 	cutoff := GetPastTermCutoff(now, j.pastTermYears)
 
 	terms, err := j.queries.GetTermsNeverScraped(ctx)
@@ -87,6 +121,9 @@ func (j *PastTermBackfillJob) Run(ctx context.Context, now time.Time) error {
 	}
 
 	for _, term := range terms {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		if !IsTermInRange(term.Code, cutoff) {
 			continue
 		}
@@ -97,25 +134,27 @@ func (j *PastTermBackfillJob) Run(ctx context.Context, now time.Time) error {
 		}
 
 		slog.Info("Scraping past term", "term", term.Code, "description", term.Description)
-		stored, err := j.scraper.ScrapeTerm(ctx, term.Code)
+		result, err := scrapeTerm(ctx, j.queries, j.scraper, j.Name(), term.Code, j.listeners)
 		if err != nil {
 			slog.Error("Failed to scrape past term", "term", term.Code, "error", err)
 			continue
 		}
-		slog.Info("Past term scrape complete", "term", term.Code, "sections", stored)
+		slog.Info("Past term scrape complete", "term", term.Code, "sections", result.Stored)
 	}
 
 	return nil
 }
 
 // ActiveScrapeJob scrapes terms in active registration phase.
-// Runs at a configurable interval (default 8 hours).
+// Runs at a configurable interval (default 8 hours). A term is rescraped once
+// its last successful run (see scrape_runs) is an interval old.
 type ActiveScrapeJob struct {
 	queries       *store.Queries
 	scraper       *scraper.Scraper
 	pastTermYears int
 	interval      time.Duration
 	lastRun       time.Time
+	listeners     []ScrapeListener
 }
 
 func NewActiveScrapeJob(queries *store.Queries, scraper *scraper.Scraper, pastTermYears int, intervalHours int) *ActiveScrapeJob {
@@ -136,8 +175,19 @@ func (j *ActiveScrapeJob) ShouldRun(now time.Time) bool {
 	return now.Sub(j.lastRun) >= j.interval-scrapeTolerance
 }
 
+func (j *ActiveScrapeJob) NextRun(now time.Time) time.Time {
+	if j.lastRun.IsZero() {
+		return now
+	}
+	return j.lastRun.Add(j.interval - scrapeTolerance)
+}
+
 func (j *ActiveScrapeJob) Run(ctx context.Context, now time.Time) error {
-	j.lastRun = now
+	defer func() {
+		if ctx.Err() == nil {
+			j.lastRun = now
+		}
+	}()
 	cutoff := GetPastTermCutoff(now, j.pastTermYears)
 
 	terms, err := j.queries.GetTerms(ctx)
@@ -146,6 +196,9 @@ func (j *ActiveScrapeJob) Run(ctx context.Context, now time.Time) error {
 	}
 
 	for _, term := range terms {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		if !IsTermInRange(term.Code, cutoff) {
 			continue
 		}
@@ -155,37 +208,37 @@ func (j *ActiveScrapeJob) Run(ctx context.Context, now time.Time) error {
 			continue
 		}
 
-		if !j.shouldScrapeTerm(term, now) {
+		due, err := scrapeDue(ctx, j.queries, term.Code, j.interval, now)
+		if err != nil {
+			slog.Error("Failed to check last scrape", "term", term.Code, "error", err)
+			continue
+		}
+		if !due {
 			continue
 		}
 
 		slog.Info("Scraping active term", "term", term.Code, "description", term.Description)
-		stored, err := j.scraper.ScrapeTerm(ctx, term.Code)
+		result, err := scrapeTerm(ctx, j.queries, j.scraper, j.Name(), term.Code, j.listeners)
 		if err != nil {
 			slog.Error("Failed to scrape term", "term", term.Code, "error", err)
 			continue
 		}
-		slog.Info("Active term scrape complete", "term", term.Code, "sections", stored)
+		slog.Info("Active term scrape complete", "term", term.Code, "sections", result.Stored)
 	}
 
 	return nil
 }
 
-func (j *ActiveScrapeJob) shouldScrapeTerm(term *store.Term, now time.Time) bool {
-	if !term.LastScrapedAt.Valid {
-		return true
-	}
-	return now.Sub(term.LastScrapedAt.Time) >= j.interval-scrapeTolerance
-}
-
 // DailyScrapeJob scrapes terms in pre-registration phase.
-// Runs once daily at a configurable hour.
+// Runs once daily at a configurable hour, rescraping terms without a
+// successful run in the past day.
 type DailyScrapeJob struct {
 	queries       *store.Queries
 	scraper       *scraper.Scraper
 	pastTermYears int
 	targetHour    int
 	lastRunDate   time.Time
+	listeners     []ScrapeListener
 }
 
 func NewDailyScrapeJob(queries *store.Queries, scraper *scraper.Scraper, pastTermYears int, targetHour int) *DailyScrapeJob {
@@ -211,8 +264,23 @@ func (j *DailyScrapeJob) ShouldRun(now time.Time) bool {
 	return !j.lastRunDate.Equal(today)
 }
 
+func (j *DailyScrapeJob) NextRun(now time.Time) time.Time {
+	if j.ShouldRun(now) {
+		return now
+	}
+	next := time.Date(now.Year(), now.Month(), now.Day(), j.targetHour, 0, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
 func (j *DailyScrapeJob) Run(ctx context.Context, now time.Time) error {
-	j.lastRunDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	defer func() {
+		if ctx.Err() == nil {
+			j.lastRunDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		}
+	}()
 	cutoff := GetPastTermCutoff(now, j.pastTermYears)
 
 	terms, err := j.queries.GetTerms(ctx)
@@ -221,6 +289,9 @@ func (j *DailyScrapeJob) Run(ctx context.Context, now time.Time) error {
 	}
 
 	for _, term := range terms {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		if !IsTermInRange(term.Code, cutoff) {
 			continue
 		}
@@ -230,25 +301,102 @@ func (j *DailyScrapeJob) Run(ctx context.Context, now time.Time) error {
 			continue
 		}
 
-		if !j.shouldScrapeTerm(term, now) {
+		due, err := scrapeDue(ctx, j.queries, term.Code, 24*time.Hour, now)
+		if err != nil {
+			slog.Error("Failed to check last scrape", "term", term.Code, "error", err)
+			continue
+		}
+		if !due {
 			continue
 		}
 
 		slog.Info("Scraping pre-registration term", "term", term.Code, "description", term.Description)
-		stored, err := j.scraper.ScrapeTerm(ctx, term.Code)
+		result, err := scrapeTerm(ctx, j.queries, j.scraper, j.Name(), term.Code, j.listeners)
 		if err != nil {
 			slog.Error("Failed to scrape term", "term", term.Code, "error", err)
 			continue
 		}
-		slog.Info("Pre-registration term scrape complete", "term", term.Code, "sections", stored)
+		slog.Info("Pre-registration term scrape complete", "term", term.Code, "sections", result.Stored)
 	}
 
 	return nil
 }
 
-func (j *DailyScrapeJob) shouldScrapeTerm(term *store.Term, now time.Time) bool {
-	if !term.LastScrapedAt.Valid {
-		return true
+// TermScrapeJob scrapes terms queued by Service.TriggerScrape, whether or not
+// they're due. Runs whenever terms are queued.
+type TermScrapeJob struct {
+	queries   *store.Queries
+	scraper   *scraper.Scraper
+	listeners []ScrapeListener
+
+	mu      sync.Mutex // Guards pending, which is filled from other goroutines
+	pending []string
+}
+
+func NewTermScrapeJob(queries *store.Queries, scraper *scraper.Scraper) *TermScrapeJob {
+	return &TermScrapeJob{queries: queries, scraper: scraper}
+}
+
+func (j *TermScrapeJob) Name() string { return "term-scrape" }
+
+// Enqueue queues a term to be scraped on the job's next run.
+func (j *TermScrapeJob) Enqueue(term string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if !slices.Contains(j.pending, term) {
+		j.pending = append(j.pending, term)
+	}
+}
+
+func (j *TermScrapeJob) ShouldRun(now time.Time) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return len(j.pending) > 0
+}
+
+func (j *TermScrapeJob) NextRun(now time.Time) time.Time {
+	if j.ShouldRun(now) {
+		return now
+	}
+	return time.Time{}
+}
+
+// Run scrapes the queued terms. If it's cancelled, the term being scraped and
+// any not yet started go back on the queue for the next run.
+func (j *TermScrapeJob) Run(ctx context.Context, now time.Time) error {
+	j.mu.Lock()
+	terms := j.pending
+	j.pending = nil
+	j.mu.Unlock()
+
+	var errs []error
+	for i, term := range terms {
+		slog.Info("Scraping requested term", "term", term)
+		result, err := scrapeTerm(ctx, j.queries, j.scraper, j.Name(), term, j.listeners)
+		if err != nil && ctx.Err() != nil {
+			// Stopped before the term was stored
+			j.requeue(terms[i:])
+			return errors.Join(append(errs, ctx.Err())...)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("scrape %s: %w", term, err))
+			continue
+		}
+		slog.Info("Requested term scrape complete", "term", term, "sections", result.Stored)
+	}
+	return errors.Join(errs...)
+}
+
+// requeue puts terms back at the front of the queue, ahead of any queued
+// since the run started.
+func (j *TermScrapeJob) requeue(terms []string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	pending := slices.Clone(terms)
+	for _, term := range j.pending {
+		if !slices.Contains(pending, term) {
+			pending = append(pending, term)
+		}
 	}
-	return now.Sub(term.LastScrapedAt.Time) >= 24*time.Hour-scrapeTolerance
+	j.pending = pending
 }
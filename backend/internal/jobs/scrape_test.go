@@ -1,6 +1,7 @@
 package jobs
 
 import (
+	"context"
 	"testing"
 	"time"
 )
@@ -33,6 +34,26 @@ func TestPastTermBackfillJob_ShouldRun(t *testing.T) {
 	}
 }
 
+func TestScrapeJobs_CancelledRunNotCounted(t *testing.T) {
+	sc, queries := newCatalogScraper(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	now := time.Now()
+
+	jobs := []Job{
+		NewBootstrapJob(sc, nil),
+		NewPastTermBackfillJob(queries, sc, 2),
+		NewActiveScrapeJob(queries, sc, 2, 8),
+		NewDailyScrapeJob(queries, sc, 2, 3),
+	}
+	for _, job := range jobs {
+		job.Run(ctx, now)
+		if !job.ShouldRun(now) {
+			t.Errorf("%s: cancelled run counted, want it still due", job.Name())
+		}
+	}
+}
+
 func TestActiveScrapeJob_ShouldRun(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -170,3 +191,43 @@ func TestScrapeTolerance(t *testing.T) {
 		t.Errorf("scrapeTolerance = %v, want 30m", scrapeTolerance)
 	}
 }
+
+func TestNextRun(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.Local)
+	tests := []struct {
+		name string
+		job  NextRunner
+		want time.Time
+	}{
+		{"bootstrap pending", &BootstrapJob{}, now},
+		{"bootstrap done", &BootstrapJob{hasRun: true}, time.Time{}},
+		{"active first run", &ActiveScrapeJob{interval: 8 * time.Hour}, now},
+		{
+			"active after run",
+			&ActiveScrapeJob{interval: 8 * time.Hour, lastRun: now.Add(-time.Hour)},
+			now.Add(-time.Hour + 8*time.Hour - scrapeTolerance),
+		},
+		{"daily first run", &DailyScrapeJob{targetHour: 3}, now},
+		{
+			"daily past today's hour",
+			&DailyScrapeJob{targetHour: 3, lastRunDate: time.Date(2025, 1, 15, 0, 0, 0, 0, time.Local)},
+			time.Date(2025, 1, 16, 3, 0, 0, 0, time.Local),
+		},
+		{
+			"daily later today",
+			&DailyScrapeJob{targetHour: 22, lastRunDate: time.Date(2025, 1, 14, 0, 0, 0, 0, time.Local)},
+			time.Date(2025, 1, 15, 22, 0, 0, 0, time.Local),
+		},
+		{"term scrape idle", &TermScrapeJob{}, time.Time{}},
+		{"term scrape queued", &TermScrapeJob{pending: []string{"202520"}}, now},
+		{"detail scrape first run", &DetailScrapeJob{}, now},
+		{"detail scrape after run", &DetailScrapeJob{lastRun: now.Add(-10 * time.Minute)}, now.Add(50 * time.Minute)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.job.NextRun(now); !got.Equal(tt.want) {
+				t.Errorf("NextRun = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
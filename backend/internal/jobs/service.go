@@ -2,11 +2,40 @@ package jobs
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"sync"
 	"time"
 )
 
+var (
+	ErrUnknownJob = errors.New("unknown job")
+	ErrPaused     = errors.New("jobs are paused")
+)
+
+// Status is the service's control state and each job's state.
+type Status struct {
+	Paused   bool       `json:"paused"`
+	PausedAt *time.Time `json:"pausedAt,omitempty"`
+	Jobs     []JobState `json:"jobs"`
+}
+
+// JobState is a job's run history since startup and when it's next due.
+type JobState struct {
+	Name           string     `json:"name"`
+	Running        bool       `json:"running"`
+	Triggered      bool       `json:"triggered"` // Queued to run on the next check
+	Runs           int        `json:"runs"`
+	Failures       int        `json:"failures"`
+	Cancellations  int        `json:"cancellations"`       // Runs cut short by Pause or shutdown; not failures
+	LastRunAt      *time.Time `json:"lastRunAt,omitempty"` // When the latest run started
+	LastDurationMs int64      `json:"lastDurationMs"`
+	LastError      string     `json:"lastError,omitempty"` // Empty if the last run succeeded or was cancelled
+	LastCancelled  bool       `json:"lastCancelled,omitempty"`
+	NextRunAt      *time.Time `json:"nextRunAt,omitempty"` // Next eligible time; omitted if the job won't run again on its own
+}
+
 // Job represents a schedulable task with its own timing logic.
 type Job interface {
 	Name() string
@@ -14,16 +43,49 @@ type Job interface {
 	Run(ctx context.Context, now time.Time) error
 }
 
+// ScrapeListener is notified after a term's sections have been scraped and stored.
+// Implementations must return quickly since jobs run sequentially.
+type ScrapeListener interface {
+	TermScraped(term string)
+}
+
+// notifyScraped tells each listener that a term was scraped.
+func notifyScraped(listeners []ScrapeListener, term string) {
+	for _, l := range listeners {
+		l.TermScraped(term)
+	}
+}
+
+// NextRunner is implemented by jobs that can tell when ShouldRun will next
+// return true. It's called from the same goroutine as ShouldRun and Run.
+type NextRunner interface {
+	// NextRun returns when the job is next eligible to run, or the zero time
+	// if it won't run again on its own.
+	NextRun(now time.Time) time.Time
+}
+
 // Service manages and runs registered jobs on a check interval.
 // Jobs are checked and run sequentially in registration order.
 // This means jobs do not need internal synchronization for their own state,
 // but they should not block for extended periods.
+//
+// Jobs can also be triggered by name, which runs them on the next check
+// whether or not they're due. While paused, no jobs run and the running job's
+// context is cancelled; a cancelled run doesn't count, so the job (and its
+// trigger, if any) runs again after Resume.
 type Service struct {
 	mu            sync.Mutex
 	jobs          []Job
 	checkInterval time.Duration
 	started       bool
 	stopCh        chan struct{}
+	wake          chan struct{} // Starts a check early after a trigger or resume
+
+	states    map[string]*JobState
+	triggered map[string]bool
+	paused    bool
+	pausedAt  time.Time
+	cancelRun context.CancelFunc // Cancels the running job
 }
 
 // NewService creates a job service that checks jobs at the given interval.
@@ -35,6 +97,9 @@ func NewService(checkInterval time.Duration) *Service {
 		jobs:          make([]Job, 0),
 		checkInterval: checkInterval,
 		stopCh:        make(chan struct{}),
+		wake:          make(chan struct{}, 1),
+		states:        make(map[string]*JobState),
+		triggered:     make(map[string]bool),
 	}
 }
 
@@ -47,6 +112,7 @@ func (s *Service) Register(job Job) {
 		return
 	}
 	s.jobs = append(s.jobs, job)
+	s.states[job.Name()] = &JobState{Name: job.Name()}
 	slog.Info("Registered job", "job", job.Name())
 }
 
@@ -73,6 +139,8 @@ func (s *Service) Start(ctx context.Context) {
 			return
 		case now := <-ticker.C:
 			s.checkJobs(ctx, now)
+		case <-s.wake:
+			s.checkJobs(ctx, time.Now())
 		}
 	}
 }
@@ -82,16 +150,191 @@ func (s *Service) Stop() {
 	close(s.stopCh)
 }
 
+// Trigger queues a job to run on the next check, whether or not it's due. If
+// the job is running, it runs again once it finishes.
+func (s *Service) Trigger(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.states[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownJob, name)
+	}
+	if s.paused {
+		return ErrPaused
+	}
+	s.triggered[name] = true
+	s.wakeLocked()
+	slog.Info("Job triggered", "job", name)
+	return nil
+}
+
+// TriggerScrape queues a scrape of one term on the first registered
+// TermScrapeJob, whether or not the term is due.
+func (s *Service) TriggerScrape(term string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.paused {
+		return ErrPaused
+	}
+	for _, job := range s.jobs {
+		if j, ok := job.(*TermScrapeJob); ok {
+			j.Enqueue(term)
+			s.wakeLocked()
+			slog.Info("Term scrape triggered", "term", term)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: no term scrape job registered", ErrUnknownJob)
+}
+
+// Pause stops jobs from running until Resume, cancelling the running job.
+// Pausing doesn't survive a restart.
+func (s *Service) Pause() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.paused {
+		return
+	}
+	s.paused = true
+	s.pausedAt = time.Now()
+	if s.cancelRun != nil {
+		s.cancelRun()
+	}
+	slog.Info("Jobs paused")
+}
+
+// Resume lets jobs run again, starting a check right away.
+func (s *Service) Resume() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.paused {
+		return
+	}
+	s.paused = false
+	s.pausedAt = time.Time{}
+	s.wakeLocked()
+	slog.Info("Jobs resumed")
+}
+
+// wakeLocked starts a check early unless one is already pending.
+func (s *Service) wakeLocked() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Status returns whether jobs are paused and each job's state, in
+// registration order.
+func (s *Service) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := Status{Paused: s.paused, Jobs: make([]JobState, 0, len(s.jobs))}
+	if s.paused {
+		pausedAt := s.pausedAt
+		status.PausedAt = &pausedAt
+	}
+	for _, job := range s.jobs {
+		state := *s.states[job.Name()]
+		state.Triggered = s.triggered[job.Name()]
+		status.Jobs = append(status.Jobs, state)
+	}
+	return status
+}
+
 func (s *Service) checkJobs(ctx context.Context, now time.Time) {
 	for _, job := range s.jobs {
-		if job.ShouldRun(now) {
-			slog.Info("Running job", "job", job.Name())
-			start := time.Now()
-			if err := job.Run(ctx, now); err != nil {
-				slog.Error("Job failed", "job", job.Name(), "error", err, "duration", time.Since(start))
-			} else {
-				slog.Info("Job completed", "job", job.Name(), "duration", time.Since(start))
+		name := job.Name()
+
+		s.mu.Lock()
+		if s.paused {
+			s.mu.Unlock()
+			break
+		}
+		triggered := s.triggered[name]
+		delete(s.triggered, name)
+		s.mu.Unlock()
+
+		if !triggered && !job.ShouldRun(now) {
+			continue
+		}
+		ran := s.runJob(ctx, job, now)
+
+		// Paused before the job started or while it ran; a trigger waits
+		// for Resume
+		s.mu.Lock()
+		stopped := !ran || s.states[name].LastCancelled
+		if stopped && triggered {
+			s.triggered[name] = true
+		}
+		s.mu.Unlock()
+		if stopped {
+			break
+		}
+	}
+
+	// Refreshed here since NextRun reads state only this goroutine writes
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, job := range s.jobs {
+		state := s.states[job.Name()]
+		state.NextRunAt = nil
+		if nr, ok := job.(NextRunner); ok {
+			if next := nr.NextRun(time.Now()); !next.IsZero() {
+				state.NextRunAt = &next
 			}
 		}
 	}
 }
+
+// runJob runs a job and records the run in its state. Returns false without
+// running it if jobs were paused since checkJobs looked.
+func (s *Service) runJob(ctx context.Context, job Job, now time.Time) bool {
+	name := job.Name()
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	start := time.Now()
+	s.mu.Lock()
+	// Checked under the lock that publishes cancelRun, so a Pause either
+	// lands before this and the run is skipped, or after and cancels it
+	if s.paused {
+		s.mu.Unlock()
+		return false
+	}
+	s.cancelRun = cancel
+	state := s.states[name]
+	state.Running = true
+	state.LastRunAt = &start
+	s.mu.Unlock()
+
+	slog.Info("Running job", "job", name)
+	err := job.Run(runCtx, now)
+	duration := time.Since(start)
+	cancelled := runCtx.Err() != nil
+	switch {
+	case cancelled:
+		slog.Info("Job cancelled", "job", name, "duration", duration)
+	case err != nil:
+		slog.Error("Job failed", "job", name, "error", err, "duration", duration)
+	default:
+		slog.Info("Job completed", "job", name, "duration", duration)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cancelRun = nil
+	state.Running = false
+	state.Runs++
+	state.LastDurationMs = duration.Milliseconds()
+	state.LastError = ""
+	state.LastCancelled = cancelled
+	switch {
+	case cancelled:
+		state.Cancellations++
+	case err != nil:
+		state.LastError = err.Error()
+		state.Failures++
+	}
+	return true
+}
@@ -2,9 +2,17 @@ package jobs
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"schedule-optimizer/internal/scraper"
+	"schedule-optimizer/internal/store"
 )
 
 type mockJob struct {
@@ -111,3 +119,311 @@ func TestNewService_DefaultInterval(t *testing.T) {
 		t.Errorf("expected default interval of 1m for negative input, got %v", svc2.checkInterval)
 	}
 }
+
+// blockingJob runs until its context is cancelled.
+type blockingJob struct {
+	started chan struct{}
+}
+
+func (b *blockingJob) Name() string             { return "blocking" }
+func (b *blockingJob) ShouldRun(time.Time) bool { return false }
+func (b *blockingJob) Run(ctx context.Context, now time.Time) error {
+	b.started <- struct{}{}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func jobState(t *testing.T, svc *Service, name string) JobState {
+	t.Helper()
+	for _, state := range svc.Status().Jobs {
+		if state.Name == name {
+			return state
+		}
+	}
+	t.Fatalf("no state for job %s", name)
+	return JobState{}
+}
+
+func TestService_TracksState(t *testing.T) {
+	svc := NewService(time.Minute)
+	okJob := &mockJob{name: "ok", shouldRun: true}
+	failJob := &mockJob{name: "fail", shouldRun: true, runErr: errors.New("banner down")}
+	svc.Register(okJob)
+	svc.Register(failJob)
+	svc.Register(&BootstrapJob{hasRun: true})
+
+	svc.checkJobs(context.Background(), time.Now())
+
+	ok := jobState(t, svc, "ok")
+	if ok.Runs != 1 || ok.Failures != 0 || ok.LastRunAt == nil || ok.LastError != "" || ok.Running {
+		t.Errorf("ok state = %+v", ok)
+	}
+	fail := jobState(t, svc, "fail")
+	if fail.Runs != 1 || fail.Failures != 1 || fail.LastError != "banner down" {
+		t.Errorf("fail state = %+v", fail)
+	}
+	if bootstrap := jobState(t, svc, "bootstrap"); bootstrap.LastRunAt != nil || bootstrap.NextRunAt != nil {
+		t.Errorf("finished bootstrap state = %+v, want no runs and no next run", bootstrap)
+	}
+
+	// A later success clears the error
+	failJob.runErr = nil
+	svc.checkJobs(context.Background(), time.Now())
+	if fail := jobState(t, svc, "fail"); fail.Runs != 2 || fail.Failures != 1 || fail.LastError != "" {
+		t.Errorf("fail state after success = %+v", fail)
+	}
+}
+
+func TestService_Trigger(t *testing.T) {
+	svc := NewService(time.Minute)
+	job := &mockJob{name: "idle"}
+	svc.Register(job)
+
+	if err := svc.Trigger("missing"); !errors.Is(err, ErrUnknownJob) {
+		t.Errorf("Trigger(missing) = %v, want ErrUnknownJob", err)
+	}
+	if err := svc.Trigger("idle"); err != nil {
+		t.Fatalf("Trigger failed: %v", err)
+	}
+	if !jobState(t, svc, "idle").Triggered {
+		t.Error("triggered job should be reported as triggered")
+	}
+
+	svc.checkJobs(context.Background(), time.Now())
+	if job.runCount.Load() != 1 {
+		t.Errorf("triggered job ran %d times, want 1", job.runCount.Load())
+	}
+	svc.checkJobs(context.Background(), time.Now())
+	if job.runCount.Load() != 1 {
+		t.Errorf("trigger should run the job once, ran %d times", job.runCount.Load())
+	}
+}
+
+func TestService_TriggerScrape(t *testing.T) {
+	svc := NewService(time.Minute)
+	if err := svc.TriggerScrape("202520"); !errors.Is(err, ErrUnknownJob) {
+		t.Errorf("TriggerScrape without a term scrape job = %v, want ErrUnknownJob", err)
+	}
+
+	job := &TermScrapeJob{}
+	svc = NewService(time.Minute)
+	svc.Register(job)
+	if err := svc.TriggerScrape("202520"); err != nil {
+		t.Fatalf("TriggerScrape failed: %v", err)
+	}
+	if !job.ShouldRun(time.Now()) {
+		t.Error("term scrape job should have the term queued")
+	}
+}
+
+func TestService_Pause(t *testing.T) {
+	svc := NewService(time.Minute)
+	job := &mockJob{name: "due", shouldRun: true}
+	svc.Register(job)
+
+	svc.Pause()
+	if status := svc.Status(); !status.Paused || status.PausedAt == nil {
+		t.Errorf("status = %+v, want paused", status)
+	}
+	if err := svc.Trigger("due"); !errors.Is(err, ErrPaused) {
+		t.Errorf("Trigger while paused = %v, want ErrPaused", err)
+	}
+	svc.checkJobs(context.Background(), time.Now())
+	if job.runCount.Load() != 0 {
+		t.Errorf("job ran %d times while paused", job.runCount.Load())
+	}
+
+	svc.Resume()
+	if svc.Status().Paused {
+		t.Error("still paused after Resume")
+	}
+	svc.checkJobs(context.Background(), time.Now())
+	if job.runCount.Load() != 1 {
+		t.Errorf("job ran %d times after resume, want 1", job.runCount.Load())
+	}
+}
+
+func TestService_PauseCancelsRunningJob(t *testing.T) {
+	svc := NewService(time.Hour)
+	job := &blockingJob{started: make(chan struct{}, 1)}
+	svc.Register(job)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go svc.Start(ctx)
+
+	if err := svc.Trigger("blocking"); err != nil {
+		t.Fatalf("Trigger failed: %v", err)
+	}
+	select {
+	case <-job.started:
+	case <-time.After(time.Second):
+		t.Fatal("triggered job did not start")
+	}
+	if !jobState(t, svc, "blocking").Running {
+		t.Error("job should be reported as running")
+	}
+
+	svc.Pause()
+	deadline := time.Now().Add(time.Second)
+	for jobState(t, svc, "blocking").Running {
+		if time.Now().After(deadline) {
+			t.Fatal("pause did not cancel the running job")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if state := jobState(t, svc, "blocking"); !state.LastCancelled || state.Cancellations != 1 || state.Failures != 0 || state.LastError != "" {
+		t.Errorf("state = %+v, want cancelled rather than failed", state)
+	}
+}
+
+func TestService_PauseBeforeRun(t *testing.T) {
+	svc := NewService(time.Minute)
+	job := &mockJob{name: "due", shouldRun: true}
+	svc.Register(job)
+
+	// A Pause between checkJobs' paused check and the run starting
+	svc.Pause()
+	if svc.runJob(context.Background(), job, time.Now()) {
+		t.Error("runJob ran a job after Pause")
+	}
+	if job.runCount.Load() != 0 || jobState(t, svc, "due").Runs != 0 {
+		t.Errorf("job ran %d times while paused", job.runCount.Load())
+	}
+}
+
+// gatedSource is a catalog whose Terms waits until open is closed or the
+// scrape is cancelled, so a run can be paused partway.
+type gatedSource struct {
+	scraper.Source
+	waiting chan struct{}
+	open    chan struct{}
+}
+
+func (g *gatedSource) Terms(ctx context.Context) ([]scraper.TermResponse, error) {
+	select {
+	case g.waiting <- struct{}{}:
+	default:
+	}
+	select {
+	case <-g.open:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return g.Source.Terms(ctx)
+}
+
+func newGatedScraper(t *testing.T) (*scraper.Scraper, *store.Queries, *gatedSource) {
+	t.Helper()
+	sc, queries := newCatalogScraper(t)
+	path := filepath.Join(t.TempDir(), "catalog.csv")
+	if err := os.WriteFile(path, []byte(testRunsCatalog), 0644); err != nil {
+		t.Fatalf("failed to write catalog: %v", err)
+	}
+	src, err := scraper.NewCatalogSource(path)
+	if err != nil {
+		t.Fatalf("NewCatalogSource failed: %v", err)
+	}
+	gated := &gatedSource{Source: src, waiting: make(chan struct{}, 1), open: make(chan struct{})}
+	sc.SetSource(gated)
+	return sc, queries, gated
+}
+
+// waitForRuns waits until a job has finished n runs.
+func waitForRuns(t *testing.T, svc *Service, name string, n int) JobState {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		state := jobState(t, svc, name)
+		if state.Runs >= n && !state.Running {
+			return state
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s state = %+v, want %d finished runs", name, state, n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestService_PauseRequeuesTermScrape(t *testing.T) {
+	sc, queries, gated := newGatedScraper(t)
+	job := NewTermScrapeJob(queries, sc)
+	svc := NewService(time.Hour)
+	svc.Register(job)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go svc.Start(ctx)
+
+	if err := svc.TriggerScrape("202520"); err != nil {
+		t.Fatalf("TriggerScrape failed: %v", err)
+	}
+	select {
+	case <-gated.waiting:
+	case <-time.After(time.Second):
+		t.Fatal("term scrape did not start")
+	}
+
+	svc.Pause()
+	if state := waitForRuns(t, svc, job.Name(), 1); !state.LastCancelled {
+		t.Errorf("state = %+v, want cancelled", state)
+	}
+	job.mu.Lock()
+	pending := slices.Clone(job.pending)
+	job.mu.Unlock()
+	if !slices.Equal(pending, []string{"202520"}) {
+		t.Errorf("pending = %v, want the paused term requeued", pending)
+	}
+	runs, _ := queries.GetScrapeRuns(ctx, store.GetScrapeRunsParams{Term: "202520", Limit: 10})
+	if len(runs) != 1 || runs[0].Status != RunCancelled {
+		t.Errorf("runs = %+v, want one cancelled run", runs)
+	}
+	_, err := queries.GetSectionByTermAndCRN(ctx, store.GetSectionByTermAndCRNParams{Term: "202520", Crn: "20001"})
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected nothing stored by the paused scrape, got err %v", err)
+	}
+
+	// Resuming scrapes the requeued term
+	close(gated.open)
+	svc.Resume()
+	if state := waitForRuns(t, svc, job.Name(), 2); state.LastCancelled || state.LastError != "" {
+		t.Errorf("state after resume = %+v, want a clean run", state)
+	}
+	if job.ShouldRun(time.Now()) {
+		t.Error("queue should be empty after the resumed run")
+	}
+	runs, _ = queries.GetScrapeRuns(ctx, store.GetScrapeRunsParams{Term: "202520", Limit: 10})
+	if len(runs) != 2 || runs[0].Status != RunSuccess {
+		t.Errorf("runs = %+v, want the resumed run to succeed", runs)
+	}
+}
+
+func TestService_PauseRerunsBootstrap(t *testing.T) {
+	sc, _, gated := newGatedScraper(t)
+	job := NewBootstrapJob(sc, nil)
+	svc := NewService(time.Hour)
+	svc.Register(job)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go svc.Start(ctx)
+
+	select {
+	case <-gated.waiting:
+	case <-time.After(time.Second):
+		t.Fatal("bootstrap did not start")
+	}
+
+	svc.Pause()
+	waitForRuns(t, svc, job.Name(), 1)
+	if state := jobState(t, svc, job.Name()); state.NextRunAt == nil {
+		t.Errorf("state = %+v, want the cancelled bootstrap still due", state)
+	}
+
+	close(gated.open)
+	svc.Resume()
+	waitForRuns(t, svc, job.Name(), 2)
+	if state := jobState(t, svc, job.Name()); state.NextRunAt != nil || state.LastCancelled {
+		t.Errorf("state after resume = %+v, want bootstrap finished", state)
+	}
+}
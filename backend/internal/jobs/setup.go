@@ -2,6 +2,8 @@ package jobs
 
 import (
 	"context"
+	"database/sql"
+	"log/slog"
 	"time"
 
 	"schedule-optimizer/internal/config"
@@ -12,22 +14,45 @@ import (
 
 // Setup creates and starts the jobs service if enabled in config.
 // Returns nil if jobs are disabled. The context controls job lifecycle.
-func Setup(ctx context.Context, cfg *config.Config, queries *store.Queries, gradeService *grades.Service) *Service {
+// Listeners are notified after each successful term scrape, and those that
+// are also DetailListeners after course details are stored.
+func Setup(ctx context.Context, cfg *config.Config, db *sql.DB, queries *store.Queries, gradeService *grades.Service, listeners ...ScrapeListener) *Service {
 	if !cfg.JobsEnabled {
 		return nil
 	}
 
-	sc, err := scraper.NewScraper(queries, cfg.ScraperConcurrency)
+	sc, err := scraper.NewScraper(db, queries, cfg.ScraperConcurrency, cfg.ScraperRequestsPerMinute)
 	if err != nil {
 		// Log and continue without jobs rather than crashing
 		return nil
 	}
+	transport, err := scraper.NewFixtureTransport(cfg.BannerMode, cfg.BannerFixturesDir)
+	if err != nil {
+		slog.Error("Jobs disabled: invalid Banner mode", "error", err)
+		return nil
+	}
+	sc.SetTransport(transport)
+	if cfg.CatalogSourcePath != "" {
+		catalog, err := scraper.NewCatalogSource(cfg.CatalogSourcePath)
+		if err != nil {
+			slog.Error("Jobs disabled: failed to load catalog source", "error", err)
+			return nil
+		}
+		sc.SetSource(catalog)
+	}
+
+	failUnfinishedRuns(ctx, queries)
 
 	gradeImportJob := NewGradeImportJob(gradeService)
 
 	pastTermJob := NewPastTermBackfillJob(queries, sc, cfg.PastTermYears)
 	activeJob := NewActiveScrapeJob(queries, sc, cfg.PastTermYears, cfg.ActiveScrapeHours)
 	dailyJob := NewDailyScrapeJob(queries, sc, cfg.PastTermYears, cfg.DailyScrapeHour)
+	termJob := NewTermScrapeJob(queries, sc)
+	pastTermJob.listeners = listeners
+	activeJob.listeners = listeners
+	dailyJob.listeners = listeners
+	termJob.listeners = listeners
 	bootstrapJob := NewBootstrapJob(sc, []Job{pastTermJob, activeJob, dailyJob})
 
 	service := NewService(time.Minute)
@@ -36,6 +61,16 @@ func Setup(ctx context.Context, cfg *config.Config, queries *store.Queries, grad
 	service.Register(pastTermJob)
 	service.Register(activeJob)
 	service.Register(dailyJob)
+	service.Register(termJob)
+	if cfg.ScraperDetails && cfg.CatalogSourcePath == "" {
+		detailJob := NewDetailScrapeJob(queries, sc, cfg.PastTermYears, cfg.ScraperDetailsBatch, time.Duration(cfg.ScraperDetailsMaxAge)*time.Hour)
+		for _, l := range listeners {
+			if dl, ok := l.(DetailListener); ok {
+				detailJob.listeners = append(detailJob.listeners, dl)
+			}
+		}
+		service.Register(detailJob)
+	}
 
 	go service.Start(ctx)
 
@@ -0,0 +1,76 @@
+package prereq
+
+// Status is whether an expression is met. Statuses are ordered, so AND takes
+// the lowest of its arguments and OR the highest.
+type Status int
+
+const (
+	Unmet   Status = iota // Some required course isn't completed
+	Unknown               // Depends on requirements that aren't courses
+	Met
+)
+
+// Eval checks the expression against completed courses, keyed like
+// NormalizeCourse. A nil expression has no requirements and is met. Text
+// nodes, such as instructor permission, can't be checked and are Unknown.
+func (n *Node) Eval(completed map[string]bool) Status {
+	switch {
+	case n == nil:
+		return Met
+	case n.Course != "":
+		if completed[n.Course] {
+			return Met
+		}
+		return Unmet
+	case n.Op == OpAnd:
+		status := Met
+		for _, arg := range n.Args {
+			status = min(status, arg.Eval(completed))
+		}
+		return status
+	case n.Op == OpOr:
+		status := Unmet
+		for _, arg := range n.Args {
+			status = max(status, arg.Eval(completed))
+		}
+		return status
+	}
+	return Unknown
+}
+
+// Missing returns courses that would meet the expression once completed:
+// the union of what each unmet AND argument is missing, and for an unmet OR
+// only the alternative missing the fewest courses (the first on a tie), so
+// unchosen alternatives aren't listed. Requirements that aren't courses add
+// nothing. Met expressions are missing nothing.
+func (n *Node) Missing(completed map[string]bool) []string {
+	if n.Eval(completed) == Met {
+		return nil
+	}
+	switch {
+	case n.Course != "":
+		return []string{n.Course}
+	case n.Op == OpAnd:
+		var missing []string
+		seen := make(map[string]bool)
+		for _, arg := range n.Args {
+			for _, course := range arg.Missing(completed) {
+				if !seen[course] {
+					seen[course] = true
+					missing = append(missing, course)
+				}
+			}
+		}
+		return missing
+	case n.Op == OpOr:
+		var best []string
+		for i, arg := range n.Args {
+			missing := arg.Missing(completed)
+			if i == 0 || len(missing) < len(best) {
+				best = missing
+			}
+		}
+		return best
+	}
+	return nil
+}
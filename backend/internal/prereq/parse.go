@@ -0,0 +1,359 @@
+// Package prereq parses registrar prerequisite text into AND/OR course
+// expressions and checks them against the courses a student has completed.
+package prereq
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidCourse is returned for a completed course that isn't a course
+// code such as "CSCI 145".
+var ErrInvalidCourse = errors.New("invalid course code")
+
+// Expression operators.
+const (
+	OpAnd = "and"
+	OpOr  = "or"
+)
+
+// Node is a prerequisite expression: an AND or OR of other nodes, a course,
+// or a requirement that isn't a course, such as instructor permission.
+// Exactly one of Op, Course, and Text is set.
+type Node struct {
+	Op     string  `json:"op,omitempty"`
+	Args   []*Node `json:"args,omitempty"`
+	Course string  `json:"course,omitempty"` // "CSCI 145"
+	Text   string  `json:"text,omitempty"`
+}
+
+// Courses returns the courses named in the expression, in order, without
+// duplicates.
+func (n *Node) Courses() []string {
+	var courses []string
+	seen := make(map[string]bool)
+	var walk func(*Node)
+	walk = func(n *Node) {
+		switch {
+		case n.Course != "":
+			if !seen[n.Course] {
+				seen[n.Course] = true
+				courses = append(courses, n.Course)
+			}
+		case n.Op != "":
+			for _, arg := range n.Args {
+				walk(arg)
+			}
+		}
+	}
+	walk(n)
+	return courses
+}
+
+var (
+	courseCodePattern   = regexp.MustCompile(`^([A-Z][A-Z&/]{0,4})[\s:-]*(\d{3}[A-Z]?)$`)
+	courseNumberPattern = regexp.MustCompile(`^\d{3}[A-Z]?$`)
+	joinedCoursePattern = regexp.MustCompile(`^([A-Z][A-Z&/]{0,4})(\d{3}[A-Z]?)$`)
+	wordPattern         = regexp.MustCompile(`[()\[\]\n,;]|[^\s()\[\]\n,;]+`)
+	hasLetter           = regexp.MustCompile(`[A-Za-z]`)
+
+	// noise is registrar boilerplate that says nothing about which courses
+	// are required: headings, grade minimums, and course levels.
+	noise = regexp.MustCompile(`(?i)\bcatalog\s+prerequisites?\b|\bprerequisites?\s*(\(s\))?\s*:|` +
+		`\bcourse\s+or\s+test\s*:|\bminimum\s+grade\s+of\s+\S+|\bor\s+(better|higher|above)\b|` +
+		`\b(under)?graduate\s+level\b|\bmay(\s+not)?\s+be\s+taken\s+concurrently\.?`)
+)
+
+// NormalizeCourse returns a course code in the form used in expressions,
+// "CSCI 145", accepting forms such as "csci145" and "CSCI:145".
+func NormalizeCourse(s string) (string, error) {
+	m := courseCodePattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+	if m == nil {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCourse, s)
+	}
+	return m[1] + " " + m[2], nil
+}
+
+type tokenKind int
+
+const (
+	tokOpen tokenKind = iota
+	tokClose
+	tokSep // Comma, semicolon, or line break; joins like the next operator
+	tokAnd
+	tokOr
+	tokCourse
+	tokText
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+// Parser turns prerequisite text into expressions. Courses are recognized by
+// subject code ("CSCI 145") or by subject description ("Computer Science
+// 145"), which is how Banner's prerequisite tables name them.
+type Parser struct {
+	codes    map[string]bool   // Subject codes
+	names    map[string]string // Lower-cased subject description to code
+	maxWords int               // Words in the longest description
+}
+
+// ParserVersion is stored with each term's graph (prerequisite_builds).
+// Bump it when Parse's output changes, so stored graphs are rebuilt on
+// startup.
+const ParserVersion = 1
+
+// NewParser creates a parser that knows the given subjects, keyed by code
+// with their descriptions as values.
+func NewParser(subjects map[string]string) *Parser {
+	p := &Parser{codes: make(map[string]bool), names: make(map[string]string)}
+	for code, desc := range subjects {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code == "" {
+			continue
+		}
+		p.codes[code] = true
+		words := strings.Fields(strings.ToLower(desc))
+		if len(words) == 0 {
+			continue
+		}
+		p.names[strings.Join(words, " ")] = code
+		p.maxWords = max(p.maxWords, len(words))
+	}
+	return p
+}
+
+// Parse returns the expression for prerequisite text, or nil if the text
+// names no requirements.
+//
+// AND binds tighter than OR, and parentheses group. A comma, semicolon, or
+// line break joins like the next explicit operator at the same depth, so
+// "CSCI 141, 145, or 241" is an OR of three courses; with no operator after
+// it, it's an AND. A bare course number following another course takes that
+// course's subject. Grades and other qualifiers next to a course are dropped,
+// and any other text becomes a Text node.
+func (p *Parser) Parse(text string) *Node {
+	ps := &parseState{tokens: p.tokenize(text)}
+	return simplify(ps.list(0))
+}
+
+// tokenize splits text into tokens, merging runs of plain words into one
+// text token.
+func (p *Parser) tokenize(text string) []token {
+	text = noise.ReplaceAllString(text, " ")
+	var words []string
+	for _, w := range wordPattern.FindAllString(text, -1) {
+		if len(w) > 1 {
+			w = strings.TrimRight(w, ".:")
+		}
+		if w != "" {
+			words = append(words, w)
+		}
+	}
+
+	var tokens []token
+	var subject string // Subject of the last course, for bare course numbers
+	last := func() tokenKind {
+		if len(tokens) == 0 {
+			return tokOpen
+		}
+		return tokens[len(tokens)-1].kind
+	}
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		switch lower := strings.ToLower(w); {
+		case w == "(" || w == "[":
+			tokens = append(tokens, token{kind: tokOpen})
+			continue
+		case w == ")" || w == "]":
+			tokens = append(tokens, token{kind: tokClose})
+			continue
+		case w == "\n" || w == "," || w == ";":
+			tokens = append(tokens, token{kind: tokSep})
+			continue
+		case lower == "and" || lower == "&":
+			tokens = append(tokens, token{kind: tokAnd})
+			continue
+		case lower == "or" || lower == "and/or":
+			tokens = append(tokens, token{kind: tokOr})
+			continue
+		}
+
+		if code, n := p.subjectAt(words, i); n > 0 && i+n < len(words) && courseNumberPattern.MatchString(strings.ToUpper(words[i+n])) {
+			subject = code
+			tokens = append(tokens, token{kind: tokCourse, value: code + " " + strings.ToUpper(words[i+n])})
+			i += n
+			continue
+		}
+		if m := joinedCoursePattern.FindStringSubmatch(w); m != nil && p.codes[m[1]] {
+			subject = m[1]
+			tokens = append(tokens, token{kind: tokCourse, value: m[1] + " " + m[2]})
+			continue
+		}
+		if subject != "" && courseNumberPattern.MatchString(strings.ToUpper(w)) && last() != tokText {
+			tokens = append(tokens, token{kind: tokCourse, value: subject + " " + strings.ToUpper(w)})
+			continue
+		}
+
+		if last() == tokText && len(tokens) > 0 {
+			tokens[len(tokens)-1].value += " " + w
+		} else {
+			tokens = append(tokens, token{kind: tokText, value: w})
+		}
+	}
+
+	// Text directly before or after a course qualifies it ("C-", "Minimum
+	// Grade of C-"), and text without letters is punctuation
+	var kept []token
+	for i, t := range tokens {
+		if t.kind == tokText {
+			if !hasLetter.MatchString(t.value) ||
+				(i > 0 && tokens[i-1].kind == tokCourse) ||
+				(i+1 < len(tokens) && tokens[i+1].kind == tokCourse) {
+				continue
+			}
+		}
+		kept = append(kept, t)
+	}
+	return kept
+}
+
+// subjectAt reports the subject named at words[i] and how many words name
+// it. Codes must be upper case so words like "Art" aren't taken for codes;
+// descriptions match in any case, longest first.
+func (p *Parser) subjectAt(words []string, i int) (string, int) {
+	for n := min(p.maxWords, len(words)-i); n > 0; n-- {
+		name := strings.ToLower(strings.Join(words[i:i+n], " "))
+		if code, ok := p.names[name]; ok {
+			return code, n
+		}
+	}
+	if p.codes[words[i]] {
+		return words[i], 1
+	}
+	return "", 0
+}
+
+type parseState struct {
+	tokens []token
+	pos    int
+}
+
+// list parses items and the operators between them until the closing
+// parenthesis for depth, or the end of input.
+func (ps *parseState) list(depth int) *Node {
+	var items []*Node
+	var ops []tokenKind // ops[i] joins items[i] and items[i+1]
+	pending := tokSep   // Operator seen since the last item
+	explicit := false
+
+	for ps.pos < len(ps.tokens) {
+		t := ps.tokens[ps.pos]
+		ps.pos++
+
+		var item *Node
+		switch t.kind {
+		case tokClose:
+			if depth > 0 {
+				return joinItems(items, ops)
+			}
+			continue // Unbalanced; ignore it
+		case tokSep:
+			continue
+		case tokAnd, tokOr:
+			pending, explicit = t.kind, true
+			continue
+		case tokOpen:
+			item = ps.list(depth + 1)
+		case tokCourse:
+			item = &Node{Course: t.value}
+		case tokText:
+			item = &Node{Text: t.value}
+		}
+		if item == nil {
+			continue
+		}
+		if len(items) > 0 {
+			if !explicit {
+				pending = tokSep
+			}
+			ops = append(ops, pending)
+		}
+		items = append(items, item)
+		pending, explicit = tokSep, false
+	}
+	return joinItems(items, ops)
+}
+
+// joinItems combines items with the operators between them, resolving
+// separators and grouping ANDs before ORs.
+func joinItems(items []*Node, ops []tokenKind) *Node {
+	if len(items) == 0 {
+		return nil
+	}
+	for i, op := range ops {
+		if op != tokSep {
+			continue
+		}
+		ops[i] = tokAnd
+		for _, next := range ops[i+1:] {
+			if next != tokSep {
+				ops[i] = next
+				break
+			}
+		}
+	}
+
+	or := &Node{Op: OpOr}
+	and := &Node{Op: OpAnd, Args: []*Node{items[0]}}
+	for i, op := range ops {
+		if op == tokOr {
+			or.Args = append(or.Args, and)
+			and = &Node{Op: OpAnd}
+		}
+		and.Args = append(and.Args, items[i+1])
+	}
+	or.Args = append(or.Args, and)
+	return or
+}
+
+// simplify flattens nested operators of the same kind, unwraps operators with
+// one argument, and drops duplicate arguments.
+func simplify(n *Node) *Node {
+	if n == nil || n.Op == "" {
+		return n
+	}
+	var flat []*Node
+	for _, arg := range n.Args {
+		arg = simplify(arg)
+		switch {
+		case arg == nil:
+		case arg.Op == n.Op:
+			flat = append(flat, arg.Args...)
+		default:
+			flat = append(flat, arg)
+		}
+	}
+	var args []*Node
+	seen := make(map[string]bool)
+	for _, arg := range flat {
+		if arg.Course != "" {
+			if seen[arg.Course] {
+				continue
+			}
+			seen[arg.Course] = true
+		}
+		args = append(args, arg)
+	}
+	switch len(args) {
+	case 0:
+		return nil
+	case 1:
+		return args[0]
+	}
+	return &Node{Op: n.Op, Args: args}
+}
@@ -0,0 +1,169 @@
+package prereq
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+var testSubjects = map[string]string{
+	"CSCI": "Computer Science",
+	"MATH": "Mathematics",
+	"ENG":  "English",
+	"ART":  "Art",
+}
+
+// expr renders an expression compactly for comparison.
+func expr(n *Node) string {
+	if n == nil {
+		return "<nil>"
+	}
+	b, _ := json.Marshal(n)
+	return string(b)
+}
+
+func TestParse(t *testing.T) {
+	p := NewParser(testSubjects)
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "single course",
+			text: "CSCI 141",
+			want: `{"course":"CSCI 141"}`,
+		},
+		{
+			name: "banner table",
+			text: "Catalog Prerequisites\n( Computer Science 145 C-\nOr Computer Science 141 C- )\nAnd Mathematics 124 C-",
+			want: `{"op":"and","args":[{"op":"or","args":[{"course":"CSCI 145"},{"course":"CSCI 141"}]},{"course":"MATH 124"}]}`,
+		},
+		{
+			name: "and binds tighter than or",
+			text: "CSCI 141 and MATH 114 or CSCI 145",
+			want: `{"op":"or","args":[{"op":"and","args":[{"course":"CSCI 141"},{"course":"MATH 114"}]},{"course":"CSCI 145"}]}`,
+		},
+		{
+			name: "comma list takes the next operator",
+			text: "Prerequisites: CSCI 141, 145, or 241.",
+			want: `{"op":"or","args":[{"course":"CSCI 141"},{"course":"CSCI 145"},{"course":"CSCI 241"}]}`,
+		},
+		{
+			name: "comma list without operator",
+			text: "CSCI 141, MATH 124",
+			want: `{"op":"and","args":[{"course":"CSCI 141"},{"course":"MATH 124"}]}`,
+		},
+		{
+			name: "permission alternative",
+			text: "CSCI 241 with a C- or better; or instructor permission.",
+			want: `{"op":"or","args":[{"course":"CSCI 241"},{"text":"instructor permission"}]}`,
+		},
+		{
+			name: "old banner format",
+			text: "Course or Test: Undergraduate level Computer Science 241 Minimum Grade of C-\nMay not be taken concurrently.",
+			want: `{"course":"CSCI 241"}`,
+		},
+		{
+			name: "joined code",
+			text: "ENG101 or ART 109",
+			want: `{"op":"or","args":[{"course":"ENG 101"},{"course":"ART 109"}]}`,
+		},
+		{
+			name: "lower-case code word isn't a subject",
+			text: "eng 101",
+			want: `{"text":"eng 101"}`,
+		},
+		{
+			name: "unbalanced parentheses",
+			text: "( CSCI 141 or CSCI 145 ) ) and MATH 124",
+			want: `{"op":"and","args":[{"op":"or","args":[{"course":"CSCI 141"},{"course":"CSCI 145"}]},{"course":"MATH 124"}]}`,
+		},
+		{
+			name: "duplicates",
+			text: "CSCI 141 or CSCI 141",
+			want: `{"course":"CSCI 141"}`,
+		},
+		{
+			name: "heading only",
+			text: "Catalog Prerequisites",
+			want: "<nil>",
+		},
+		{
+			name: "empty",
+			text: "",
+			want: "<nil>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expr(p.Parse(tt.text)); got != tt.want {
+				t.Errorf("Parse(%q)\n got %s\nwant %s", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEval(t *testing.T) {
+	p := NewParser(testSubjects)
+	completed := map[string]bool{"CSCI 141": true, "MATH 124": true}
+	tests := []struct {
+		text string
+		want Status
+	}{
+		{"", Met},
+		{"CSCI 141", Met},
+		{"CSCI 241", Unmet},
+		{"CSCI 141 and MATH 124", Met},
+		{"CSCI 141 and CSCI 145", Unmet},
+		{"CSCI 145 or CSCI 141", Met},
+		{"CSCI 241 or instructor permission", Unknown},
+		{"CSCI 141 or instructor permission", Met},
+		{"CSCI 141 and instructor permission", Unknown},
+		{"CSCI 241 and instructor permission", Unmet},
+	}
+	for _, tt := range tests {
+		if got := p.Parse(tt.text).Eval(completed); got != tt.want {
+			t.Errorf("Eval(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+
+	missingTests := []struct {
+		text string
+		want []string
+	}{
+		// A met alternative leaves nothing missing from its OR
+		{"(CSCI 141 or CSCI 145) and MATH 204", []string{"MATH 204"}},
+		// Otherwise only the alternative closest to being met is listed
+		{"(CSCI 241 and CSCI 247) or CSCI 301", []string{"CSCI 301"}},
+		{"CSCI 241 or (CSCI 141 and CSCI 247)", []string{"CSCI 241"}},
+		{"(CSCI 241 and CSCI 247) or (CSCI 141 and CSCI 301)", []string{"CSCI 301"}},
+		{"(CSCI 241 or instructor permission) and MATH 204", []string{"MATH 204"}},
+		{"CSCI 241 and (CSCI 241 or CSCI 247)", []string{"CSCI 241"}},
+		{"CSCI 141", nil},
+	}
+	for _, tt := range missingTests {
+		if got := p.Parse(tt.text).Missing(completed); !slices.Equal(got, tt.want) {
+			t.Errorf("Missing(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeCourse(t *testing.T) {
+	for in, want := range map[string]string{
+		"CSCI 145":  "CSCI 145",
+		" csci145 ": "CSCI 145",
+		"CSCI:145":  "CSCI 145",
+		"math 112a": "MATH 112A",
+	} {
+		if got, err := NormalizeCourse(in); err != nil || got != want {
+			t.Errorf("NormalizeCourse(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "CSCI", "145", "Computer Science 145"} {
+		if _, err := NormalizeCourse(in); !errors.Is(err, ErrInvalidCourse) {
+			t.Errorf("NormalizeCourse(%q) error = %v, want ErrInvalidCourse", in, err)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package prereq
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"schedule-optimizer/internal/store"
+)
+
+// ErrTooManyCourses is returned when more completed courses are given than
+// MaxCompletedCourses.
+var ErrTooManyCourses = fmt.Errorf("too many completed courses (limit %d)", MaxCompletedCourses)
+
+const (
+	MaxCompletedCourses = 300
+
+	rebuildTimeout = 2 * time.Minute
+)
+
+// Service keeps each term's prerequisite graph, parsed from the prerequisite
+// text in course_details, and answers which courses a student can take.
+type Service struct {
+	db      *sql.DB
+	queries *store.Queries
+
+	mu sync.Mutex // Serializes rebuilds
+}
+
+// NewService creates a new prerequisite service.
+func NewService(db *sql.DB, queries *store.Queries) *Service {
+	return &Service{db: db, queries: queries}
+}
+
+// TermScraped implements jobs.ScrapeListener. The term's graph is rebuilt in
+// the background, since subject descriptions the parser matches may change.
+func (s *Service) TermScraped(term string) {
+	s.rebuildAsync(term)
+}
+
+// DetailsScraped implements jobs.DetailListener. The term's graph is rebuilt
+// in the background from the newly stored prerequisite text.
+func (s *Service) DetailsScraped(term string) {
+	s.rebuildAsync(term)
+}
+
+func (s *Service) rebuildAsync(term string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), rebuildTimeout)
+		defer cancel()
+		if _, err := s.Rebuild(ctx, term); err != nil {
+			slog.Error("Failed to rebuild prerequisite graph", "term", term, "error", err)
+		}
+	}()
+}
+
+// Rebuild parses the prerequisite text of every course in term and replaces
+// the term's stored graph. Returns how many courses have prerequisites.
+func (s *Service) Rebuild(ctx context.Context, term string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subjects, err := s.queries.GetSubjectDescriptions(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("load subjects: %w", err)
+	}
+	descriptions := make(map[string]string, len(subjects))
+	for _, row := range subjects {
+		if _, ok := descriptions[row.Subject]; !ok || row.SubjectDescription.Valid {
+			descriptions[row.Subject] = row.SubjectDescription.String
+		}
+	}
+	parser := NewParser(descriptions)
+
+	rows, err := s.queries.GetCoursePrerequisiteTexts(ctx, term)
+	if err != nil {
+		return 0, fmt.Errorf("load prerequisite text: %w", err)
+	}
+
+	var courses int
+	err = store.ExecTx(ctx, s.db, func(queries *store.Queries) error {
+		if err := queries.DeleteCoursePrerequisites(ctx, term); err != nil {
+			return err
+		}
+		if err := queries.DeletePrerequisiteEdges(ctx, term); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			node := parser.Parse(row.Prerequisites.String)
+			if node == nil {
+				continue
+			}
+			expression, err := json.Marshal(node)
+			if err != nil {
+				return err
+			}
+			if err := queries.InsertCoursePrerequisite(ctx, store.InsertCoursePrerequisiteParams{
+				Term:         term,
+				Subject:      row.Subject,
+				CourseNumber: row.CourseNumber,
+				Expression:   string(expression),
+			}); err != nil {
+				return fmt.Errorf("insert prerequisites for %s %s: %w", row.Subject, row.CourseNumber, err)
+			}
+			for _, course := range node.Courses() {
+				subject, number, _ := strings.Cut(course, " ")
+				if err := queries.InsertPrerequisiteEdge(ctx, store.InsertPrerequisiteEdgeParams{
+					Term:               term,
+					Subject:            row.Subject,
+					CourseNumber:       row.CourseNumber,
+					PrereqSubject:      subject,
+					PrereqCourseNumber: number,
+				}); err != nil {
+					return fmt.Errorf("insert edge %s %s-%s: %w", row.Subject, row.CourseNumber, course, err)
+				}
+			}
+			courses++
+		}
+		return queries.UpsertPrerequisiteBuild(ctx, store.UpsertPrerequisiteBuildParams{
+			Term:          term,
+			ParserVersion: ParserVersion,
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	slog.Info("Rebuilt prerequisite graph", "term", term, "courses", courses)
+	return courses, nil
+}
+
+// RebuildStale rebuilds the graphs of terms with details that were never
+// built or were built by an older ParserVersion, and returns how many were
+// rebuilt. Called on startup; reads only use stored graphs.
+func (s *Service) RebuildStale(ctx context.Context) (int, error) {
+	terms, err := s.queries.GetStalePrerequisiteTerms(ctx, ParserVersion)
+	if err != nil {
+		return 0, fmt.Errorf("load stale terms: %w", err)
+	}
+	for i, term := range terms {
+		if _, err := s.Rebuild(ctx, term); err != nil {
+			return i, fmt.Errorf("rebuild %s: %w", term, err)
+		}
+	}
+	return len(terms), nil
+}
+
+// graph returns the term's prerequisite expressions, keyed like
+// NormalizeCourse.
+func (s *Service) graph(ctx context.Context, term string) (map[string]*Node, error) {
+	rows, err := s.queries.GetCoursePrerequisites(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+	graph := make(map[string]*Node, len(rows))
+	for _, row := range rows {
+		var node Node
+		if err := json.Unmarshal([]byte(row.Expression), &node); err != nil {
+			slog.Warn("Skipping unreadable prerequisites", "term", term, "subject", row.Subject, "courseNumber", row.CourseNumber, "error", err)
+			continue
+		}
+		graph[row.Subject+" "+row.CourseNumber] = &node
+	}
+	return graph, nil
+}
+
+// completedSet normalizes completed course codes.
+func completedSet(completed []string) (map[string]bool, error) {
+	if len(completed) > MaxCompletedCourses {
+		return nil, ErrTooManyCourses
+	}
+	set := make(map[string]bool, len(completed))
+	for _, c := range completed {
+		course, err := NormalizeCourse(c)
+		if err != nil {
+			return nil, err
+		}
+		set[course] = true
+	}
+	return set, nil
+}
+
+// Eligible sorts the courses offered in term by whether completed meets
+// their prerequisites. Completed courses are left out.
+func (s *Service) Eligible(ctx context.Context, term string, completed []string) (*Eligibility, error) {
+	done, err := completedSet(completed)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := s.graph(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+	fetched, err := s.queries.GetCourseDetailsFetchedAt(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+	hasDetails := make(map[string]bool, len(fetched))
+	for _, row := range fetched {
+		hasDetails[row.Subject+" "+row.CourseNumber] = true
+	}
+	offered, err := s.queries.GetTermCourses(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Eligibility{
+		Term:       term,
+		Eligible:   []Course{},
+		Unverified: []Course{},
+		Ineligible: []Course{},
+	}
+	for _, row := range offered {
+		key := row.Subject + " " + row.CourseNumber
+		if done[key] {
+			continue
+		}
+		node := graph[key]
+		course := Course{
+			Subject:       row.Subject,
+			CourseNumber:  row.CourseNumber,
+			Title:         row.Title,
+			Prerequisites: node,
+		}
+
+		switch status := node.Eval(done); {
+		case !hasDetails[key]:
+			result.Unverified = append(result.Unverified, course)
+		case status == Met:
+			result.Eligible = append(result.Eligible, course)
+		case status == Unknown:
+			result.Unverified = append(result.Unverified, course)
+		default:
+			course.Missing = node.Missing(done)
+			result.Ineligible = append(result.Ineligible, course)
+		}
+	}
+	return result, nil
+}
+
+// Unmet returns the term's courses whose prerequisites completed doesn't
+// meet, keyed like NormalizeCourse. Courses whose prerequisites can't be
+// checked aren't included.
+func (s *Service) Unmet(ctx context.Context, term string, completed []string) (map[string]bool, error) {
+	done, err := completedSet(completed)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := s.graph(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+	unmet := make(map[string]bool)
+	for course, node := range graph {
+		if node.Eval(done) == Unmet {
+			unmet[course] = true
+		}
+	}
+	return unmet, nil
+}
+
+// Course returns a course's prerequisites in term and the courses that name
+// it as a prerequisite.
+func (s *Service) Course(ctx context.Context, term, subject, courseNumber string) (*CoursePrerequisites, error) {
+	result := &CoursePrerequisites{Term: term, Subject: subject, CourseNumber: courseNumber, Unlocks: []string{}}
+
+	expression, err := s.queries.GetCoursePrerequisite(ctx, store.GetCoursePrerequisiteParams{
+		Term:         term,
+		Subject:      subject,
+		CourseNumber: courseNumber,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err == nil {
+		var node Node
+		if err := json.Unmarshal([]byte(expression), &node); err != nil {
+			return nil, err
+		}
+		result.Prerequisites = &node
+	}
+
+	dependents, err := s.queries.GetPrerequisiteDependents(ctx, store.GetPrerequisiteDependentsParams{
+		Term:               term,
+		PrereqSubject:      subject,
+		PrereqCourseNumber: courseNumber,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range dependents {
+		result.Unlocks = append(result.Unlocks, row.Subject+" "+row.CourseNumber)
+	}
+	return result, nil
+}
@@ -0,0 +1,151 @@
+package prereq
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"schedule-optimizer/internal/store"
+	"schedule-optimizer/internal/testutil"
+)
+
+// newTestService seeds prerequisites for Spring 2025: CSCI 247 needs CSCI 145
+// (named by description, as in Banner's tables), CSCI 301 needs CSCI 247 and
+// MATH 204, and MATH 204 has none. The graph is built as on startup.
+func newTestService(t *testing.T) (*Service, *store.Queries) {
+	t.Helper()
+	db, queries := testutil.SetupTestDB(t)
+	testutil.SeedTestData(t, db)
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`UPDATE sections SET subject_description = 'Computer Science' WHERE subject = 'CSCI'`); err != nil {
+		t.Fatalf("failed to set subject descriptions: %v", err)
+	}
+	ctx := context.Background()
+	for _, details := range []store.UpsertCourseDetailsParams{
+		{Term: "202520", Subject: "CSCI", CourseNumber: "247", Prerequisites: sql.NullString{String: "Catalog Prerequisites\nComputer Science 145 C-", Valid: true}},
+		{Term: "202520", Subject: "CSCI", CourseNumber: "301", Prerequisites: sql.NullString{String: "CSCI 247 and MATH 204", Valid: true}},
+		{Term: "202520", Subject: "MATH", CourseNumber: "204"},
+	} {
+		if err := queries.UpsertCourseDetails(ctx, details); err != nil {
+			t.Fatalf("UpsertCourseDetails failed: %v", err)
+		}
+	}
+	s := NewService(db, queries)
+	if _, err := s.RebuildStale(ctx); err != nil {
+		t.Fatalf("RebuildStale failed: %v", err)
+	}
+	return s, queries
+}
+
+func courseKeys(courses []Course) []string {
+	keys := []string{}
+	for _, c := range courses {
+		keys = append(keys, c.Subject+" "+c.CourseNumber)
+	}
+	return keys
+}
+
+func TestRebuild(t *testing.T) {
+	s, queries := newTestService(t)
+	ctx := context.Background()
+
+	n, err := s.Rebuild(ctx, "202520")
+	if err != nil {
+		t.Fatalf("Rebuild failed: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("courses with prerequisites = %d, want 2", n)
+	}
+	rows, _ := queries.GetCoursePrerequisites(ctx, "202520")
+	if len(rows) != 2 || rows[0].Expression != `{"course":"CSCI 145"}` {
+		t.Errorf("stored prerequisites = %+v", rows)
+	}
+
+	// Rebuilding replaces the term's graph rather than adding to it
+	if _, err := s.Rebuild(ctx, "202520"); err != nil {
+		t.Fatalf("second Rebuild failed: %v", err)
+	}
+	course, err := s.Course(ctx, "202520", "MATH", "204")
+	if err != nil {
+		t.Fatalf("Course failed: %v", err)
+	}
+	if course.Prerequisites != nil || !reflect.DeepEqual(course.Unlocks, []string{"CSCI 301"}) {
+		t.Errorf("MATH 204 = %+v, want no prerequisites, unlocks CSCI 301", course)
+	}
+}
+
+func TestRebuildStale(t *testing.T) {
+	s, queries := newTestService(t)
+	ctx := context.Background()
+
+	// Built terms are left alone until the parser version changes
+	if n, err := s.RebuildStale(ctx); err != nil || n != 0 {
+		t.Errorf("RebuildStale = %d, %v; want nothing rebuilt", n, err)
+	}
+	if err := queries.UpsertPrerequisiteBuild(ctx, store.UpsertPrerequisiteBuildParams{Term: "202520", ParserVersion: ParserVersion - 1}); err != nil {
+		t.Fatalf("UpsertPrerequisiteBuild failed: %v", err)
+	}
+	if n, err := s.RebuildStale(ctx); err != nil || n != 1 {
+		t.Errorf("RebuildStale = %d, %v; want the older build rebuilt", n, err)
+	}
+	if terms, _ := queries.GetStalePrerequisiteTerms(ctx, ParserVersion); len(terms) != 0 {
+		t.Errorf("stale terms after rebuilding = %v", terms)
+	}
+}
+
+func TestEligible(t *testing.T) {
+	s, _ := newTestService(t)
+	ctx := context.Background()
+
+	result, err := s.Eligible(ctx, "202520", []string{"csci145"})
+	if err != nil {
+		t.Fatalf("Eligible failed: %v", err)
+	}
+	if got := courseKeys(result.Eligible); !reflect.DeepEqual(got, []string{"CSCI 247", "MATH 204"}) {
+		t.Errorf("eligible = %v", got)
+	}
+	if len(result.Ineligible) != 1 || !reflect.DeepEqual(result.Ineligible[0].Missing, []string{"CSCI 247", "MATH 204"}) {
+		t.Errorf("ineligible = %+v, want CSCI 301 missing CSCI 247 and MATH 204", result.Ineligible)
+	}
+
+	// Completed courses are left out, and unlock the courses after them
+	result, err = s.Eligible(ctx, "202520", []string{"CSCI 145", "CSCI 247", "MATH 204"})
+	if err != nil {
+		t.Fatalf("Eligible failed: %v", err)
+	}
+	if got := courseKeys(result.Eligible); !reflect.DeepEqual(got, []string{"CSCI 301"}) {
+		t.Errorf("eligible = %v, want [CSCI 301]", got)
+	}
+
+	if _, err := s.Eligible(ctx, "202520", []string{"not a course"}); !errors.Is(err, ErrInvalidCourse) {
+		t.Errorf("invalid course error = %v, want ErrInvalidCourse", err)
+	}
+}
+
+func TestEligible_NoDetails(t *testing.T) {
+	s, _ := newTestService(t)
+
+	// Winter 2025 has no scraped details, so nothing can be checked
+	result, err := s.Eligible(context.Background(), "202510", nil)
+	if err != nil {
+		t.Fatalf("Eligible failed: %v", err)
+	}
+	if len(result.Eligible) != 0 || len(result.Ineligible) != 0 || len(result.Unverified) != 1 {
+		t.Errorf("result = %+v, want one unverified course", result)
+	}
+}
+
+func TestUnmet(t *testing.T) {
+	s, _ := newTestService(t)
+
+	unmet, err := s.Unmet(context.Background(), "202520", []string{"CSCI 145"})
+	if err != nil {
+		t.Fatalf("Unmet failed: %v", err)
+	}
+	if !reflect.DeepEqual(unmet, map[string]bool{"CSCI 301": true}) {
+		t.Errorf("unmet = %v, want CSCI 301", unmet)
+	}
+}
@@ -0,0 +1,28 @@
+package prereq
+
+// Eligibility sorts a term's courses by whether a student's completed courses
+// meet their prerequisites.
+type Eligibility struct {
+	Term       string   `json:"term"`
+	Eligible   []Course `json:"eligible"`   // Prerequisites met, or none
+	Unverified []Course `json:"unverified"` // Hinges on permission, tests, or other non-course requirements, or details not scraped yet
+	Ineligible []Course `json:"ineligible"` // Missing required courses
+}
+
+// Course is a course offered in the term, with its prerequisites.
+type Course struct {
+	Subject       string   `json:"subject"`
+	CourseNumber  string   `json:"courseNumber"`
+	Title         string   `json:"title"`
+	Prerequisites *Node    `json:"prerequisites,omitempty"`
+	Missing       []string `json:"missing,omitempty"` // Courses that would meet the prerequisites once completed; set for ineligible courses
+}
+
+// CoursePrerequisites is one course's place in the prerequisite graph.
+type CoursePrerequisites struct {
+	Term          string   `json:"term"`
+	Subject       string   `json:"subject"`
+	CourseNumber  string   `json:"courseNumber"`
+	Prerequisites *Node    `json:"prerequisites"` // Null when the course has none
+	Unlocks       []string `json:"unlocks"`       // Courses that name this one as a prerequisite
+}
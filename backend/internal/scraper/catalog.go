@@ -0,0 +1,347 @@
+package scraper
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CatalogSource serves sections from a local catalog export instead of a
+// registrar's API, so the optimizer can run on other schools' data or
+// registrar dumps. The whole file is loaded up front.
+//
+// JSON files hold {"terms": [...], "sections": [...]}, with terms as in
+// getTerms and sections as CourseData (Banner's classSearch fields).
+//
+// Term codes must follow WWU's YYYYQQ format (QQ is 10, 20, 30, or 40),
+// since the scrape jobs pick terms by their year and quarter. Catalogs with
+// other codes are rejected rather than never scraped.
+//
+// CSV files have a header row naming the columns below. term, crn, subject,
+// course_number, and title are required; the rest are optional. A section
+// with several meetings or instructors takes a row for each, repeating the
+// section columns; the first instructor listed is primary.
+//
+//	term, term_description, crn, subject, subject_description, course_number,
+//	sequence, title, credits, credits_high, campus, schedule_type,
+//	instructional_method, max_enrollment, enrollment, seats_available,
+//	wait_capacity, wait_count, open, instructor, instructor_email,
+//	attributes (semicolon-separated codes), days (letters from MTWRFSU),
+//	begin_time, end_time (HHMM), building, room, start_date, end_date
+type CatalogSource struct {
+	terms    []TermResponse
+	sections map[string][]CourseData // By term, in file order
+}
+
+// catalogFile is the JSON catalog format.
+type catalogFile struct {
+	Terms    []TermResponse `json:"terms"`
+	Sections []CourseData   `json:"sections"`
+}
+
+// NewCatalogSource loads a .json or .csv catalog export.
+func NewCatalogSource(path string) (*CatalogSource, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open catalog: %w", err)
+	}
+	defer f.Close()
+
+	var catalog catalogFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		if err := json.NewDecoder(f).Decode(&catalog); err != nil {
+			return nil, fmt.Errorf("decode catalog %s: %w", path, err)
+		}
+	case ".csv":
+		if catalog, err = readCatalogCSV(f); err != nil {
+			return nil, fmt.Errorf("read catalog %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("catalog %s: unsupported format (want .json or .csv)", path)
+	}
+	return newCatalogSource(catalog)
+}
+
+func newCatalogSource(catalog catalogFile) (*CatalogSource, error) {
+	src := &CatalogSource{sections: make(map[string][]CourseData)}
+	described := make(map[string]bool)
+	for _, t := range catalog.Terms {
+		if t.Code == "" {
+			return nil, errors.New("catalog term without a code")
+		}
+		if !validTermCode(t.Code) {
+			return nil, fmt.Errorf("catalog term %q: code must be YYYYQQ with QQ 10, 20, 30, or 40", t.Code)
+		}
+		src.terms = append(src.terms, t)
+		described[t.Code] = true
+	}
+
+	seen := make(map[string]bool)
+	for i, section := range catalog.Sections {
+		if section.Term == "" || section.CourseReferenceNumber == "" || section.Subject == "" ||
+			section.CourseNumber == "" || section.CourseTitle == "" {
+			return nil, fmt.Errorf("catalog section %d: term, crn, subject, course number, and title are required", i+1)
+		}
+		if !validTermCode(section.Term) {
+			return nil, fmt.Errorf("catalog section %d: term %q must be YYYYQQ with QQ 10, 20, 30, or 40", i+1, section.Term)
+		}
+		key := section.Term + ":" + section.CourseReferenceNumber
+		if seen[key] {
+			return nil, fmt.Errorf("catalog section %d: duplicate CRN %s in term %s", i+1, section.CourseReferenceNumber, section.Term)
+		}
+		seen[key] = true
+
+		src.sections[section.Term] = append(src.sections[section.Term], section)
+		if !described[section.Term] {
+			src.terms = append(src.terms, TermResponse{Code: section.Term, Description: section.TermDesc})
+			described[section.Term] = true
+		}
+	}
+
+	// Most recent first, like Banner
+	slices.SortFunc(src.terms, func(a, b TermResponse) int {
+		return cmp.Compare(b.Code, a.Code)
+	})
+	for i, t := range src.terms {
+		if t.Description == "" {
+			src.terms[i].Description = t.Code
+		}
+	}
+	return src, nil
+}
+
+// validTermCode reports whether code is a YYYYQQ term code, matching
+// jobs.ParseTermCode.
+func validTermCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	if _, err := strconv.Atoi(code[:4]); err != nil {
+		return false
+	}
+	switch code[4:] {
+	case "10", "20", "30", "40":
+		return true
+	}
+	return false
+}
+
+// Terms implements Source.
+func (c *CatalogSource) Terms(ctx context.Context) ([]TermResponse, error) {
+	return slices.Clone(c.terms), nil
+}
+
+// SelectTerm implements Source. Catalogs need no setup.
+func (c *CatalogSource) SelectTerm(ctx context.Context, term string) error {
+	return nil
+}
+
+// FetchPage implements Source.
+func (c *CatalogSource) FetchPage(ctx context.Context, term string, offset int) (*PageResult, error) {
+	sections := c.sections[term]
+	start := min(offset, len(sections))
+	end := min(offset+pageSize, len(sections))
+	return &PageResult{
+		Courses:    sections[start:end],
+		TotalCount: len(sections),
+		Offset:     offset,
+	}, nil
+}
+
+// readCatalogCSV groups rows into sections by term and CRN.
+func readCatalogCSV(r io.Reader) (catalogFile, error) {
+	var catalog catalogFile
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return catalog, fmt.Errorf("read header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"term", "crn", "subject", "course_number", "title"} {
+		if _, ok := columns[required]; !ok {
+			return catalog, fmt.Errorf("missing required column %q", required)
+		}
+	}
+
+	index := make(map[string]int) // term:crn -> catalog.Sections index
+	termDescriptions := make(map[string]string)
+	for n := 1; ; n++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return catalog, err
+		}
+		row := csvRow{columns: columns, record: record}
+
+		term, crn := row.get("term"), row.get("crn")
+		if desc := row.get("term_description"); desc != "" {
+			termDescriptions[term] = desc
+		}
+
+		key := term + ":" + crn
+		i, ok := index[key]
+		if !ok {
+			section, err := row.section()
+			if err != nil {
+				return catalog, fmt.Errorf("row %d: %w", n, err)
+			}
+			i = len(catalog.Sections)
+			index[key] = i
+			catalog.Sections = append(catalog.Sections, section)
+		}
+		section := &catalog.Sections[i]
+
+		if name := row.get("instructor"); name != "" && !slices.ContainsFunc(section.Faculty, func(f FacultyData) bool {
+			return f.DisplayName == name
+		}) {
+			section.Faculty = append(section.Faculty, FacultyData{
+				DisplayName:      name,
+				EmailAddress:     row.get("instructor_email"),
+				PrimaryIndicator: len(section.Faculty) == 0,
+			})
+		}
+		// Rows repeat a meeting when listing further instructors
+		if meeting, ok := row.meeting(); ok && !slices.ContainsFunc(section.MeetingsFaculty, func(m MeetingsFaculty) bool {
+			return m.MeetingTime == meeting
+		}) {
+			section.MeetingsFaculty = append(section.MeetingsFaculty, MeetingsFaculty{MeetingTime: meeting})
+		}
+	}
+
+	for code, desc := range termDescriptions {
+		catalog.Terms = append(catalog.Terms, TermResponse{Code: code, Description: desc})
+	}
+	return catalog, nil
+}
+
+// csvRow reads a catalog CSV record by column name.
+type csvRow struct {
+	columns map[string]int
+	record  []string
+}
+
+func (r csvRow) get(name string) string {
+	i, ok := r.columns[name]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[i])
+}
+
+func (r csvRow) int(name string) (int, error) {
+	value := r.get(name)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", name, err)
+	}
+	return n, nil
+}
+
+func (r csvRow) float(name string) (*float64, error) {
+	value := r.get(name)
+	if value == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &f, nil
+}
+
+// section builds a section from a row's section columns.
+func (r csvRow) section() (CourseData, error) {
+	section := CourseData{
+		Term:                    r.get("term"),
+		TermDesc:                r.get("term_description"),
+		CourseReferenceNumber:   r.get("crn"),
+		Subject:                 strings.ToUpper(r.get("subject")),
+		SubjectDescription:      r.get("subject_description"),
+		CourseNumber:            r.get("course_number"),
+		SequenceNumber:          r.get("sequence"),
+		CourseTitle:             r.get("title"),
+		CampusDescription:       r.get("campus"),
+		ScheduleTypeDescription: r.get("schedule_type"),
+		InstructionalMethod:     r.get("instructional_method"),
+	}
+
+	var err error
+	if section.CreditHourLow, err = r.float("credits"); err != nil {
+		return section, err
+	}
+	if section.CreditHourHigh, err = r.float("credits_high"); err != nil {
+		return section, err
+	}
+	section.CreditHours = section.CreditHourLow
+
+	for name, dest := range map[string]*int{
+		"max_enrollment":  &section.MaximumEnrollment,
+		"enrollment":      &section.Enrollment,
+		"seats_available": &section.SeatsAvailable,
+		"wait_capacity":   &section.WaitCapacity,
+		"wait_count":      &section.WaitCount,
+	} {
+		if *dest, err = r.int(name); err != nil {
+			return section, err
+		}
+	}
+
+	// Open unless stated otherwise or full
+	section.OpenSection = section.SeatsAvailable > 0
+	if open := r.get("open"); open != "" {
+		if section.OpenSection, err = strconv.ParseBool(open); err != nil {
+			return section, fmt.Errorf("open: %w", err)
+		}
+	}
+
+	for code := range strings.SplitSeq(r.get("attributes"), ";") {
+		if code = strings.TrimSpace(code); code != "" {
+			section.SectionAttributes = append(section.SectionAttributes, SectionAttribute{Code: code})
+		}
+	}
+	return section, nil
+}
+
+// meeting builds a meeting time from a row's meeting columns, if it has any.
+func (r csvRow) meeting() (MeetingTimeData, bool) {
+	days := strings.ToUpper(r.get("days"))
+	meeting := MeetingTimeData{
+		BeginTime: strings.ReplaceAll(r.get("begin_time"), ":", ""),
+		EndTime:   strings.ReplaceAll(r.get("end_time"), ":", ""),
+		Building:  r.get("building"),
+		Room:      r.get("room"),
+		StartDate: r.get("start_date"),
+		EndDate:   r.get("end_date"),
+		Monday:    strings.Contains(days, "M"),
+		Tuesday:   strings.Contains(days, "T"),
+		Wednesday: strings.Contains(days, "W"),
+		Thursday:  strings.Contains(days, "R"),
+		Friday:    strings.Contains(days, "F"),
+		Saturday:  strings.Contains(days, "S"),
+		Sunday:    strings.Contains(days, "U"),
+	}
+	empty := days == "" && meeting.BeginTime == "" && meeting.EndTime == "" &&
+		meeting.Building == "" && meeting.Room == ""
+	return meeting, !empty
+}
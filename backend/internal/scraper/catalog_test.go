@@ -0,0 +1,134 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"schedule-optimizer/internal/store"
+	"schedule-optimizer/internal/testutil"
+)
+
+const testCatalogCSV = `term,term_description,crn,subject,course_number,title,credits,max_enrollment,enrollment,seats_available,instructor,days,begin_time,end_time,building,room,attributes
+202530,Fall 2025,30001,csci,247,Data Structures,4,30,25,5,Dr. Smith,MWF,10:00,10:50,CF,105,GUR;QSR
+202530,Fall 2025,30001,csci,247,Data Structures,4,30,25,5,Dr. Jones,MWF,10:00,10:50,CF,105,GUR;QSR
+202530,Fall 2025,30001,csci,247,Data Structures,4,30,25,5,,R,1400,1550,CF,110,GUR;QSR
+202530,Fall 2025,30002,MATH,204,Linear Algebra,4,40,40,0,Dr. Brown,,,,,,
+`
+
+func writeCatalog(t *testing.T, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("failed to write catalog: %v", err)
+	}
+	return path
+}
+
+func TestCatalogSource_CSV(t *testing.T) {
+	src, err := NewCatalogSource(writeCatalog(t, "catalog.csv", testCatalogCSV))
+	if err != nil {
+		t.Fatalf("NewCatalogSource failed: %v", err)
+	}
+
+	ctx := context.Background()
+	terms, _ := src.Terms(ctx)
+	if len(terms) != 1 || terms[0] != (TermResponse{Code: "202530", Description: "Fall 2025"}) {
+		t.Errorf("terms = %+v", terms)
+	}
+
+	page, _ := src.FetchPage(ctx, "202530", 0)
+	if page.Error != nil || page.TotalCount != 2 || len(page.Courses) != 2 {
+		t.Fatalf("page = %+v", page)
+	}
+	ds := page.Courses[0]
+	if ds.Subject != "CSCI" || *ds.CreditHourLow != 4 || ds.SeatsAvailable != 5 || !ds.OpenSection {
+		t.Errorf("section = %+v", ds)
+	}
+	if len(ds.Faculty) != 2 || !ds.Faculty[0].PrimaryIndicator || ds.Faculty[1].PrimaryIndicator {
+		t.Errorf("faculty = %+v", ds.Faculty)
+	}
+	if got := courseState(ds).meetings; got != "MWF 1000-1050 CF 105; R 1400-1550 CF 110" {
+		t.Errorf("meetings = %q", got)
+	}
+	if len(ds.SectionAttributes) != 2 || ds.SectionAttributes[1].Code != "QSR" {
+		t.Errorf("attributes = %+v", ds.SectionAttributes)
+	}
+	if la := page.Courses[1]; la.OpenSection || len(la.MeetingsFaculty) != 0 {
+		t.Errorf("full section without meetings = %+v", la)
+	}
+
+	if page, _ := src.FetchPage(ctx, "202530", 500); len(page.Courses) != 0 || page.TotalCount != 2 {
+		t.Errorf("page past the end = %+v", page)
+	}
+}
+
+func TestCatalogSource_Invalid(t *testing.T) {
+	tests := []struct {
+		name, file, contents, want string
+	}{
+		{"missing column", "c.csv", "term,crn,subject,title\n", `"course_number"`},
+		{"bad number", "c.csv", "term,crn,subject,course_number,title,enrollment\n202530,1,CSCI,101,Intro,lots\n", "enrollment"},
+		{"missing title", "c.csv", "term,crn,subject,course_number,title\n202530,1,CSCI,101,\n", "required"},
+		{"duplicate CRN", "c.json", `{"sections": [
+			{"term": "202530", "courseReferenceNumber": "1", "subject": "CSCI", "courseNumber": "101", "courseTitle": "Intro"},
+			{"term": "202530", "courseReferenceNumber": "1", "subject": "CSCI", "courseNumber": "102", "courseTitle": "Intro II"}]}`, "duplicate"},
+		{"section term code", "c.csv", "term,crn,subject,course_number,title\nFA25,1,CSCI,101,Intro\n", "YYYYQQ"},
+		{"term code", "c.json", `{"terms": [{"code": "202550", "description": "Intersession"}]}`, "YYYYQQ"},
+		{"format", "c.xml", "<catalog/>", "unsupported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCatalogSource(writeCatalog(t, tt.file, tt.contents))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, expected one mentioning %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestScrapeTerm_CatalogSource(t *testing.T) {
+	catalog, err := json.Marshal(catalogFile{
+		Terms: []TermResponse{{Code: "202520", Description: "Spring 2025"}},
+		Sections: []CourseData{
+			makeMockCourse("20001", "CSCI", "247", "Data Structures"),
+			makeMockCourse("20002", "CSCI", "301", "Algorithms"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to encode catalog: %v", err)
+	}
+	src, err := NewCatalogSource(writeCatalog(t, "catalog.json", string(catalog)))
+	if err != nil {
+		t.Fatalf("NewCatalogSource failed: %v", err)
+	}
+
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	// No Banner server; every request would fail
+	scraper, err := newScraperWithBaseURL(db, queries, 2, 0, "http://banner.invalid")
+	if err != nil {
+		t.Fatalf("failed to create scraper: %v", err)
+	}
+	scraper.SetSource(src)
+
+	ctx := context.Background()
+	result, err := scraper.ScrapeTerm(ctx, "202520")
+	if err != nil {
+		t.Fatalf("ScrapeTerm failed: %v", err)
+	}
+	if result.Stored != 2 {
+		t.Errorf("stored = %d, expected 2", result.Stored)
+	}
+	if _, err := queries.GetSectionByTermAndCRN(ctx, store.GetSectionByTermAndCRNParams{Term: "202520", Crn: "20002"}); err != nil {
+		t.Errorf("section not stored: %v", err)
+	}
+	term, err := queries.GetTermByCode(ctx, "202520")
+	if err != nil || !term.LastScrapedAt.Valid {
+		t.Errorf("term = %+v, %v", term, err)
+	}
+}
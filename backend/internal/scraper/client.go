@@ -1,6 +1,7 @@
 package scraper
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,6 +10,7 @@ import (
 	"net/http/cookiejar"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -16,20 +18,31 @@ const (
 	baseURL     = "https://registration.banner.wwu.edu/StudentRegistrationSsb/ssb"
 	pageSize    = 500
 	httpTimeout = 2 * time.Minute // Banner servers can be slow under load
+
+	// sessionDelay lets Banner process a term selection before pages are fetched
+	sessionDelay = time.Second
 )
 
-// Client handles HTTP requests to the Banner API.
+// Client handles HTTP requests to the Banner API. Requests are rate limited
+// and retried with backoff; page fetches start a new session if Banner has
+// dropped the current one. A Client is safe for concurrent use.
 type Client struct {
 	httpClient *http.Client
 	baseURL    string
+	limiter    *limiter
+	retry      retryPolicy
+
+	sessionMu sync.Mutex // Serializes session reinitialization
+	sessionAt time.Time  // When the session was last initialized
 }
 
-// NewClient creates a new Banner API client with a cookie jar.
-func NewClient() (*Client, error) {
-	return newClientWithBaseURL(baseURL)
+// NewClient creates a new Banner API client with a cookie jar, allowing up to
+// requestsPerMinute requests (0 for no limit) with bursts of burst.
+func NewClient(requestsPerMinute, burst int) (*Client, error) {
+	return newClientWithBaseURL(baseURL, requestsPerMinute, burst)
 }
 
-func newClientWithBaseURL(base string) (*Client, error) {
+func newClientWithBaseURL(base string, requestsPerMinute, burst int) (*Client, error) {
 	jar, err := cookiejar.New(nil)
 	if err != nil {
 		return nil, fmt.Errorf("create cookie jar: %w", err)
@@ -41,22 +54,38 @@ func newClientWithBaseURL(base string) (*Client, error) {
 			Timeout: httpTimeout,
 		},
 		baseURL: base,
+		limiter: newLimiter(requestsPerMinute, burst),
+		retry:   defaultRetryPolicy,
 	}, nil
 }
 
 // FetchTerms retrieves available terms from the Banner API.
 // This also initializes cookies needed for subsequent requests.
-func (c *Client) FetchTerms() ([]TermResponse, error) {
+func (c *Client) FetchTerms(ctx context.Context) ([]TermResponse, error) {
+	var terms []TermResponse
+	err := c.do(ctx, "", func() error {
+		var err error
+		terms, err = c.fetchTerms(ctx)
+		return err
+	})
+	return terms, err
+}
+
+func (c *Client) fetchTerms(ctx context.Context) ([]TermResponse, error) {
 	reqURL := c.baseURL + "/classSearch/getTerms?searchTerm=&offset=1&max=100"
 
-	resp, err := c.httpClient.Get(reqURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch terms: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("fetch terms: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("fetch terms: unexpected status %d", resp.StatusCode)
+		return nil, fmt.Errorf("fetch terms: %w", newStatusError(resp))
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -75,7 +104,21 @@ func (c *Client) FetchTerms() ([]TermResponse, error) {
 
 // InitializeSession sets the term context for subsequent course fetches.
 // Must be called after FetchTerms and before FetchPage.
-func (c *Client) InitializeSession(term string) error {
+func (c *Client) InitializeSession(ctx context.Context, term string) error {
+	err := c.do(ctx, "", func() error {
+		return c.initializeSession(ctx, term)
+	})
+	if err != nil {
+		return err
+	}
+
+	c.sessionMu.Lock()
+	c.sessionAt = time.Now()
+	c.sessionMu.Unlock()
+	return nil
+}
+
+func (c *Client) initializeSession(ctx context.Context, term string) error {
 	reqURL := c.baseURL + "/term/search?mode=search"
 
 	data := url.Values{}
@@ -85,7 +128,12 @@ func (c *Client) InitializeSession(term string) error {
 	data.Set("startDatepicker", "")
 	data.Set("endDatepicker", "")
 
-	resp, err := c.httpClient.PostForm(reqURL, data)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return fmt.Errorf("initialize session: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("initialize session: %w", err)
 	}
@@ -95,15 +143,29 @@ func (c *Client) InitializeSession(term string) error {
 	_, _ = io.Copy(io.Discard, resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("initialize session: unexpected status %d", resp.StatusCode)
+		return fmt.Errorf("initialize session: %w", newStatusError(resp))
 	}
 
 	slog.Debug("Session initialized", "term", term)
 	return nil
 }
 
-// FetchPage fetches a single page of course results.
-func (c *Client) FetchPage(term string, offset int) (*PageResult, error) {
+// FetchPage fetches a single page of course results. Failures are reported
+// in the result's Error once retries are exhausted.
+func (c *Client) FetchPage(ctx context.Context, term string, offset int) (*PageResult, error) {
+	var result *PageResult
+	err := c.do(ctx, term, func() error {
+		result = c.fetchPage(ctx, term, offset)
+		return result.Error
+	})
+	if err != nil && result == nil {
+		// The limiter gave up before the first attempt
+		result = &PageResult{Offset: offset, Error: fmt.Errorf("fetch page %d: %w", offset, err)}
+	}
+	return result, nil
+}
+
+func (c *Client) fetchPage(ctx context.Context, term string, offset int) *PageResult {
 	reqURL := c.baseURL + "/searchResults/searchResults"
 
 	params := url.Values{}
@@ -115,27 +177,32 @@ func (c *Client) FetchPage(term string, offset int) (*PageResult, error) {
 
 	fullURL := reqURL + "?" + params.Encode()
 
-	resp, err := c.httpClient.Get(fullURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
 	if err != nil {
-		return &PageResult{Offset: offset, Error: fmt.Errorf("fetch page %d: %w", offset, err)}, nil
+		return &PageResult{Offset: offset, Error: fmt.Errorf("fetch page %d: %w", offset, err)}
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &PageResult{Offset: offset, Error: fmt.Errorf("fetch page %d: %w", offset, err)}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		return &PageResult{
 			Offset: offset,
-			Error:  fmt.Errorf("fetch page %d: unexpected status %d", offset, resp.StatusCode),
-		}, nil
+			Error:  fmt.Errorf("fetch page %d: %w", offset, newStatusError(resp)),
+		}
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return &PageResult{Offset: offset, Error: fmt.Errorf("read page %d: %w", offset, err)}, nil
+		return &PageResult{Offset: offset, Error: fmt.Errorf("read page %d: %w", offset, err)}
 	}
 
 	var apiResp APIResponse
 	if err := json.Unmarshal(body, &apiResp); err != nil {
-		// Try to get a snippet of the response for debugging
+		// Try to get a snippet of the response for debugging. An expired
+		// session gets an HTML page instead of JSON.
 		snippet := string(body)
 		if len(snippet) > 200 {
 			snippet = snippet[:200] + "..."
@@ -143,15 +210,22 @@ func (c *Client) FetchPage(term string, offset int) (*PageResult, error) {
 		snippet = strings.ReplaceAll(snippet, "\n", " ")
 		return &PageResult{
 			Offset: offset,
-			Error:  fmt.Errorf("decode page %d: %w (response: %s)", offset, err, snippet),
-		}, nil
+			Error:  fmt.Errorf("decode page %d: %w: %v (response: %s)", offset, errSessionExpired, err, snippet),
+		}
 	}
 
 	if !apiResp.Success {
 		return &PageResult{
 			Offset: offset,
-			Error:  fmt.Errorf("page %d: API returned success=false", offset),
-		}, nil
+			Error:  fmt.Errorf("page %d: API returned success=false: %w", offset, errSessionExpired),
+		}
+	}
+	// Without a term selected, Banner answers every page with no results
+	if offset > 0 && apiResp.TotalCount == 0 && len(apiResp.Data) == 0 {
+		return &PageResult{
+			Offset: offset,
+			Error:  fmt.Errorf("page %d: empty past the first page: %w", offset, errSessionExpired),
+		}
 	}
 
 	slog.Debug("Fetched page", "offset", offset, "courses", len(apiResp.Data), "total", apiResp.TotalCount)
@@ -160,7 +234,7 @@ func (c *Client) FetchPage(term string, offset int) (*PageResult, error) {
 		Courses:    apiResp.Data,
 		TotalCount: apiResp.TotalCount,
 		Offset:     offset,
-	}, nil
+	}
 }
 
 // PageSize returns the number of courses per page.
@@ -0,0 +1,412 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"schedule-optimizer/internal/store"
+)
+
+// SectionDetails is the catalog text on a section's detail tabs, as plain
+// text. Fields are empty when Banner has nothing to show.
+type SectionDetails struct {
+	Description   string
+	Prerequisites string
+	Restrictions  string
+	Fees          string
+}
+
+// DetailSource is a Source that can also fetch sections' detail tabs.
+// ScrapeDetails returns ErrNoDetails for sources that can't.
+type DetailSource interface {
+	Source
+
+	// FetchDetails returns the description, prerequisites, restrictions,
+	// and fees shown for a section.
+	FetchDetails(ctx context.Context, term, crn string) (*SectionDetails, error)
+
+	// FetchLinkedSections returns the alternative sets of sections that
+	// must be registered together with crn.
+	FetchLinkedSections(ctx context.Context, term, crn string) ([][]string, error)
+}
+
+// detailTabs are the Banner endpoints behind a section's detail tabs. Each
+// takes the term and CRN as a form and answers with an HTML fragment.
+var detailTabs = []struct {
+	endpoint string
+	field    func(*SectionDetails) *string
+}{
+	{"getCourseDescription", func(d *SectionDetails) *string { return &d.Description }},
+	{"getSectionPrerequisites", func(d *SectionDetails) *string { return &d.Prerequisites }},
+	{"getRestrictions", func(d *SectionDetails) *string { return &d.Restrictions }},
+	{"getFees", func(d *SectionDetails) *string { return &d.Fees }},
+}
+
+// FetchDetails implements DetailSource.
+func (c *Client) FetchDetails(ctx context.Context, term, crn string) (*SectionDetails, error) {
+	var details SectionDetails
+	for _, tab := range detailTabs {
+		var body []byte
+		err := c.do(ctx, "", func() error {
+			var err error
+			body, err = c.fetchDetailTab(ctx, tab.endpoint, term, crn)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		*tab.field(&details) = htmlText(body)
+	}
+	return &details, nil
+}
+
+func (c *Client) fetchDetailTab(ctx context.Context, endpoint, term, crn string) ([]byte, error) {
+	data := url.Values{}
+	data.Set("term", term)
+	data.Set("courseReferenceNumber", crn)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/searchResults/"+endpoint, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", endpoint, crn, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", endpoint, crn, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: %w", endpoint, crn, newStatusError(resp))
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read %s %s: %w", endpoint, crn, err)
+	}
+	return body, nil
+}
+
+// linkedSectionsResponse is the response from /searchResults/fetchLinkedSections.
+type linkedSectionsResponse struct {
+	LinkedData [][]struct {
+		CourseReferenceNumber string `json:"courseReferenceNumber"`
+	} `json:"linkedData"`
+}
+
+// FetchLinkedSections implements DetailSource.
+func (c *Client) FetchLinkedSections(ctx context.Context, term, crn string) ([][]string, error) {
+	var groups [][]string
+	err := c.do(ctx, term, func() error {
+		var err error
+		groups, err = c.fetchLinkedSections(ctx, term, crn)
+		return err
+	})
+	return groups, err
+}
+
+func (c *Client) fetchLinkedSections(ctx context.Context, term, crn string) ([][]string, error) {
+	params := url.Values{}
+	params.Set("term", term)
+	params.Set("courseReferenceNumber", crn)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/searchResults/fetchLinkedSections?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch linked sections %s: %w", crn, err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch linked sections %s: %w", crn, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch linked sections %s: %w", crn, newStatusError(resp))
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read linked sections %s: %w", crn, err)
+	}
+
+	var linked linkedSectionsResponse
+	if err := json.Unmarshal(body, &linked); err != nil {
+		return nil, fmt.Errorf("decode linked sections %s: %w: %v", crn, errSessionExpired, err)
+	}
+	groups := make([][]string, 0, len(linked.LinkedData))
+	for _, group := range linked.LinkedData {
+		crns := make([]string, 0, len(group))
+		for _, section := range group {
+			if section.CourseReferenceNumber != "" {
+				crns = append(crns, section.CourseReferenceNumber)
+			}
+		}
+		if len(crns) > 0 {
+			groups = append(groups, crns)
+		}
+	}
+	return groups, nil
+}
+
+// noInformation matches Banner's placeholders for empty tabs, such as "No
+// prerequisite information available."
+var noInformation = regexp.MustCompile(`(?i)^no\b.*\bavailable\.?$`)
+
+// htmlText flattens a detail tab's HTML to text. Block elements and table
+// rows become lines, table cells are separated by spaces, and header cells
+// and placeholders for empty tabs are dropped.
+func htmlText(body []byte) string {
+	var (
+		lines []string
+		line  strings.Builder
+		skip  int // Depth inside elements whose text is dropped
+	)
+	flush := func() {
+		text := strings.Join(strings.Fields(line.String()), " ")
+		line.Reset()
+		if text != "" && !noInformation.MatchString(text) {
+			lines = append(lines, text)
+		}
+	}
+
+	z := html.NewTokenizer(bytes.NewReader(body))
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			flush()
+			return strings.Join(lines, "\n")
+		case html.TextToken:
+			if skip == 0 {
+				line.Write(z.Text())
+			}
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			switch atom.Lookup(name) {
+			case atom.Th, atom.Script, atom.Style:
+				if tt == html.StartTagToken {
+					skip++
+				} else if tt == html.EndTagToken && skip > 0 {
+					skip--
+				}
+			case atom.Td:
+				line.WriteByte(' ')
+			case atom.Br, atom.P, atom.Div, atom.Tr, atom.Li, atom.Table, atom.Section,
+				atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+				flush()
+			}
+		}
+	}
+}
+
+// detailJob is a course whose details are due, with the section they're
+// fetched from and its sections that have linked sections.
+type detailJob struct {
+	section CourseData
+	linked  []string
+}
+
+// detailResult is what was fetched for a detailJob.
+type detailResult struct {
+	job     detailJob
+	details *SectionDetails
+	links   map[string][][]string // By CRN
+	err     error
+}
+
+// ErrNoDetails is returned by ScrapeDetails when the source can't fetch
+// section details, such as a CatalogSource.
+var ErrNoDetails = errors.New("source has no section details")
+
+// DetailResult describes a ScrapeDetails batch.
+type DetailResult struct {
+	Stored    int // Courses whose details were fetched and stored
+	Failed    int // Courses that failed to fetch or store
+	Remaining int // Courses still due after this batch
+}
+
+// ScrapeDetails fetches details for up to limit of the term's stored courses
+// (no limit if limit <= 0) that have none or whose details are older than
+// maxAge. Courses never fetched go first, then the longest since fetched, and
+// each course is stored as it arrives, so batches cut short or left over are
+// picked up by the next call.
+func (s *Scraper) ScrapeDetails(ctx context.Context, term string, maxAge time.Duration, limit int) (DetailResult, error) {
+	var result DetailResult
+	src, ok := s.source.(DetailSource)
+	if !ok {
+		return result, ErrNoDetails
+	}
+
+	rows, err := s.queries.GetCourseDetailsFetchedAt(ctx, term)
+	if err != nil {
+		return result, fmt.Errorf("load fetch times: %w", err)
+	}
+	fetchedAt := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		if row.FetchedAt.Valid {
+			fetchedAt[row.Subject+" "+row.CourseNumber] = row.FetchedAt.Time
+		}
+	}
+
+	sections, err := s.queries.GetDetailSections(ctx, term)
+	if err != nil {
+		return result, fmt.Errorf("load sections: %w", err)
+	}
+
+	var jobs []detailJob
+	index := make(map[string]int)
+	for _, section := range sections {
+		key := section.Subject + " " + section.CourseNumber
+		if at, ok := fetchedAt[key]; ok && time.Since(at) < maxAge {
+			continue
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(jobs)
+			index[key] = i
+			jobs = append(jobs, detailJob{section: CourseData{
+				Term:                  term,
+				CourseReferenceNumber: section.Crn,
+				Subject:               section.Subject,
+				CourseNumber:          section.CourseNumber,
+			}})
+		}
+		if section.IsLinked != 0 {
+			jobs[i].linked = append(jobs[i].linked, section.Crn)
+		}
+	}
+
+	// Never fetched first (the zero time), then oldest
+	slices.SortStableFunc(jobs, func(a, b detailJob) int {
+		return fetchedAt[a.section.Subject+" "+a.section.CourseNumber].Compare(fetchedAt[b.section.Subject+" "+b.section.CourseNumber])
+	})
+	if limit > 0 && len(jobs) > limit {
+		result.Remaining = len(jobs) - limit
+		jobs = jobs[:limit]
+	}
+
+	result.Stored, result.Failed = s.scrapeDetails(ctx, src, term, jobs)
+	result.Remaining += result.Failed
+	return result, nil
+}
+
+// scrapeDetails fetches and stores details for jobs, storing each course as
+// it arrives. Returns how many courses were stored and failed.
+func (s *Scraper) scrapeDetails(ctx context.Context, src DetailSource, term string, jobs []detailJob) (stored, failed int) {
+	if len(jobs) == 0 {
+		return 0, 0
+	}
+	slog.Info("Fetching section details", "term", term, "courses", len(jobs))
+
+	jobChan := make(chan detailJob, len(jobs))
+	for _, job := range jobs {
+		jobChan <- job
+	}
+	close(jobChan)
+
+	results := make(chan detailResult)
+	var wg sync.WaitGroup
+	for range s.concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobChan {
+				if ctx.Err() != nil {
+					results <- detailResult{job: job, err: ctx.Err()}
+					continue
+				}
+				results <- fetchDetails(ctx, src, term, job)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Workers only fetch; details are written here, one course at a time
+	for result := range results {
+		if result.err != nil {
+			if ctx.Err() == nil {
+				slog.Warn("Failed to fetch section details", "term", term, "crn", result.job.section.CourseReferenceNumber, "error", result.err)
+			}
+			failed++
+			continue
+		}
+		if err := store.ExecTx(ctx, s.db, func(queries *store.Queries) error {
+			return saveDetails(ctx, queries, term, result)
+		}); err != nil {
+			slog.Warn("Failed to store section details", "term", term, "crn", result.job.section.CourseReferenceNumber, "error", err)
+			failed++
+			continue
+		}
+		stored++
+	}
+	return stored, failed
+}
+
+// fetchDetails fetches a course's details and its linked sections.
+func fetchDetails(ctx context.Context, src DetailSource, term string, job detailJob) detailResult {
+	result := detailResult{job: job, links: make(map[string][][]string)}
+	result.details, result.err = src.FetchDetails(ctx, term, job.section.CourseReferenceNumber)
+	if result.err != nil {
+		return result
+	}
+	for _, crn := range job.linked {
+		groups, err := src.FetchLinkedSections(ctx, term, crn)
+		if err != nil {
+			result.err = err
+			return result
+		}
+		result.links[crn] = groups
+	}
+	return result
+}
+
+// saveDetails stores a course's details and replaces its sections' links.
+func saveDetails(ctx context.Context, queries *store.Queries, term string, result detailResult) error {
+	section, details := result.job.section, result.details
+	if err := queries.UpsertCourseDetails(ctx, store.UpsertCourseDetailsParams{
+		Term:          term,
+		Subject:       section.Subject,
+		CourseNumber:  section.CourseNumber,
+		Description:   toNullString(details.Description),
+		Prerequisites: toNullString(details.Prerequisites),
+		Restrictions:  toNullString(details.Restrictions),
+		Fees:          toNullString(details.Fees),
+	}); err != nil {
+		return fmt.Errorf("upsert course details: %w", err)
+	}
+
+	for crn, groups := range result.links {
+		if err := queries.DeleteSectionLinks(ctx, store.DeleteSectionLinksParams{Term: term, Crn: crn}); err != nil {
+			return fmt.Errorf("delete links for %s: %w", crn, err)
+		}
+		for i, group := range groups {
+			for _, linked := range group {
+				if err := queries.InsertSectionLink(ctx, store.InsertSectionLinkParams{
+					Term:      term,
+					Crn:       crn,
+					LinkGroup: int64(i),
+					LinkedCrn: linked,
+				}); err != nil {
+					return fmt.Errorf("insert link %s-%s: %w", crn, linked, err)
+				}
+			}
+		}
+	}
+	return nil
+}
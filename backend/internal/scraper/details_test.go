@@ -0,0 +1,254 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"schedule-optimizer/internal/store"
+	"schedule-optimizer/internal/testutil"
+)
+
+func TestHTMLText(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "description",
+			body: `<section aria-labelledby="courseDescription">
+				Linear data structures &amp; their   algorithms.<br/>Lab required.
+			</section>`,
+			want: "Linear data structures & their algorithms.\nLab required.",
+		},
+		{
+			name: "prerequisite table",
+			body: `<section aria-labelledby="preReqs"><h3>Catalog Prerequisites</h3>
+				<table><thead><tr><th>And/Or</th><th>Subject</th><th>Course Number</th><th>Grade</th></tr></thead>
+				<tbody><tr><td></td><td>( Computer Science</td><td>145</td><td>C-</td></tr>
+				<tr><td>Or</td><td>Computer Science</td><td>141</td><td>C- )</td></tr></tbody></table></section>`,
+			want: "Catalog Prerequisites\n( Computer Science 145 C-\nOr Computer Science 141 C- )",
+		},
+		{
+			name: "placeholder",
+			body: `<section>No prerequisite information available.</section>`,
+			want: "",
+		},
+		{
+			name: "empty",
+			body: "",
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := htmlText([]byte(tt.body)); got != tt.want {
+				t.Errorf("htmlText = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// newDetailServer stands in for Banner with two CSCI 247 sections, the
+// second linked to a lab, and one MATH 204 section. detailRequests counts
+// detail tab requests.
+func newDetailServer(t *testing.T, detailRequests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/classSearch/getTerms", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]TermResponse{{Code: "202520", Description: "Spring 2025"}})
+	})
+	mux.HandleFunc("/term/search", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/searchResults/searchResults", func(w http.ResponseWriter, r *http.Request) {
+		linked := makeMockCourse("20002", "CSCI", "247", "Data Structures")
+		linked.IsSectionLinked = true
+		json.NewEncoder(w).Encode(APIResponse{Success: true, TotalCount: 3, Data: []CourseData{
+			makeMockCourse("20001", "CSCI", "247", "Data Structures"),
+			linked,
+			makeMockCourse("20003", "MATH", "204", "Linear Algebra"),
+		}})
+	})
+	for _, tab := range detailTabs {
+		mux.HandleFunc("/searchResults/"+tab.endpoint, func(w http.ResponseWriter, r *http.Request) {
+			detailRequests.Add(1)
+			if r.Method != http.MethodPost || r.FormValue("term") != "202520" {
+				http.Error(w, "bad request", http.StatusBadRequest)
+				return
+			}
+			switch tab.endpoint {
+			case "getCourseDescription":
+				w.Write([]byte("<section>Course " + r.FormValue("courseReferenceNumber") + "</section>"))
+			case "getSectionPrerequisites":
+				w.Write([]byte("<section>No prerequisite information available.</section>"))
+			default:
+				w.Write([]byte("<section>" + tab.endpoint + "</section>"))
+			}
+		})
+	}
+	mux.HandleFunc("/searchResults/fetchLinkedSections", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"linkedData":[[{"courseReferenceNumber":"20011"}],[{"courseReferenceNumber":"20012"},{"courseReferenceNumber":"20013"}]]}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestScrapeDetails(t *testing.T) {
+	var detailRequests atomic.Int32
+	server := newDetailServer(t, &detailRequests)
+
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	scraper, err := newScraperWithBaseURL(db, queries, 2, 0, server.URL)
+	if err != nil {
+		t.Fatalf("failed to create scraper: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := scraper.ScrapeTerm(ctx, "202520"); err != nil {
+		t.Fatalf("ScrapeTerm failed: %v", err)
+	}
+	if got := detailRequests.Load(); got != 0 {
+		t.Errorf("ScrapeTerm made %d detail requests, want none", got)
+	}
+
+	result, err := scraper.ScrapeDetails(ctx, "202520", time.Hour, 0)
+	if err != nil {
+		t.Fatalf("ScrapeDetails failed: %v", err)
+	}
+	if result.Stored != 2 || result.Remaining != 0 {
+		t.Errorf("result = %+v, want 2 courses stored and none remaining", result)
+	}
+	// One section per course, four tabs each
+	if got := detailRequests.Load(); got != 8 {
+		t.Errorf("detail requests = %d, want 8", got)
+	}
+
+	details, err := queries.GetCourseDetails(ctx, store.GetCourseDetailsParams{Term: "202520", Subject: "CSCI", CourseNumber: "247"})
+	if err != nil {
+		t.Fatalf("GetCourseDetails failed: %v", err)
+	}
+	if details.Description.String != "Course 20001" || details.Prerequisites.Valid || details.Fees.String != "getFees" {
+		t.Errorf("details = %+v", details)
+	}
+
+	links, err := queries.GetSectionLinks(ctx, store.GetSectionLinksParams{Term: "202520", Crn: "20002"})
+	if err != nil {
+		t.Fatalf("GetSectionLinks failed: %v", err)
+	}
+	if len(links) != 3 || links[0].LinkGroup != 0 || links[0].LinkedCrn != "20011" || links[2].LinkGroup != 1 {
+		t.Errorf("links = %+v", links)
+	}
+
+	// Details fetched within the max age aren't fetched again
+	detailRequests.Store(0)
+	if result, err = scraper.ScrapeDetails(ctx, "202520", time.Hour, 0); err != nil {
+		t.Fatalf("second ScrapeDetails failed: %v", err)
+	}
+	if result.Stored != 0 || detailRequests.Load() != 0 {
+		t.Errorf("second batch fetched %d courses in %d requests, want none", result.Stored, detailRequests.Load())
+	}
+}
+
+func TestScrapeDetails_Batches(t *testing.T) {
+	var detailRequests atomic.Int32
+	server := newDetailServer(t, &detailRequests)
+
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	scraper, err := newScraperWithBaseURL(db, queries, 2, 0, server.URL)
+	if err != nil {
+		t.Fatalf("failed to create scraper: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := scraper.ScrapeTerm(ctx, "202520"); err != nil {
+		t.Fatalf("ScrapeTerm failed: %v", err)
+	}
+
+	result, err := scraper.ScrapeDetails(ctx, "202520", time.Hour, 1)
+	if err != nil {
+		t.Fatalf("ScrapeDetails failed: %v", err)
+	}
+	if result.Stored != 1 || result.Remaining != 1 {
+		t.Errorf("first batch = %+v, want 1 stored and 1 remaining", result)
+	}
+
+	// The next batch resumes with the course not yet fetched
+	result, err = scraper.ScrapeDetails(ctx, "202520", time.Hour, 1)
+	if err != nil {
+		t.Fatalf("ScrapeDetails failed: %v", err)
+	}
+	if result.Stored != 1 || result.Remaining != 0 {
+		t.Errorf("second batch = %+v, want 1 stored and none remaining", result)
+	}
+	for _, course := range [][2]string{{"CSCI", "247"}, {"MATH", "204"}} {
+		if _, err := queries.GetCourseDetails(ctx, store.GetCourseDetailsParams{Term: "202520", Subject: course[0], CourseNumber: course[1]}); err != nil {
+			t.Errorf("details for %s %s: %v", course[0], course[1], err)
+		}
+	}
+
+	// Sources without details are reported, not skipped silently
+	scraper.SetSource(&CatalogSource{})
+	if _, err := scraper.ScrapeDetails(ctx, "202520", time.Hour, 1); !errors.Is(err, ErrNoDetails) {
+		t.Errorf("catalog source error = %v, want ErrNoDetails", err)
+	}
+}
+
+func TestScrapeDetails_Replay(t *testing.T) {
+	var detailRequests atomic.Int32
+	server := newDetailServer(t, &detailRequests)
+	dir := filepath.Join(t.TempDir(), "fixtures")
+	ctx := context.Background()
+
+	recorder, err := NewFixtureTransport(ModeRecord, dir)
+	if err != nil {
+		t.Fatalf("NewFixtureTransport(record) failed: %v", err)
+	}
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	scraper, err := newScraperWithBaseURL(db, queries, 2, 0, server.URL)
+	if err != nil {
+		t.Fatalf("failed to create scraper: %v", err)
+	}
+	scraper.SetTransport(recorder)
+	if _, err := scraper.ScrapeTerm(ctx, "202520"); err != nil {
+		t.Fatalf("recording ScrapeTerm failed: %v", err)
+	}
+	if _, err := scraper.ScrapeDetails(ctx, "202520", time.Hour, 0); err != nil {
+		t.Fatalf("recording ScrapeDetails failed: %v", err)
+	}
+
+	replayer, err := NewFixtureTransport(ModeReplay, dir)
+	if err != nil {
+		t.Fatalf("NewFixtureTransport(replay) failed: %v", err)
+	}
+	replayDB, replayQueries := testutil.SetupTestDB(t)
+	defer replayDB.Close()
+	replay, err := newScraperWithBaseURL(replayDB, replayQueries, 2, 0, "http://banner.invalid")
+	if err != nil {
+		t.Fatalf("failed to create scraper: %v", err)
+	}
+	replay.SetTransport(replayer)
+	if _, err := replay.ScrapeTerm(ctx, "202520"); err != nil {
+		t.Fatalf("replay ScrapeTerm failed: %v", err)
+	}
+	result, err := replay.ScrapeDetails(ctx, "202520", time.Hour, 0)
+	if err != nil {
+		t.Fatalf("replay ScrapeDetails failed: %v", err)
+	}
+	if result.Stored != 2 {
+		t.Errorf("replayed details = %d, want 2", result.Stored)
+	}
+	links, _ := replayQueries.GetSectionLinks(ctx, store.GetSectionLinksParams{Term: "202520", Crn: "20002"})
+	if len(links) != 3 {
+		t.Errorf("replayed links = %+v", links)
+	}
+}
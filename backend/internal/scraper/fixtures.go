@@ -0,0 +1,184 @@
+package scraper
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Banner modes select where the scraper's requests go.
+const (
+	ModeLive   = "live"   // Banner itself
+	ModeRecord = "record" // Banner, saving responses as fixtures
+	ModeReplay = "replay" // Saved fixtures only; no network
+)
+
+// fixtureParam matches the term codes and offsets used in fixture names.
+var fixtureParam = regexp.MustCompile(`^[0-9]+$`)
+
+// NewFixtureTransport returns the transport for a Banner mode, reading and
+// writing fixtures in dir: nil (the default transport) for ModeLive or an
+// empty mode, a recorder for ModeRecord, and a replayer for ModeReplay.
+func NewFixtureTransport(mode, dir string) (http.RoundTripper, error) {
+	switch mode {
+	case "", ModeLive:
+		return nil, nil
+	case ModeRecord:
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("create fixtures directory: %w", err)
+		}
+		return &recordingTransport{next: http.DefaultTransport, dir: dir}, nil
+	case ModeReplay:
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("fixtures directory: %w", err)
+		}
+		return &replayTransport{dir: dir}, nil
+	}
+	return nil, fmt.Errorf("unknown banner mode %q (want %s, %s, or %s)", mode, ModeLive, ModeRecord, ModeReplay)
+}
+
+// fixtureName returns the file a request's response is stored in, or "" for
+// requests that aren't recorded. Terms are stored as getTerms.json, course
+// pages as searchResults-<term>-<offset>.json, and section detail tabs as
+// <endpoint>-<term>-<crn>.html (.json for linked sections); session requests
+// carry no data.
+func fixtureName(req *http.Request) string {
+	query := requestParams(req)
+	endpoint := path.Base(req.URL.Path)
+	switch endpoint {
+	case "getTerms":
+		return "getTerms.json"
+	case "searchResults":
+		term, offset := query.Get("txt_term"), query.Get("pageOffset")
+		if !fixtureParam.MatchString(term) || !fixtureParam.MatchString(offset) {
+			return ""
+		}
+		return "searchResults-" + term + "-" + offset + ".json"
+	case "getCourseDescription", "getSectionPrerequisites", "getRestrictions", "getFees", "fetchLinkedSections":
+		term, crn := query.Get("term"), query.Get("courseReferenceNumber")
+		if !fixtureParam.MatchString(term) || !fixtureParam.MatchString(crn) {
+			return ""
+		}
+		ext := ".html"
+		if endpoint == "fetchLinkedSections" {
+			ext = ".json"
+		}
+		return endpoint + "-" + term + "-" + crn + ext
+	}
+	return ""
+}
+
+// requestParams returns a request's query parameters along with its form
+// body, which is read from a copy so the request can still be sent.
+func requestParams(req *http.Request) url.Values {
+	params := req.URL.Query()
+	if req.GetBody == nil || req.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+		return params
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return params
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return params
+	}
+	form, err := url.ParseQuery(string(data))
+	if err != nil {
+		return params
+	}
+	for key, values := range form {
+		params[key] = append(params[key], values...)
+	}
+	return params
+}
+
+// recordingTransport passes requests through and saves successful data
+// responses to dir.
+type recordingTransport struct {
+	next http.RoundTripper
+	dir  string
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	name := fixtureName(req)
+	if err != nil || name == "" || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// HTML in place of JSON means the session expired; don't save it over a
+	// good fixture
+	if filepath.Ext(name) != ".json" || !strings.HasPrefix(strings.TrimSpace(string(body)), "<") {
+		if err := writeFixture(filepath.Join(t.dir, name), body); err != nil {
+			slog.Warn("Failed to record Banner fixture", "name", name, "error", err)
+		}
+	}
+	return resp, nil
+}
+
+// writeFixture replaces path's contents, writing to a temporary file first
+// so a concurrent replay never reads a partial fixture.
+func writeFixture(path string, body []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, body, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// replayTransport answers requests from fixtures in dir without touching the
+// network. Session requests always succeed; data requests without a fixture
+// get a 404.
+type replayTransport struct {
+	dir string
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	status, body := http.StatusOK, []byte{}
+	if name := fixtureName(req); name != "" {
+		data, err := os.ReadFile(filepath.Join(t.dir, name))
+		switch {
+		case err == nil:
+			body = data
+		case os.IsNotExist(err):
+			status, body = http.StatusNotFound, []byte("no fixture "+name)
+		default:
+			return nil, err
+		}
+	} else if path.Base(req.URL.Path) != "search" {
+		status = http.StatusNotFound
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
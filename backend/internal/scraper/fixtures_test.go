@@ -0,0 +1,110 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"schedule-optimizer/internal/testutil"
+)
+
+func TestFixtures_RecordAndReplay(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/classSearch/getTerms", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]TermResponse{{Code: "202520", Description: "Spring 2025"}})
+	})
+	mux.HandleFunc("/term/search", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/searchResults/searchResults", func(w http.ResponseWriter, r *http.Request) {
+		var courses []CourseData
+		switch r.URL.Query().Get("pageOffset") {
+		case "0":
+			courses = []CourseData{makeMockCourse("20001", "CSCI", "247", "Data Structures")}
+		case "500":
+			courses = []CourseData{makeMockCourse("20002", "CSCI", "301", "Algorithms")}
+		}
+		json.NewEncoder(w).Encode(APIResponse{Success: true, TotalCount: 501, Data: courses})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	dir := filepath.Join(t.TempDir(), "fixtures")
+	ctx := context.Background()
+
+	// Record a scrape against the stand-in
+	recorder, err := NewFixtureTransport(ModeRecord, dir)
+	if err != nil {
+		t.Fatalf("NewFixtureTransport(record) failed: %v", err)
+	}
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	scraper, err := newScraperWithBaseURL(db, queries, 2, 0, server.URL)
+	if err != nil {
+		t.Fatalf("failed to create scraper: %v", err)
+	}
+	scraper.SetTransport(recorder)
+	if _, err := scraper.ScrapeTerm(ctx, "202520"); err != nil {
+		t.Fatalf("recording ScrapeTerm failed: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read fixtures: %v", err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	expected := []string{"getTerms.json", "searchResults-202520-0.json", "searchResults-202520-500.json"}
+	if !slices.Equal(names, expected) {
+		t.Fatalf("fixtures = %v, expected %v", names, expected)
+	}
+
+	// Replay into a fresh database with Banner unreachable
+	server.Close()
+	replayer, err := NewFixtureTransport(ModeReplay, dir)
+	if err != nil {
+		t.Fatalf("NewFixtureTransport(replay) failed: %v", err)
+	}
+	db2, queries2 := testutil.SetupTestDB(t)
+	defer db2.Close()
+	scraper, err = newScraperWithBaseURL(db2, queries2, 2, 0, server.URL)
+	if err != nil {
+		t.Fatalf("failed to create scraper: %v", err)
+	}
+	scraper.SetTransport(replayer)
+	result, err := scraper.ScrapeTerm(ctx, "202520")
+	if err != nil {
+		t.Fatalf("replayed ScrapeTerm failed: %v", err)
+	}
+	if result.Stored != 2 {
+		t.Errorf("replay stored %d sections, expected 2", result.Stored)
+	}
+	term, err := queries2.GetTermByCode(ctx, "202520")
+	if err != nil || term.Description != "Spring 2025" {
+		t.Errorf("replayed term = %+v, %v", term, err)
+	}
+
+	// Terms without fixtures fail rather than reaching the network
+	if _, err := scraper.ScrapeTerm(ctx, "202510"); err == nil {
+		t.Error("expected an error for a term with no fixtures")
+	}
+}
+
+func TestNewFixtureTransport(t *testing.T) {
+	if rt, err := NewFixtureTransport(ModeLive, ""); rt != nil || err != nil {
+		t.Errorf("live = (%v, %v), expected the default transport", rt, err)
+	}
+	if _, err := NewFixtureTransport(ModeReplay, filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected an error replaying from a missing directory")
+	}
+	if _, err := NewFixtureTransport("mock", ""); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
@@ -0,0 +1,224 @@
+package scraper
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"schedule-optimizer/internal/store"
+)
+
+// Section history change kinds, stored in section_history.kind.
+const (
+	ChangeAdded      = "added"      // First scrape that saw the section; new value is the title
+	ChangeTitle      = "title"      // Course title
+	ChangeInstructor = "instructor" // Primary instructor name, empty for none
+	ChangeMeetings   = "meetings"   // Meeting summary, see formatMeetings
+	ChangeCapacity   = "capacity"   // Maximum enrollment
+	ChangeSeats      = "seats"      // Seats available
+	ChangeStatus     = "status"     // "open" or "closed"
+	ChangeRemoved    = "removed"    // A complete scrape no longer returned the section
+	ChangeRestored   = "restored"   // A removed section was returned again
+)
+
+// sectionState is the part of a section whose changes are tracked.
+type sectionState struct {
+	title      string
+	instructor string
+	meetings   string
+	capacity   int64
+	seats      int64
+	open       bool
+	removed    bool
+}
+
+// sectionChange is one section_history row to insert.
+type sectionChange struct {
+	kind     string
+	oldValue sql.NullString
+	newValue sql.NullString
+}
+
+// loadSectionStates reads the tracked fields of the stored sections among
+// crns in two queries, keyed by CRN. CRNs that haven't been stored before
+// are absent from the result.
+func loadSectionStates(ctx context.Context, queries *store.Queries, term string, crns []string) (map[string]*sectionState, error) {
+	states := make(map[string]*sectionState, len(crns))
+	if len(crns) == 0 {
+		return states, nil
+	}
+
+	sections, err := queries.GetSectionStatesByCRNs(ctx, store.GetSectionStatesByCRNsParams{Term: term, Crns: crns})
+	if err != nil {
+		return nil, err
+	}
+	crnByID := make(map[int64]string, len(sections))
+	ids := make([]int64, 0, len(sections))
+	for _, section := range sections {
+		// A section with several primary instructors is listed once per instructor; keep the first
+		if _, ok := states[section.Crn]; ok {
+			continue
+		}
+		states[section.Crn] = &sectionState{
+			title:      section.Title,
+			instructor: section.InstructorName.String,
+			capacity:   section.MaxEnrollment.Int64,
+			seats:      section.SeatsAvailable.Int64,
+			open:       section.IsOpen.Int64 == 1,
+			removed:    section.RemovedAt.Valid,
+		}
+		crnByID[section.ID] = section.Crn
+		ids = append(ids, section.ID)
+	}
+
+	rows, err := queries.GetMeetingTimesBySectionIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	meetings := make(map[string][]MeetingTimeData, len(ids))
+	for _, mt := range rows {
+		crn := crnByID[mt.SectionID]
+		meetings[crn] = append(meetings[crn], MeetingTimeData{
+			BeginTime: mt.StartTime.String,
+			EndTime:   mt.EndTime.String,
+			Building:  mt.Building.String,
+			Room:      mt.Room.String,
+			Monday:    mt.Monday.Int64 == 1,
+			Tuesday:   mt.Tuesday.Int64 == 1,
+			Wednesday: mt.Wednesday.Int64 == 1,
+			Thursday:  mt.Thursday.Int64 == 1,
+			Friday:    mt.Friday.Int64 == 1,
+			Saturday:  mt.Saturday.Int64 == 1,
+			Sunday:    mt.Sunday.Int64 == 1,
+		})
+	}
+	for crn, state := range states {
+		state.meetings = formatMeetings(meetings[crn])
+	}
+	return states, nil
+}
+
+// courseState returns the tracked fields of a scraped course.
+func courseState(course CourseData) sectionState {
+	state := sectionState{
+		title:    course.CourseTitle,
+		capacity: int64(course.MaximumEnrollment),
+		seats:    int64(course.SeatsAvailable),
+		open:     course.OpenSection,
+	}
+	for _, faculty := range course.Faculty {
+		if faculty.PrimaryIndicator {
+			state.instructor = faculty.DisplayName
+			break
+		}
+	}
+	meetings := make([]MeetingTimeData, len(course.MeetingsFaculty))
+	for i, mf := range course.MeetingsFaculty {
+		meetings[i] = mf.MeetingTime
+	}
+	state.meetings = formatMeetings(meetings)
+	return state
+}
+
+// diffSection lists the changes from prev to next. A nil prev means the
+// section is new.
+func diffSection(prev *sectionState, next sectionState) []sectionChange {
+	if prev == nil {
+		return []sectionChange{{kind: ChangeAdded, newValue: toNullString(next.title)}}
+	}
+
+	var changes []sectionChange
+	if prev.removed {
+		changes = append(changes, sectionChange{kind: ChangeRestored})
+	}
+	add := func(kind, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, sectionChange{
+				kind:     kind,
+				oldValue: toNullString(oldValue),
+				newValue: toNullString(newValue),
+			})
+		}
+	}
+	add(ChangeTitle, prev.title, next.title)
+	add(ChangeInstructor, prev.instructor, next.instructor)
+	add(ChangeMeetings, prev.meetings, next.meetings)
+	add(ChangeCapacity, strconv.FormatInt(prev.capacity, 10), strconv.FormatInt(next.capacity, 10))
+	add(ChangeSeats, strconv.FormatInt(prev.seats, 10), strconv.FormatInt(next.seats, 10))
+	add(ChangeStatus, openStatus(prev.open), openStatus(next.open))
+	return changes
+}
+
+// markRemoved flags the term's sections that aren't in seen, the CRNs a
+// complete scrape returned, and records a ChangeRemoved for each. Returns
+// the number of sections flagged.
+func markRemoved(ctx context.Context, queries *store.Queries, term string, seen []string) (int, error) {
+	crns, err := queries.MarkSectionsRemoved(ctx, store.MarkSectionsRemovedParams{Term: term, Crns: seen})
+	if err != nil {
+		return 0, err
+	}
+	for _, crn := range crns {
+		if err := recordChanges(ctx, queries, term, crn, []sectionChange{{kind: ChangeRemoved}}); err != nil {
+			return 0, err
+		}
+	}
+	return len(crns), nil
+}
+
+// recordChanges inserts a section's history rows.
+func recordChanges(ctx context.Context, queries *store.Queries, term, crn string, changes []sectionChange) error {
+	for _, change := range changes {
+		if err := queries.InsertSectionHistory(ctx, store.InsertSectionHistoryParams{
+			Term:     term,
+			Crn:      crn,
+			Kind:     change.kind,
+			OldValue: change.oldValue,
+			NewValue: change.newValue,
+		}); err != nil {
+			return fmt.Errorf("insert %s change: %w", change.kind, err)
+		}
+	}
+	return nil
+}
+
+func openStatus(open bool) string {
+	if open {
+		return "open"
+	}
+	return "closed"
+}
+
+// formatMeetings summarizes meeting times in a stable order, e.g.
+// "MWF 1000-1050 CF 105; R 1400-1550 CF 110". Meetings without days, times,
+// or location show as "TBA".
+func formatMeetings(meetings []MeetingTimeData) string {
+	parts := make([]string, 0, len(meetings))
+	for _, mt := range meetings {
+		var days strings.Builder
+		for i, meets := range []bool{mt.Monday, mt.Tuesday, mt.Wednesday, mt.Thursday, mt.Friday, mt.Saturday, mt.Sunday} {
+			if meets {
+				days.WriteByte("MTWRFSU"[i])
+			}
+		}
+
+		var fields []string
+		if days.Len() > 0 {
+			fields = append(fields, days.String())
+		}
+		if mt.BeginTime != "" || mt.EndTime != "" {
+			fields = append(fields, mt.BeginTime+"-"+mt.EndTime)
+		}
+		if location := strings.TrimSpace(mt.Building + " " + mt.Room); location != "" {
+			fields = append(fields, location)
+		}
+		if len(fields) == 0 {
+			fields = append(fields, "TBA")
+		}
+		parts = append(parts, strings.Join(fields, " "))
+	}
+	slices.Sort(parts)
+	return strings.Join(parts, "; ")
+}
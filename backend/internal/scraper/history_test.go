@@ -0,0 +1,130 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"schedule-optimizer/internal/store"
+	"schedule-optimizer/internal/testutil"
+)
+
+func TestSaveCourse_History(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	course := makeMockCourse("20001", "CSCI", "247", "Data Structures")
+	if err := saveWithState(ctx, queries, course); err != nil {
+		t.Fatalf("saveCourse failed: %v", err)
+	}
+	// An identical scrape records nothing
+	if err := saveWithState(ctx, queries, course); err != nil {
+		t.Fatalf("saveCourse failed: %v", err)
+	}
+
+	// The section fills, moves to Tuesday/Thursday, and changes instructor
+	course.SeatsAvailable = 0
+	course.Enrollment = 30
+	course.OpenSection = false
+	course.Faculty = []FacultyData{
+		{DisplayName: "Dr. Assistant", PrimaryIndicator: false},
+		{DisplayName: "Dr. Other", PrimaryIndicator: true},
+	}
+	course.MeetingsFaculty[0].MeetingTime = MeetingTimeData{
+		BeginTime: "1200", EndTime: "1350", Tuesday: true, Thursday: true, Building: "CF", Room: "105",
+	}
+	if err := saveWithState(ctx, queries, course); err != nil {
+		t.Fatalf("saveCourse failed: %v", err)
+	}
+
+	rows, err := queries.GetSectionHistory(ctx, store.GetSectionHistoryParams{Term: "202520", Crn: "20001"})
+	if err != nil {
+		t.Fatalf("failed to get history: %v", err)
+	}
+	var got []string
+	for _, row := range rows {
+		got = append(got, fmt.Sprintf("%s %q->%q", row.Kind, row.OldValue.String, row.NewValue.String))
+	}
+	expected := []string{
+		`added ""->"Data Structures"`,
+		`instructor "Dr. Test"->"Dr. Other"`,
+		`meetings "MWF 1000-1050 CF 105"->"TR 1200-1350 CF 105"`,
+		`seats "5"->"0"`,
+		`status "open"->"closed"`,
+	}
+	if !slices.Equal(got, expected) {
+		t.Errorf("history = %q, expected %q", got, expected)
+	}
+	if rows[0].OldValue.Valid {
+		t.Error("added should have no old value")
+	}
+}
+
+func TestLoadSectionStates(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	first := makeMockCourse("20001", "CSCI", "247", "Data Structures")
+	second := makeMockCourse("20002", "CSCI", "301", "Algorithms")
+	second.Faculty = nil
+	second.MeetingsFaculty = append(second.MeetingsFaculty, MeetingsFaculty{
+		MeetingTime: MeetingTimeData{BeginTime: "1400", EndTime: "1550", Thursday: true},
+	})
+	for _, course := range []CourseData{first, second} {
+		if err := saveWithState(ctx, queries, course); err != nil {
+			t.Fatalf("saveCourse failed: %v", err)
+		}
+	}
+
+	states, err := loadSectionStates(ctx, queries, "202520", []string{"20001", "20002", "29999"})
+	if err != nil {
+		t.Fatalf("loadSectionStates failed: %v", err)
+	}
+	if len(states) != 2 {
+		t.Fatalf("states = %v, expected 20001 and 20002", states)
+	}
+	if got := states["20001"]; got.instructor != "Dr. Test" || got.meetings != "MWF 1000-1050 CF 105" || got.seats != 5 || !got.open {
+		t.Errorf("20001 = %+v", got)
+	}
+	if got := states["20002"]; got.instructor != "" || got.meetings != "MWF 1000-1050 CF 105; R 1400-1550" {
+		t.Errorf("20002 = %+v", got)
+	}
+}
+
+// saveWithState saves a course against its stored state, as storePage does.
+func saveWithState(ctx context.Context, queries *store.Queries, course CourseData) error {
+	states, err := loadSectionStates(ctx, queries, course.Term, []string{course.CourseReferenceNumber})
+	if err != nil {
+		return err
+	}
+	return saveCourse(ctx, queries, course, states[course.CourseReferenceNumber])
+}
+
+func TestFormatMeetings(t *testing.T) {
+	tests := []struct {
+		name     string
+		meetings []MeetingTimeData
+		expected string
+	}{
+		{"none", nil, ""},
+		{"online", []MeetingTimeData{{}}, "TBA"},
+		{
+			"sorted",
+			[]MeetingTimeData{
+				{BeginTime: "1400", EndTime: "1550", Thursday: true, Building: "CF", Room: "110"},
+				{BeginTime: "1000", EndTime: "1050", Monday: true, Wednesday: true, Friday: true},
+			},
+			"MWF 1000-1050; R 1400-1550 CF 110",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatMeetings(tt.meetings); got != tt.expected {
+				t.Errorf("formatMeetings() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
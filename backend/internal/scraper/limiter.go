@@ -0,0 +1,64 @@
+package scraper
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// limiter is a token bucket: up to burst requests at once, refilled at rate
+// per second. A nil limiter never blocks.
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64 // Negative while callers are waiting for refills
+	last   time.Time
+}
+
+// newLimiter returns a limiter allowing perMinute requests per minute, or nil
+// for no limit if perMinute isn't positive.
+func newLimiter(perMinute, burst int) *limiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	burst = max(burst, 1)
+	return &limiter{
+		rate:   float64(perMinute) / 60,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request may be made or ctx is done.
+func (l *limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+
+	// Take a token now, going into debt if none are left, and sleep until
+	// the refill covers it. Waiters queue in the order they arrived.
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	wait := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++ // Return the unused token
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -41,6 +41,8 @@ type CourseData struct {
 	Faculty                  []FacultyData      `json:"faculty"`
 	MeetingsFaculty          []MeetingsFaculty  `json:"meetingsFaculty"`
 	SectionAttributes        []SectionAttribute `json:"sectionAttributes"`
+	IsSectionLinked          bool               `json:"isSectionLinked"`
+	LinkIdentifier           string             `json:"linkIdentifier"`
 }
 
 // FacultyData represents an instructor for a section.
@@ -0,0 +1,153 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// errSessionExpired marks responses Banner sends once it has forgotten the
+// session's term selection: HTML instead of JSON, success=false, or an empty
+// page past the first. The session is initialized again before retrying.
+var errSessionExpired = errors.New("banner session expired")
+
+// statusError is a non-200 response from Banner.
+type statusError struct {
+	code       int
+	retryAfter time.Duration // From the Retry-After header, if any
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status %d", e.code)
+}
+
+func newStatusError(resp *http.Response) *statusError {
+	err := &statusError{code: resp.StatusCode}
+	if seconds, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && seconds > 0 {
+		err.retryAfter = time.Duration(seconds) * time.Second
+	}
+	return err
+}
+
+// retryPolicy controls how failed Banner requests are repeated. Delays double
+// from baseDelay up to maxDelay, with jitter so workers don't retry in step.
+type retryPolicy struct {
+	attempts  int // Including the first
+	baseDelay time.Duration
+	maxDelay  time.Duration
+}
+
+var defaultRetryPolicy = retryPolicy{
+	attempts:  4,
+	baseDelay: 2 * time.Second,
+	maxDelay:  30 * time.Second,
+}
+
+// delay returns how long to wait after the given failed attempt (1-based).
+// A server's Retry-After is honored up to maxDelay.
+func (p retryPolicy) delay(attempt int, retryAfter time.Duration) time.Duration {
+	d := min(p.baseDelay<<(attempt-1), p.maxDelay)
+	// Equal jitter: half fixed, half random
+	d = d/2 + rand.N(d/2+1)
+	return min(max(d, retryAfter), p.maxDelay)
+}
+
+// classify reports whether a failed request is worth repeating, and whether
+// the session must be initialized again first. Network errors, 429s, and 5xx
+// are retried; other statuses are not.
+func classify(err error) (retry, reinit bool) {
+	if errors.Is(err, errSessionExpired) {
+		return true, true
+	}
+	var se *statusError
+	if errors.As(err, &se) {
+		switch {
+		case se.code == http.StatusUnauthorized || se.code == http.StatusForbidden:
+			return true, true
+		case se.code == http.StatusTooManyRequests || se.code >= 500:
+			return true, false
+		}
+		return false, false
+	}
+	return true, false
+}
+
+// do runs fn until it succeeds, fails in a way retrying won't fix, or runs
+// out of attempts, waiting on the rate limiter before each attempt. If term
+// is set and the session expired, it is initialized again before retrying.
+func (c *Client) do(ctx context.Context, term string, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return err
+		}
+		started := time.Now()
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		retry, reinit := classify(err)
+		if !retry || attempt >= c.retry.attempts || ctx.Err() != nil {
+			return err
+		}
+		if reinit && term != "" {
+			if initErr := c.reinitialize(ctx, term, started); initErr != nil {
+				slog.Warn("Failed to reinitialize Banner session", "term", term, "error", initErr)
+			}
+		}
+
+		var retryAfter time.Duration
+		var se *statusError
+		if errors.As(err, &se) {
+			retryAfter = se.retryAfter
+		}
+		delay := c.retry.delay(attempt, retryAfter)
+		slog.Warn("Retrying Banner request", "attempt", attempt, "delay", delay, "error", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
+
+// reinitialize starts a new Banner session for term, unless another worker
+// already did since the failed request started at since.
+func (c *Client) reinitialize(ctx context.Context, term string, since time.Time) error {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+	if c.sessionAt.After(since) {
+		return nil
+	}
+
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	if _, err := c.fetchTerms(ctx); err != nil {
+		return err
+	}
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	if err := c.initializeSession(ctx, term); err != nil {
+		return err
+	}
+	// Other workers wait on sessionMu, so the delay ends early on shutdown
+	select {
+	case <-time.After(sessionDelay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	c.sessionAt = time.Now()
+	slog.Info("Reinitialized Banner session", "term", term)
+	return nil
+}
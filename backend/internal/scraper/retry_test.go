@@ -0,0 +1,178 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testRetryPolicy retries like the default without the wait.
+var testRetryPolicy = retryPolicy{attempts: 4, baseDelay: time.Millisecond, maxDelay: 5 * time.Millisecond}
+
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := newClientWithBaseURL(server.URL, 0, 1)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	client.retry = testRetryPolicy
+	return client
+}
+
+func TestFetchPage_RetriesTransientErrors(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			json.NewEncoder(w).Encode(APIResponse{Success: true, TotalCount: 1, Data: []CourseData{{CourseReferenceNumber: "20001"}}})
+		}
+	}))
+
+	result, _ := client.FetchPage(context.Background(), "202520", 0)
+	if result.Error != nil {
+		t.Fatalf("expected success after retries, got %v", result.Error)
+	}
+	if calls.Load() != 3 || len(result.Courses) != 1 {
+		t.Errorf("calls = %d, courses = %d", calls.Load(), len(result.Courses))
+	}
+}
+
+func TestFetchPage_GivesUp(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+
+	result, _ := client.FetchPage(context.Background(), "202520", 0)
+	if result.Error == nil {
+		t.Fatal("expected an error")
+	}
+	if calls.Load() != int32(testRetryPolicy.attempts) {
+		t.Errorf("calls = %d, expected %d", calls.Load(), testRetryPolicy.attempts)
+	}
+
+	// Client errors aren't retried
+	calls.Store(0)
+	client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	if result, _ := client.FetchPage(context.Background(), "202520", 0); result.Error == nil || calls.Load() != 1 {
+		t.Errorf("404: error %v after %d calls, expected one failed call", result.Error, calls.Load())
+	}
+}
+
+func TestFetchPage_ReinitializesSession(t *testing.T) {
+	var sessions, pageCalls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/classSearch/getTerms", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]TermResponse{{Code: "202520"}})
+	})
+	mux.HandleFunc("/term/search", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("term") != "202520" {
+			t.Errorf("session initialized for term %q", r.PostForm.Get("term"))
+		}
+		sessions.Add(1)
+	})
+	mux.HandleFunc("/searchResults/searchResults", func(w http.ResponseWriter, r *http.Request) {
+		pageCalls.Add(1)
+		// Banner serves its login page once the session has expired
+		if sessions.Load() == 0 {
+			w.Write([]byte("<html>Session expired</html>"))
+			return
+		}
+		json.NewEncoder(w).Encode(APIResponse{Success: true, TotalCount: 600, Data: []CourseData{{CourseReferenceNumber: "20501"}}})
+	})
+	client := newTestClient(t, mux)
+
+	result, _ := client.FetchPage(context.Background(), "202520", 500)
+	if result.Error != nil {
+		t.Fatalf("expected success after reinitializing, got %v", result.Error)
+	}
+	if sessions.Load() != 1 || pageCalls.Load() != 2 {
+		t.Errorf("sessions = %d, page calls = %d; expected 1 and 2", sessions.Load(), pageCalls.Load())
+	}
+}
+
+func TestReinitialize_CancelledDuringDelay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/classSearch/getTerms", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]TermResponse{{Code: "202520"}})
+	})
+	mux.HandleFunc("/term/search", func(w http.ResponseWriter, r *http.Request) {
+		// Shutdown starts while Banner processes the term selection
+		cancel()
+	})
+	client := newTestClient(t, mux)
+
+	start := time.Now()
+	err := client.reinitialize(ctx, "202520", start)
+	if err == nil {
+		t.Fatal("expected an error after cancelling")
+	}
+	if elapsed := time.Since(start); elapsed >= sessionDelay {
+		t.Errorf("reinitialize took %v, expected it to stop before the %v session delay", elapsed, sessionDelay)
+	}
+	if !client.sessionAt.IsZero() {
+		t.Error("expected the cancelled session not to be recorded")
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := retryPolicy{attempts: 5, baseDelay: time.Second, maxDelay: 5 * time.Second}
+	for attempt, upper := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second} {
+		for range 20 {
+			d := p.delay(attempt, 0)
+			if d < upper/2 || d > upper {
+				t.Fatalf("attempt %d delay %v outside [%v, %v]", attempt, d, upper/2, upper)
+			}
+		}
+	}
+	// Retry-After is honored up to maxDelay
+	if d := p.delay(1, 3*time.Second); d != 3*time.Second {
+		t.Errorf("delay with Retry-After 3s = %v", d)
+	}
+	if d := p.delay(1, time.Minute); d != p.maxDelay {
+		t.Errorf("delay with Retry-After 1m = %v, expected %v", d, p.maxDelay)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	if err := (*limiter)(nil).Wait(context.Background()); err != nil {
+		t.Fatalf("nil limiter: %v", err)
+	}
+
+	// 600 per minute is one every 100ms after a burst of 2
+	l := newLimiter(600, 2)
+	start := time.Now()
+	for range 4 {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Errorf("4 requests took %v, expected about 200ms", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Wait(ctx); err == nil {
+		t.Error("expected an error for a cancelled context")
+	}
+}
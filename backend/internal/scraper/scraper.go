@@ -19,6 +19,45 @@
 // is idempotent - failed pages will be refetched on the next run. This is preferable
 // to failing the entire scrape due to one transient error.
 //
+// One transaction per term: fetched pages are held in memory until every page
+// has come back, then written in a single transaction with prepared statements
+// (store.ExecTx), so the transaction isn't held open while waiting on Banner and
+// a scrape that fails, or is cancelled or paused part way, leaves the term as it
+// was. Each section is saved in a savepoint; one that fails is rolled back
+// alone, counted, and the rest are still stored.
+//
+// Change tracking: saving a section replaces its stored row and children, so
+// differences from the previous scrape (seats, instructor, meeting times, ...)
+// are recorded in section_history before the overwrite. See history.go.
+//
+// Scraped timestamp: a term's last_scraped_at is only set by a complete scrape
+// (Result.Complete), so a partial one is retried by the past term backfill.
+//
+// Removed sections: Banner drops cancelled sections from results rather than
+// flagging them. After a scrape that fetched every page, stored sections it
+// didn't return get removed_at set, which hides them from search, the schedule
+// cache, and generation. A later scrape that returns them clears it.
+//
+// Retries and rate limiting: failed requests are retried with exponential backoff
+// and jitter (429 and 5xx responses, network errors), and a page that comes back as
+// HTML or empty means Banner dropped the session, so it is initialized again first.
+// A token bucket shared by all workers caps the request rate. See retry.go.
+//
+// Fixtures: with BANNER_MODE=record, getTerms and searchResults responses are saved
+// to BANNER_FIXTURES_DIR during a real scrape; BANNER_MODE=replay serves them instead
+// of Banner, so a full database can be built offline. See fixtures.go.
+//
+// Sources: Banner is one Source; CatalogSource loads sections from a local CSV or
+// JSON export instead, for other registrars or data dumps (CATALOG_SOURCE_PATH).
+// Sources normalize sections to CourseData, which follows Banner's JSON.
+//
+// Details: ScrapeDetails fetches a stored term's course descriptions, prerequisites,
+// restrictions, and fees from one section each, plus the sections linked to any
+// linked section. It's separate from ScrapeTerm and takes a batch size, since a
+// new term costs about four requests per course; courses are picked by
+// course_details.fetched_at, so each batch resumes where the last stopped. It
+// shares the rate limiter. See details.go.
+//
 // 2-minute HTTP timeout: Banner servers can be extremely slow under load. The previous
 // implementation used 5 minutes; we use 2 minutes as a compromise.
 //
@@ -34,53 +73,92 @@ package scraper
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"sync"
-	"time"
 
 	"schedule-optimizer/internal/store"
 )
 
 // Scraper handles fetching course data from Banner and storing it in the database.
 type Scraper struct {
+	db          *sql.DB
 	queries     *store.Queries
-	client      *Client
+	client      *Client // Banner, the default source
+	source      Source
 	concurrency int
 }
 
-// NewScraper creates a new Scraper with the given database queries and concurrency level.
-func NewScraper(queries *store.Queries, concurrency int) (*Scraper, error) {
-	return newScraperWithBaseURL(queries, concurrency, baseURL)
+// NewScraper creates a new Scraper with the given database and concurrency level.
+// db is used to write each term in a transaction. Banner requests from all
+// workers share a limit of requestsPerMinute (0 for none).
+func NewScraper(db *sql.DB, queries *store.Queries, concurrency, requestsPerMinute int) (*Scraper, error) {
+	return newScraperWithBaseURL(db, queries, concurrency, requestsPerMinute, baseURL)
 }
 
-func newScraperWithBaseURL(queries *store.Queries, concurrency int, base string) (*Scraper, error) {
+func newScraperWithBaseURL(db *sql.DB, queries *store.Queries, concurrency, requestsPerMinute int, base string) (*Scraper, error) {
 	if concurrency < 1 {
 		concurrency = 4
 	}
 
-	client, err := newClientWithBaseURL(base)
+	client, err := newClientWithBaseURL(base, requestsPerMinute, concurrency)
 	if err != nil {
 		return nil, fmt.Errorf("create client: %w", err)
 	}
 
 	return &Scraper{
+		db:          db,
 		queries:     queries,
 		client:      client,
+		source:      client,
 		concurrency: concurrency,
 	}, nil
 }
 
-// ScrapeTerm fetches all courses for the given term and stores them in the database.
-// Returns the number of sections stored. Partial success is possible if some pages fail.
-func (s *Scraper) ScrapeTerm(ctx context.Context, term string) (int, error) {
+// SetSource replaces Banner as the source of sections, such as with a
+// CatalogSource.
+func (s *Scraper) SetSource(src Source) {
+	s.source = src
+}
+
+// SetTransport replaces the transport used for Banner requests, such as one
+// from NewFixtureTransport. nil restores the default.
+func (s *Scraper) SetTransport(rt http.RoundTripper) {
+	s.client.httpClient.Transport = rt
+}
+
+// Result describes a term scrape. It is filled in as far as the scrape got,
+// so failed scrapes still report what was expected and how many pages failed.
+type Result struct {
+	Stored     int // Sections written
+	Expected   int // Sections the source reported for the term
+	PageErrors int // Pages that failed after retries
+	SaveErrors int // Sections that failed to save
+	Removed    int // Sections marked removed
+}
+
+// Complete reports whether every page was fetched and every section the source
+// reported was stored.
+func (r Result) Complete() bool {
+	return r.PageErrors == 0 && r.SaveErrors == 0 && r.Stored >= r.Expected
+}
+
+// ScrapeTerm fetches all courses for the given term and stores them in the database
+// in one transaction. Partial success is possible if some pages fail or some
+// sections fail to save; both are counted in the Result. If ctx is done before
+// the pages are stored, nothing is written and the error wraps ctx.Err().
+func (s *Scraper) ScrapeTerm(ctx context.Context, term string) (Result, error) {
+	var result Result
+
 	slog.Info("Starting term scrape", "term", term, "concurrency", s.concurrency)
 
 	// Fetch terms to initialize cookies and upsert term list
-	terms, err := s.client.FetchTerms()
+	terms, err := s.source.Terms(ctx)
 	if err != nil {
-		return 0, fmt.Errorf("fetch terms: %w", err)
+		return result, fmt.Errorf("fetch terms: %w", err)
 	}
 
 	// Upsert all terms
@@ -94,27 +172,26 @@ func (s *Scraper) ScrapeTerm(ctx context.Context, term string) (int, error) {
 	}
 
 	// Initialize session for the target term
-	if err := s.client.InitializeSession(term); err != nil {
-		return 0, fmt.Errorf("initialize session: %w", err)
+	if err := s.source.SelectTerm(ctx, term); err != nil {
+		return result, fmt.Errorf("initialize session: %w", err)
 	}
 
-	// Brief delay to let server process term selection
-	time.Sleep(time.Second)
-
 	// Fetch first page to get total count
-	firstPage, err := s.client.FetchPage(term, 0)
+	firstPage, err := s.source.FetchPage(ctx, term, 0)
 	if err != nil {
-		return 0, fmt.Errorf("fetch first page: %w", err)
+		return result, fmt.Errorf("fetch first page: %w", err)
 	}
 	if firstPage.Error != nil {
-		return 0, fmt.Errorf("first page error: %w", firstPage.Error)
+		result.PageErrors = 1
+		return result, fmt.Errorf("first page error: %w", firstPage.Error)
 	}
 
 	totalCount := firstPage.TotalCount
+	result.Expected = totalCount
 	slog.Info("Term has courses", "term", term, "total", totalCount)
 
 	if totalCount == 0 {
-		return 0, nil
+		return result, nil
 	}
 
 	// Calculate offsets for remaining pages
@@ -143,8 +220,8 @@ func (s *Scraper) ScrapeTerm(ctx context.Context, term string) (int, error) {
 					results <- &PageResult{Offset: offset, Error: ctx.Err()}
 					return
 				default:
-					result, _ := s.client.FetchPage(term, offset)
-					results <- result
+					page, _ := s.source.FetchPage(ctx, term, offset)
+					results <- page
 				}
 			}
 		}()
@@ -162,58 +239,127 @@ func (s *Scraper) ScrapeTerm(ctx context.Context, term string) (int, error) {
 		close(results)
 	}()
 
-	// Process results
+	// Pages are staged as they arrive and written together at the end, so a
+	// scrape that fails or is stopped part way leaves the term as it was
 	var (
-		stored     int
+		pages      []*PageResult
+		seen       []string // Every CRN the source returned, saved or not
 		pageErrors int
-		saveErrors int
 	)
 
-	for result := range results {
-		if result.Error != nil {
-			slog.Warn("Page fetch failed", "offset", result.Offset, "error", result.Error)
+	for page := range results {
+		if page.Error != nil {
+			slog.Warn("Page fetch failed", "offset", page.Offset, "error", page.Error)
 			pageErrors++
 			continue
 		}
-
-		for _, course := range result.Courses {
-			if err := saveCourse(ctx, s.queries, course); err != nil {
-				slog.Warn("Failed to save course",
-					"crn", course.CourseReferenceNumber,
-					"error", err,
-				)
-				saveErrors++
-				continue
-			}
-			stored++
+		pages = append(pages, page)
+		for _, course := range page.Courses {
+			seen = append(seen, course.CourseReferenceNumber)
 		}
 	}
+	result.PageErrors = pageErrors
+
+	if err := ctx.Err(); err != nil {
+		return result, fmt.Errorf("scrape stopped, no data stored: %w", err)
+	}
 
 	// Check if we got any data at all
-	if stored == 0 && pageErrors > 0 {
-		return 0, errors.New("all pages failed, no data stored")
+	if len(seen) == 0 && pageErrors > 0 {
+		return result, errors.New("all pages failed, no data stored")
 	}
 
-	// Update last_scraped_at timestamp
-	if err := s.queries.UpdateTermScrapedAt(ctx, term); err != nil {
-		slog.Warn("Failed to update term scraped timestamp", "term", term, "error", err)
+	if err := s.storeTerm(ctx, term, pages, seen, &result); err != nil {
+		result.Stored, result.SaveErrors, result.Removed = 0, 0, 0
+		return result, fmt.Errorf("store term %s: %w", term, err)
 	}
 
 	slog.Info("Term scrape complete",
 		"term", term,
-		"stored", stored,
+		"stored", result.Stored,
 		"page_errors", pageErrors,
-		"save_errors", saveErrors,
+		"save_errors", result.SaveErrors,
+		"removed", result.Removed,
 		"expected", totalCount,
 	)
 
-	return stored, nil
+	return result, nil
+}
+
+// storeTerm writes a scrape's pages in one transaction, marks sections it
+// didn't return removed, and sets the term's last_scraped_at if the scrape was
+// complete. Counts are added to result; they're only meaningful if it returns
+// nil, since an error rolls everything back.
+func (s *Scraper) storeTerm(ctx context.Context, term string, pages []*PageResult, seen []string, result *Result) error {
+	return store.ExecTx(ctx, s.db, func(queries *store.Queries) error {
+		for _, page := range pages {
+			stored, failed, err := storePage(ctx, queries, term, page)
+			if err != nil {
+				return fmt.Errorf("store page at offset %d: %w", page.Offset, err)
+			}
+			result.Stored += stored
+			result.SaveErrors += failed
+		}
+
+		// Sections missing from a complete scrape were dropped from Banner. Any
+		// failed or short page means we can't tell, so nothing is marked.
+		switch {
+		case result.PageErrors > 0:
+			slog.Warn("Skipping removed section check after page errors", "term", term, "page_errors", result.PageErrors)
+		case len(seen) < result.Expected:
+			slog.Warn("Skipping removed section check for incomplete scrape", "term", term, "seen", len(seen), "expected", result.Expected)
+		default:
+			removed, err := markRemoved(ctx, queries, term, seen)
+			if err != nil {
+				return fmt.Errorf("mark removed sections: %w", err)
+			}
+			result.Removed = removed
+		}
+
+		// Only a complete scrape counts as scraped, so partial terms are
+		// picked up again by jobs looking for unscraped ones
+		if !result.Complete() {
+			return nil
+		}
+		return queries.UpdateTermScrapedAt(ctx, term)
+	})
+}
+
+// storePage writes a page's sections and returns how many were stored and how
+// many failed. Each section is saved in a savepoint, so a failed section's
+// writes are undone without affecting the rest.
+func storePage(ctx context.Context, queries *store.Queries, term string, page *PageResult) (stored, failed int, err error) {
+	crns := make([]string, len(page.Courses))
+	for i, course := range page.Courses {
+		crns[i] = course.CourseReferenceNumber
+	}
+	prev, err := loadSectionStates(ctx, queries, term, crns)
+	if err != nil {
+		return 0, 0, fmt.Errorf("load sections: %w", err)
+	}
+
+	for _, course := range page.Courses {
+		err := store.Savepoint(ctx, queries, func() error {
+			return saveCourse(ctx, queries, course, prev[course.CourseReferenceNumber])
+		})
+		if err != nil {
+			slog.Warn("Failed to save course",
+				"term", term,
+				"crn", course.CourseReferenceNumber,
+				"error", err,
+			)
+			failed++
+			continue
+		}
+		stored++
+	}
+	return stored, failed, nil
 }
 
-// ScrapeTerms fetches all available terms from Banner.
+// ScrapeTerms fetches all available terms from the source.
 // Useful for populating the terms table without scraping course data.
 func (s *Scraper) ScrapeTerms(ctx context.Context) ([]TermResponse, error) {
-	terms, err := s.client.FetchTerms()
+	terms, err := s.source.Terms(ctx)
 	if err != nil {
 		return nil, err
 	}
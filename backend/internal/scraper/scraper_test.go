@@ -2,11 +2,15 @@ package scraper
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 
+	"schedule-optimizer/internal/store"
 	"schedule-optimizer/internal/testutil"
 )
 
@@ -50,19 +54,22 @@ func TestScrapeTerm(t *testing.T) {
 	db, queries := testutil.SetupTestDB(t)
 	defer db.Close()
 
-	scraper, err := newScraperWithBaseURL(queries, 2, server.URL)
+	scraper, err := newScraperWithBaseURL(db, queries, 2, 0, server.URL)
 	if err != nil {
 		t.Fatalf("failed to create scraper: %v", err)
 	}
 
 	ctx := context.Background()
-	stored, err := scraper.ScrapeTerm(ctx, "202520")
+	result, err := scraper.ScrapeTerm(ctx, "202520")
 	if err != nil {
 		t.Fatalf("ScrapeTerm failed: %v", err)
 	}
 
-	if stored != 2 {
-		t.Errorf("expected 2 sections stored, got %d", stored)
+	if result.Stored != 2 {
+		t.Errorf("expected 2 sections stored, got %d", result.Stored)
+	}
+	if !result.Complete() {
+		t.Errorf("result = %+v, expected complete", result)
 	}
 
 	sections, err := queries.GetSectionsByTerm(ctx, "202520")
@@ -123,19 +130,19 @@ func TestScrapeTerm_Pagination(t *testing.T) {
 	db, queries := testutil.SetupTestDB(t)
 	defer db.Close()
 
-	scraper, err := newScraperWithBaseURL(queries, 2, server.URL)
+	scraper, err := newScraperWithBaseURL(db, queries, 2, 0, server.URL)
 	if err != nil {
 		t.Fatalf("failed to create scraper: %v", err)
 	}
 
 	ctx := context.Background()
-	stored, err := scraper.ScrapeTerm(ctx, "202520")
+	result, err := scraper.ScrapeTerm(ctx, "202520")
 	if err != nil {
 		t.Fatalf("ScrapeTerm failed: %v", err)
 	}
 
-	if stored != 3 {
-		t.Errorf("expected 3 sections stored from pagination, got %d", stored)
+	if result.Stored != 3 {
+		t.Errorf("expected 3 sections stored from pagination, got %d", result.Stored)
 	}
 }
 
@@ -178,19 +185,108 @@ func TestScrapeTerm_PartialFailure(t *testing.T) {
 	db, queries := testutil.SetupTestDB(t)
 	defer db.Close()
 
-	scraper, err := newScraperWithBaseURL(queries, 1, server.URL)
+	scraper, err := newScraperWithBaseURL(db, queries, 1, 0, server.URL)
 	if err != nil {
 		t.Fatalf("failed to create scraper: %v", err)
 	}
+	scraper.client.retry = testRetryPolicy
 
 	ctx := context.Background()
-	stored, err := scraper.ScrapeTerm(ctx, "202520")
+	result, err := scraper.ScrapeTerm(ctx, "202520")
 	if err != nil {
 		t.Fatalf("expected partial success, got error: %v", err)
 	}
 
-	if stored != 1 {
-		t.Errorf("expected 1 section stored despite page failure, got %d", stored)
+	if result.Stored != 1 {
+		t.Errorf("expected 1 section stored despite page failure, got %d", result.Stored)
+	}
+	if result.PageErrors != 1 || result.Complete() {
+		t.Errorf("result = %+v, expected one page error and incomplete", result)
+	}
+
+	// The term isn't marked scraped, so the backfill retries it
+	term, err := queries.GetTermByCode(ctx, "202520")
+	if err != nil {
+		t.Fatalf("failed to get term: %v", err)
+	}
+	if term.LastScrapedAt.Valid {
+		t.Error("expected last_scraped_at to stay unset after a partial scrape")
+	}
+}
+
+func TestScrapeTerm_SaveFailureCounted(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/classSearch/getTerms", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]TermResponse{{Code: "202520", Description: "Spring 2025"}})
+	})
+
+	mux.HandleFunc("/term/search", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	courses := []CourseData{makeMockCourse("20001", "CSCI", "247", "Data Structures")}
+	mux.HandleFunc("/searchResults/searchResults", func(w http.ResponseWriter, r *http.Request) {
+		var data []CourseData
+		if r.URL.Query().Get("pageOffset") == "0" {
+			data = courses
+		}
+		json.NewEncoder(w).Encode(APIResponse{Success: true, TotalCount: len(courses), Data: data})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	scraper, err := newScraperWithBaseURL(db, queries, 1, 0, server.URL)
+	if err != nil {
+		t.Fatalf("failed to create scraper: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := scraper.ScrapeTerm(ctx, "202520"); err != nil {
+		t.Fatalf("ScrapeTerm failed: %v", err)
+	}
+
+	// 20001 changes, then saving the new section fails at its last write
+	if _, err := db.Exec(`
+		CREATE TRIGGER fail_insert BEFORE INSERT ON section_history WHEN NEW.crn = '20002'
+		BEGIN SELECT RAISE(ABORT, 'insert failed'); END;
+	`); err != nil {
+		t.Fatalf("failed to create trigger: %v", err)
+	}
+	courses[0].SeatsAvailable = 0
+	courses = append(courses, makeMockCourse("20002", "CSCI", "301", "Algorithms"))
+
+	result, err := scraper.ScrapeTerm(ctx, "202520")
+	if err != nil {
+		t.Fatalf("expected a failed section not to fail the scrape, got: %v", err)
+	}
+	if result.Stored != 1 || result.SaveErrors != 1 || result.Complete() {
+		t.Errorf("result = %+v, expected 1 stored, 1 save error, and incomplete", result)
+	}
+
+	section, err := queries.GetSectionByTermAndCRN(ctx, store.GetSectionByTermAndCRNParams{Term: "202520", Crn: "20001"})
+	if err != nil {
+		t.Fatalf("failed to get section: %v", err)
+	}
+	if section.SeatsAvailable.Int64 != 0 {
+		t.Errorf("seats = %d, expected the second scrape's 0", section.SeatsAvailable.Int64)
+	}
+	history, err := queries.GetSectionHistory(ctx, store.GetSectionHistoryParams{Term: "202520", Crn: "20001"})
+	if err != nil {
+		t.Fatalf("failed to get history: %v", err)
+	}
+	if len(history) != 2 {
+		t.Errorf("expected the added and seats changes, got %d", len(history))
+	}
+
+	// The failed section's earlier writes were rolled back with it
+	_, err = queries.GetSectionByTermAndCRN(ctx, store.GetSectionByTermAndCRNParams{Term: "202520", Crn: "20002"})
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected no row for the failed section, got err %v", err)
 	}
 }
 
@@ -212,7 +308,7 @@ func TestScrapeTerms(t *testing.T) {
 	db, queries := testutil.SetupTestDB(t)
 	defer db.Close()
 
-	scraper, err := newScraperWithBaseURL(queries, 1, server.URL)
+	scraper, err := newScraperWithBaseURL(db, queries, 1, 0, server.URL)
 	if err != nil {
 		t.Fatalf("failed to create scraper: %v", err)
 	}
@@ -268,3 +364,172 @@ func makeMockCourse(crn, subject, number, title string) CourseData {
 		},
 	}
 }
+
+func TestScrapeTerm_CancelledStoresNothing(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/classSearch/getTerms", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]TermResponse{{Code: "202520", Description: "Spring 2025"}})
+	})
+
+	mux.HandleFunc("/term/search", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	courses := []CourseData{
+		makeMockCourse("20001", "CSCI", "247", "Data Structures"),
+		makeMockCourse("20002", "CSCI", "301", "Algorithms"),
+	}
+	var cancel context.CancelFunc
+	mux.HandleFunc("/searchResults/searchResults", func(w http.ResponseWriter, r *http.Request) {
+		// 20001 is on the first page and 20002 on the second, which stops
+		// the scrape once it's been fetched
+		data := courses[:1]
+		if r.URL.Query().Get("pageOffset") != "0" {
+			data = courses[1:]
+			if cancel != nil {
+				cancel()
+			}
+		}
+		json.NewEncoder(w).Encode(APIResponse{Success: true, TotalCount: 600, Data: data})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	scraper, err := newScraperWithBaseURL(db, queries, 1, 0, server.URL)
+	if err != nil {
+		t.Fatalf("failed to create scraper: %v", err)
+	}
+
+	if _, err := scraper.ScrapeTerm(context.Background(), "202520"); err != nil {
+		t.Fatalf("ScrapeTerm failed: %v", err)
+	}
+
+	courses[0].SeatsAvailable = 0
+	courses[1].SeatsAvailable = 0
+	ctx, c := context.WithCancel(context.Background())
+	cancel = c
+	defer c()
+
+	result, err := scraper.ScrapeTerm(ctx, "202520")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if result.Stored != 0 {
+		t.Errorf("stored = %d, expected nothing stored", result.Stored)
+	}
+
+	// Neither page's changes were written
+	for _, crn := range []string{"20001", "20002"} {
+		section, err := queries.GetSectionByTermAndCRN(context.Background(), store.GetSectionByTermAndCRNParams{Term: "202520", Crn: crn})
+		if err != nil {
+			t.Fatalf("failed to get section %s: %v", crn, err)
+		}
+		if section.SeatsAvailable.Int64 == 0 {
+			t.Errorf("section %s was updated by the cancelled scrape", crn)
+		}
+	}
+}
+
+func TestScrapeTerm_RemovedSections(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/classSearch/getTerms", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]TermResponse{{Code: "202520", Description: "Spring 2025"}})
+	})
+
+	mux.HandleFunc("/term/search", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	courses := []CourseData{
+		makeMockCourse("20001", "CSCI", "247", "Data Structures"),
+		makeMockCourse("20002", "CSCI", "301", "Algorithms"),
+	}
+	failing := false
+	mux.HandleFunc("/searchResults/searchResults", func(w http.ResponseWriter, r *http.Request) {
+		offset := r.URL.Query().Get("pageOffset")
+		if failing {
+			// The first page lacks 20002 and the second fails
+			if offset != "0" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			json.NewEncoder(w).Encode(APIResponse{Success: true, TotalCount: 600, Data: courses[:1]})
+			return
+		}
+		var data []CourseData
+		if offset == "0" {
+			data = courses
+		}
+		json.NewEncoder(w).Encode(APIResponse{Success: true, TotalCount: len(courses), Data: data})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	scraper, err := newScraperWithBaseURL(db, queries, 1, 0, server.URL)
+	if err != nil {
+		t.Fatalf("failed to create scraper: %v", err)
+	}
+	scraper.client.retry = testRetryPolicy
+
+	ctx := context.Background()
+	scrape := func() {
+		t.Helper()
+		if _, err := scraper.ScrapeTerm(ctx, "202520"); err != nil {
+			t.Fatalf("ScrapeTerm failed: %v", err)
+		}
+	}
+	removed := func(crn string) bool {
+		t.Helper()
+		section, err := queries.GetSectionByTermAndCRN(ctx, store.GetSectionByTermAndCRNParams{Term: "202520", Crn: crn})
+		if err != nil {
+			t.Fatalf("failed to get section %s: %v", crn, err)
+		}
+		return section.RemovedAt.Valid
+	}
+
+	scrape()
+
+	// A scrape with a failed page can't tell what was dropped, so nothing is marked
+	failing = true
+	scrape()
+	if removed("20001") || removed("20002") {
+		t.Fatal("failed scrape marked sections removed")
+	}
+
+	failing = false
+	courses = courses[:1]
+	scrape()
+	if removed("20001") || !removed("20002") {
+		t.Fatalf("expected only 20002 removed, got 20001=%v 20002=%v", removed("20001"), removed("20002"))
+	}
+
+	// Marking is idempotent, and a returning section is restored
+	scrape()
+	courses = append(courses, makeMockCourse("20002", "CSCI", "301", "Algorithms"))
+	scrape()
+	if removed("20002") {
+		t.Error("expected 20002 restored")
+	}
+
+	rows, err := queries.GetSectionHistory(ctx, store.GetSectionHistoryParams{Term: "202520", Crn: "20002"})
+	if err != nil {
+		t.Fatalf("failed to get history: %v", err)
+	}
+	var kinds []string
+	for _, row := range rows {
+		kinds = append(kinds, row.Kind)
+	}
+	if !slices.Equal(kinds, []string{ChangeAdded, ChangeRemoved, ChangeRestored}) {
+		t.Errorf("history = %v", kinds)
+	}
+}
@@ -0,0 +1,39 @@
+package scraper
+
+import (
+	"context"
+	"time"
+)
+
+// Source is where the scraper gets sections from. Sections are normalized to
+// CourseData, which follows Banner's classSearch JSON; other registrars'
+// data is converted to it. The Banner Client is the default Source;
+// CatalogSource reads a local export.
+type Source interface {
+	// Terms lists the terms the source offers. Scrapes call it first, so
+	// sources may use it to set up state, as Banner does with cookies.
+	Terms(ctx context.Context) ([]TermResponse, error)
+
+	// SelectTerm prepares to fetch a term's pages.
+	SelectTerm(ctx context.Context, term string) error
+
+	// FetchPage returns up to PageSize() sections starting at offset, with
+	// the term's total. Failures are reported in the result's Error; the
+	// returned error is reserved for the source being unusable.
+	FetchPage(ctx context.Context, term string, offset int) (*PageResult, error)
+}
+
+// Terms implements Source.
+func (c *Client) Terms(ctx context.Context) ([]TermResponse, error) {
+	return c.FetchTerms(ctx)
+}
+
+// SelectTerm implements Source. Banner needs a moment after the term is
+// selected before pages come back populated.
+func (c *Client) SelectTerm(ctx context.Context, term string) error {
+	if err := c.InitializeSession(ctx, term); err != nil {
+		return err
+	}
+	time.Sleep(sessionDelay)
+	return nil
+}
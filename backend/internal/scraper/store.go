@@ -9,8 +9,12 @@ import (
 )
 
 // saveCourse persists a single course to the database.
-// Handles upsert of section, then replaces all child records.
-func saveCourse(ctx context.Context, queries *store.Queries, course CourseData) error {
+// Handles upsert of section, then replaces all child records. Differences
+// from prev, the stored section's state from loadSectionStates or nil if it
+// is new, are recorded in section_history, since the replace loses them.
+func saveCourse(ctx context.Context, queries *store.Queries, course CourseData, prev *sectionState) error {
+	changes := diffSection(prev, courseState(course))
+
 	// Determine credit hours (use CreditHourLow, fallback to CreditHours)
 	var creditLow, creditHigh sql.NullInt64
 	if course.CreditHourLow != nil {
@@ -41,6 +45,7 @@ func saveCourse(ctx context.Context, queries *store.Queries, course CourseData)
 		WaitCapacity:            toNullInt64(int64(course.WaitCapacity)),
 		WaitCount:               toNullInt64(int64(course.WaitCount)),
 		IsOpen:                  toNullInt64(boolToInt64(course.OpenSection)),
+		IsLinked:                boolToInt64(course.IsSectionLinked),
 	})
 	if err != nil {
 		return fmt.Errorf("upsert section %s: %w", course.CourseReferenceNumber, err)
@@ -109,6 +114,14 @@ func saveCourse(ctx context.Context, queries *store.Queries, course CourseData)
 		}
 	}
 
+	if err := queries.IndexSectionText(ctx, sectionID); err != nil {
+		return fmt.Errorf("index text for section %d: %w", sectionID, err)
+	}
+
+	if err := recordChanges(ctx, queries, course.Term, course.CourseReferenceNumber, changes); err != nil {
+		return fmt.Errorf("record history for section %d: %w", sectionID, err)
+	}
+
 	return nil
 }
 
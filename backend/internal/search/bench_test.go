@@ -2,8 +2,11 @@ package search
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"testing"
 
+	"schedule-optimizer/internal/store"
 	"schedule-optimizer/internal/testutil"
 )
 
@@ -12,7 +15,7 @@ func BenchmarkSearch_SubjectFilter(b *testing.B) {
 	defer db.Close()
 	testutil.SeedTestData(b, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 	ctx := context.Background()
 	req := SearchRequest{
 		Term:    "202520",
@@ -33,7 +36,7 @@ func BenchmarkSearch_TitleToken(b *testing.B) {
 	defer db.Close()
 	testutil.SeedTestData(b, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 	ctx := context.Background()
 	req := SearchRequest{
 		Term:  "202520",
@@ -54,7 +57,7 @@ func BenchmarkSearch_AllTimeScope(b *testing.B) {
 	defer db.Close()
 	testutil.SeedTestData(b, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 	ctx := context.Background()
 	req := SearchRequest{
 		Subject:      "CSCI",
@@ -75,7 +78,7 @@ func BenchmarkSearch_CombinedFilters(b *testing.B) {
 	defer db.Close()
 	testutil.SeedTestData(b, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 	ctx := context.Background()
 	req := SearchRequest{
 		Term:         "202520",
@@ -92,3 +95,95 @@ func BenchmarkSearch_CombinedFilters(b *testing.B) {
 		}
 	}
 }
+
+// seedBenchSections inserts n sections spread across 20 terms with varied
+// titles and instructors, then rebuilds the full-text index.
+func seedBenchSections(b *testing.B, db *sql.DB, n int) {
+	b.Helper()
+	words := []string{"Data", "Structures", "Introduction", "Advanced", "Systems", "Theory",
+		"Design", "Analysis", "Programming", "Networks", "Biology", "Chemistry", "History", "Writing"}
+	names := []string{"Smith", "Jones", "Brown", "Garcia", "Nguyen", "Patel", "Kim", "Lopez"}
+
+	tx, err := db.Begin()
+	if err != nil {
+		b.Fatal(err)
+	}
+	secStmt, err := tx.Prepare(`INSERT INTO sections (term, crn, subject, course_number, title, credit_hours_low, seats_available, is_open)
+		VALUES (?, ?, 'BNCH', ?, ?, 4, 5, 1)`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	instStmt, err := tx.Prepare(`INSERT INTO instructors (section_id, name, is_primary) VALUES (?, ?, 1)`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := range n {
+		term := fmt.Sprintf("20%02d%d0", 10+i%5, 1+(i/5)%4)
+		title := words[i%len(words)] + " " + words[(i/len(words))%len(words)] + " " + words[(i*7)%len(words)]
+		res, err := secStmt.Exec(term, fmt.Sprintf("B%06d", i), fmt.Sprintf("%03d", 100+i%400), title)
+		if err != nil {
+			b.Fatal(err)
+		}
+		id, _ := res.LastInsertId()
+		if _, err := instStmt.Exec(id, "Dr. "+names[i%len(names)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		b.Fatal(err)
+	}
+	if err := store.New(db).RebuildSectionTextIndex(context.Background()); err != nil {
+		b.Fatal(err)
+	}
+}
+
+// BenchmarkSearch_AllTimeTitle measures the section query for an all-time
+// title search through the full-text index. Compare with
+// BenchmarkSearch_AllTimeTitleLike.
+func BenchmarkSearch_AllTimeTitle(b *testing.B) {
+	db, _ := testutil.SetupTestDB(b)
+	defer db.Close()
+	seedBenchSections(b, db, 50000)
+
+	ctx := context.Background()
+	query := sectionQuery{
+		titleTerms: parseQuery("chemistry writing", nil),
+		limit:      MaxSectionFetch,
+	}
+
+	b.ResetTimer()
+	for b.Loop() {
+		if _, err := query.run(ctx, db); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkSearch_AllTimeTitleLike is the pre-FTS baseline for
+// BenchmarkSearch_AllTimeTitle: the same lookup as a LIKE scan over sections.
+func BenchmarkSearch_AllTimeTitleLike(b *testing.B) {
+	db, _ := testutil.SetupTestDB(b)
+	defer db.Close()
+	seedBenchSections(b, db, 50000)
+
+	ctx := context.Background()
+	query := `SELECT s.id, s.term, s.crn, s.title, i.name
+		FROM sections s
+		LEFT JOIN instructors i ON s.id = i.section_id AND i.is_primary = 1
+		WHERE LOWER(s.title) LIKE '%' || ? || '%' AND LOWER(s.title) LIKE '%' || ? || '%'
+		ORDER BY s.term DESC, s.subject, s.course_number, s.crn
+		LIMIT ?`
+
+	b.ResetTimer()
+	for b.Loop() {
+		rows, err := db.QueryContext(ctx, query, "chemistry", "writing", MaxSectionFetch)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for rows.Next() {
+		}
+		if err := rows.Close(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
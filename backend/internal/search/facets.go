@@ -0,0 +1,225 @@
+package search
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+)
+
+// facetQuery counts distinct courses per facet bucket over a set of section
+// IDs, one UNION branch per facet. The ID list is bound once in the CTE, as
+// a single JSON array.
+const facetQuery = `
+WITH m AS (
+    SELECT id, subject || ':' || course_number AS course
+    FROM sections
+    WHERE id IN (SELECT value FROM json_each(?))
+)
+SELECT 'campus', s.campus, '', COUNT(DISTINCT m.course)
+FROM m JOIN sections s ON s.id = m.id
+WHERE s.campus IS NOT NULL AND s.campus != ''
+GROUP BY s.campus
+UNION ALL
+SELECT 'scheduleType', s.schedule_type, '', COUNT(DISTINCT m.course)
+FROM m JOIN sections s ON s.id = m.id
+WHERE s.schedule_type IS NOT NULL AND s.schedule_type != ''
+GROUP BY s.schedule_type
+UNION ALL
+SELECT 'deliveryMode', s.instructional_method, COALESCE(MAX(s.instructional_method_desc), ''), COUNT(DISTINCT m.course)
+FROM m JOIN sections s ON s.id = m.id
+WHERE s.instructional_method IS NOT NULL AND s.instructional_method != ''
+GROUP BY s.instructional_method
+UNION ALL
+SELECT 'attributes', a.code, COALESCE(MAX(a.description), ''), COUNT(DISTINCT m.course)
+FROM m JOIN section_attributes a ON a.section_id = m.id
+GROUP BY a.code
+UNION ALL
+SELECT 'level', substr(s.course_number, 1, 1) || '00', '', COUNT(DISTINCT m.course)
+FROM m JOIN sections s ON s.id = m.id
+WHERE substr(s.course_number, 1, 1) BETWEEN '1' AND '9'
+GROUP BY substr(s.course_number, 1, 1)
+UNION ALL
+SELECT 'term', s.term, COALESCE(MAX(t.description), ''), COUNT(DISTINCT m.course)
+FROM m JOIN sections s ON s.id = m.id
+LEFT JOIN terms t ON t.code = s.term
+GROUP BY s.term
+UNION ALL
+SELECT 'instructor', i.name, '', COUNT(DISTINCT m.course)
+FROM m JOIN instructors i ON i.section_id = m.id AND i.is_primary = 1
+GROUP BY i.name
+UNION ALL
+SELECT 'availability', CASE WHEN s.seats_available > 0 THEN 'open' ELSE 'full' END, '', COUNT(DISTINCT m.course)
+FROM m JOIN sections s ON s.id = m.id
+GROUP BY CASE WHEN s.seats_available > 0 THEN 'open' ELSE 'full' END`
+
+type gpaBandRange struct {
+	value    string
+	min, max float64
+}
+
+// gpaBands are the GPABand facet buckets, lowest first. A GPA belongs to the
+// band whose min it is at or above and whose max it is below; 4.0 is in the top band.
+var gpaBands = []gpaBandRange{
+	{"0.0-2.5", 0, 2.5},
+	{"2.5-3.0", 2.5, 3.0},
+	{"3.0-3.5", 3.0, 3.5},
+	{"3.5-4.0", 3.5, 4.01},
+}
+
+// gpaBand returns the band for a GPA.
+func gpaBand(gpa float64) string {
+	for _, b := range gpaBands {
+		if gpa >= b.min && gpa < b.max {
+			return b.value
+		}
+	}
+	return ""
+}
+
+// courseGPABand looks up a course's GPA band, or "" without grade data.
+func (s *Service) courseGPABand(subject, courseNumber string) string {
+	if s.gradeService == nil || !s.gradeService.IsLoaded() {
+		return ""
+	}
+	gpa, _, ok := s.gradeService.LookupCourseGPA(subject, courseNumber)
+	if !ok {
+		return ""
+	}
+	return gpaBand(gpa)
+}
+
+// filterGPABands keeps rows whose course GPA is in one of bands. Courses
+// without grade data never match.
+func (s *Service) filterGPABands(rows []*sectionRow, bands []string) []*sectionRow {
+	bandByCourse := make(map[string]string)
+	kept := rows[:0]
+	for _, row := range rows {
+		key := row.Subject + ":" + row.CourseNumber
+		band, ok := bandByCourse[key]
+		if !ok {
+			band = s.courseGPABand(row.Subject, row.CourseNumber)
+			bandByCourse[key] = band
+		}
+		if band != "" && slices.Contains(bands, band) {
+			kept = append(kept, row)
+		}
+	}
+	return kept
+}
+
+// gpaBandFacet counts the courses among rows in each GPA band.
+func (s *Service) gpaBandFacet(rows []*sectionRow) []FacetValue {
+	counts := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		key := row.Subject + ":" + row.CourseNumber
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if band := s.courseGPABand(row.Subject, row.CourseNumber); band != "" {
+			counts[band]++
+		}
+	}
+
+	values := []FacetValue{}
+	for _, b := range gpaBands {
+		if counts[b.value] > 0 {
+			values = append(values, FacetValue{Value: b.value, Count: counts[b.value]})
+		}
+	}
+	return values
+}
+
+// newSearchFacets returns facets with empty (not nil) buckets so they
+// serialize as [].
+func newSearchFacets() SearchFacets {
+	return SearchFacets{
+		Attributes:   []FacetValue{},
+		Campus:       []FacetValue{},
+		ScheduleType: []FacetValue{},
+		DeliveryMode: []FacetValue{},
+		Level:        []FacetValue{},
+		Term:         []FacetValue{},
+		Instructor:   []FacetValue{},
+		Availability: []FacetValue{},
+		GPABand:      []FacetValue{},
+	}
+}
+
+// computeFacets counts the courses among rows for each facet bucket.
+func (s *Service) computeFacets(ctx context.Context, rows []*sectionRow) (SearchFacets, error) {
+	facets := newSearchFacets()
+	if len(rows) == 0 {
+		return facets, nil
+	}
+
+	ids := make([]int64, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+
+	result, err := s.db.QueryContext(ctx, facetQuery, idList(ids))
+	if err != nil {
+		return facets, err
+	}
+	defer result.Close()
+
+	for result.Next() {
+		var facet string
+		var v FacetValue
+		if err := result.Scan(&facet, &v.Value, &v.Label, &v.Count); err != nil {
+			return facets, err
+		}
+		if v.Label == v.Value {
+			v.Label = ""
+		}
+		switch facet {
+		case "campus":
+			facets.Campus = append(facets.Campus, v)
+		case "scheduleType":
+			facets.ScheduleType = append(facets.ScheduleType, v)
+		case "deliveryMode":
+			facets.DeliveryMode = append(facets.DeliveryMode, v)
+		case "attributes":
+			facets.Attributes = append(facets.Attributes, v)
+		case "level":
+			facets.Level = append(facets.Level, v)
+		case "term":
+			facets.Term = append(facets.Term, v)
+		case "instructor":
+			facets.Instructor = append(facets.Instructor, v)
+		case "availability":
+			facets.Availability = append(facets.Availability, v)
+		}
+	}
+	if err := result.Err(); err != nil {
+		return facets, err
+	}
+
+	for _, values := range [][]FacetValue{facets.Attributes, facets.Campus, facets.ScheduleType, facets.DeliveryMode, facets.Instructor, facets.Availability} {
+		sortFacet(values)
+	}
+	slices.SortFunc(facets.Level, func(a, b FacetValue) int {
+		return strings.Compare(a.Value, b.Value)
+	})
+	slices.SortFunc(facets.Term, func(a, b FacetValue) int {
+		return strings.Compare(b.Value, a.Value)
+	})
+	if len(facets.Instructor) > MaxInstructorFacet {
+		facets.Instructor = facets.Instructor[:MaxInstructorFacet]
+	}
+	facets.GPABand = s.gpaBandFacet(rows)
+	return facets, nil
+}
+
+// sortFacet orders buckets by count descending, then value.
+func sortFacet(values []FacetValue) {
+	slices.SortFunc(values, func(a, b FacetValue) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Value, b.Value)
+	})
+}
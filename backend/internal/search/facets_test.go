@@ -0,0 +1,382 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+
+	"schedule-optimizer/internal/stats/grades"
+	"schedule-optimizer/internal/store"
+	"schedule-optimizer/internal/testutil"
+)
+
+// seedSectionProperties gives the seed sections campuses, schedule types,
+// delivery modes, and attributes.
+func seedSectionProperties(t *testing.T, db *sql.DB) {
+	t.Helper()
+	_, err := db.Exec(`
+		UPDATE sections SET campus = 'Bellingham', schedule_type = 'Lecture',
+			instructional_method = 'FTF', instructional_method_desc = 'Face to Face';
+		UPDATE sections SET campus = 'Everett', instructional_method = 'ONL',
+			instructional_method_desc = 'Fully Online' WHERE crn = '20002';
+		UPDATE sections SET schedule_type = 'Laboratory' WHERE crn = '20003';
+
+		INSERT INTO section_attributes (section_id, code, description) VALUES
+			(1, 'QSR', 'Quantitative and Symbolic Reasoning GUR'),
+			(3, 'QSR', 'Quantitative and Symbolic Reasoning GUR'),
+			(3, 'LSCI', 'Lab Science GUR');
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed section properties: %v", err)
+	}
+}
+
+// seedGrades loads course GPAs of 3.6 for CSCI 247 and 2.8 for MATH 204;
+// CSCI 301 has no grade data.
+func seedGrades(t *testing.T, db *sql.DB, queries *store.Queries) *grades.Service {
+	t.Helper()
+	_, err := db.Exec(`
+		INSERT INTO grade_aggregates (level, subject, course_number, instructor,
+			sections_count, students_count, cnt_a, cnt_am, cnt_bp, cnt_b, cnt_bm,
+			cnt_cp, cnt_c, cnt_cm, cnt_dp, cnt_d, cnt_dm, cnt_f, cnt_w, cnt_p, cnt_np, cnt_s, cnt_u,
+			gpa, pass_rate)
+		VALUES
+			('course', 'CSCI', '247', '', 10, 250, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 3.6, NULL),
+			('course', 'MATH', '204', '', 10, 250, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2.8, NULL);
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed grades: %v", err)
+	}
+	gradeService := grades.NewService(db, queries, "")
+	if err := gradeService.LoadFromDB(context.Background()); err != nil {
+		t.Fatalf("failed to load grades: %v", err)
+	}
+	return gradeService
+}
+
+func TestSearch_SectionPropertyFilters(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+	seedSectionProperties(t, db)
+
+	svc := NewService(db, queries, nil, nil)
+
+	tests := []struct {
+		name     string
+		req      SearchRequest
+		expected []string
+	}{
+		{
+			name:     "attribute",
+			req:      SearchRequest{Term: "202520", Attributes: []string{"QSR"}},
+			expected: []string{"202520:20001", "202520:20003"},
+		},
+		{
+			name:     "any of several attributes",
+			req:      SearchRequest{Term: "202520", Attributes: []string{"LSCI,XYZ"}},
+			expected: []string{"202520:20003"},
+		},
+		{
+			name:     "campus",
+			req:      SearchRequest{Term: "202520", Subject: "CSCI", Campus: []string{"Everett"}},
+			expected: []string{"202520:20002"},
+		},
+		{
+			name:     "schedule type",
+			req:      SearchRequest{Term: "202520", CourseNumber: "2", ScheduleType: []string{"Laboratory"}},
+			expected: []string{"202520:20003"},
+		},
+		{
+			name:     "delivery mode",
+			req:      SearchRequest{Term: "202520", Subject: "CSCI", DeliveryMode: []string{"FTF"}},
+			expected: []string{"202520:20001"},
+		},
+		{
+			name:     "filters combine",
+			req:      SearchRequest{Term: "202520", Attributes: []string{"QSR"}, ScheduleType: []string{"Lecture"}},
+			expected: []string{"202520:20001"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.Search(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := sectionKeys(resp); !slices.Equal(got, tt.expected) {
+				t.Errorf("sections = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSearch_Facets(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+	seedSectionProperties(t, db)
+
+	svc := NewService(db, queries, nil, nil)
+
+	// All-time: CSCI 247 has a section in each term but counts once
+	resp, err := svc.Search(context.Background(), SearchRequest{Subject: "CSCI"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		got      []FacetValue
+		expected []FacetValue
+	}{
+		{
+			name: "attributes",
+			got:  resp.Facets.Attributes,
+			expected: []FacetValue{
+				{Value: "QSR", Label: "Quantitative and Symbolic Reasoning GUR", Count: 1},
+			},
+		},
+		{
+			name: "campus",
+			got:  resp.Facets.Campus,
+			expected: []FacetValue{
+				{Value: "Bellingham", Count: 1},
+				{Value: "Everett", Count: 1},
+			},
+		},
+		{
+			name: "schedule type",
+			got:  resp.Facets.ScheduleType,
+			expected: []FacetValue{
+				{Value: "Lecture", Count: 2},
+			},
+		},
+		{
+			name: "delivery mode",
+			got:  resp.Facets.DeliveryMode,
+			expected: []FacetValue{
+				{Value: "FTF", Label: "Face to Face", Count: 1},
+				{Value: "ONL", Label: "Fully Online", Count: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		if !slices.Equal(tt.got, tt.expected) {
+			t.Errorf("%s facet = %+v, expected %+v", tt.name, tt.got, tt.expected)
+		}
+	}
+}
+
+func TestSearch_FacetsEmpty(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(db, queries, nil, nil)
+
+	resp, err := svc.Search(context.Background(), SearchRequest{Term: "202520", Subject: "ZZZZ"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Facets.Campus == nil || resp.Facets.Attributes == nil {
+		t.Error("facets should be empty slices, not nil")
+	}
+}
+
+func TestSearch_AggregateFacets(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(db, queries, nil, seedGrades(t, db, queries))
+
+	resp, err := svc.Search(context.Background(), SearchRequest{Subject: "CSCI"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		got      []FacetValue
+		expected []FacetValue
+	}{
+		{
+			name: "level",
+			got:  resp.Facets.Level,
+			expected: []FacetValue{
+				{Value: "200", Count: 1},
+				{Value: "300", Count: 1},
+			},
+		},
+		{
+			name: "term",
+			got:  resp.Facets.Term,
+			expected: []FacetValue{
+				{Value: "202520", Label: "Spring 2025", Count: 2},
+				{Value: "202510", Label: "Winter 2025", Count: 1},
+			},
+		},
+		{
+			name: "instructor",
+			got:  resp.Facets.Instructor,
+			expected: []FacetValue{
+				{Value: "Dr. Jones", Count: 1},
+				{Value: "Dr. Smith", Count: 1},
+			},
+		},
+		{
+			name: "availability",
+			got:  resp.Facets.Availability,
+			expected: []FacetValue{
+				{Value: "full", Count: 1},
+				{Value: "open", Count: 1},
+			},
+		},
+		{
+			name: "gpa band",
+			got:  resp.Facets.GPABand,
+			expected: []FacetValue{
+				{Value: "3.5-4.0", Count: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		if !slices.Equal(tt.got, tt.expected) {
+			t.Errorf("%s facet = %+v, expected %+v", tt.name, tt.got, tt.expected)
+		}
+	}
+}
+
+func TestSearch_AvailabilityFacetNullSeats(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	// Unknown seats count as full, in the same bucket as zero seats
+	if _, err := db.Exec(`UPDATE sections SET seats_available = NULL WHERE crn = '10001'`); err != nil {
+		t.Fatalf("failed to clear seats: %v", err)
+	}
+
+	svc := NewService(db, queries, nil, nil)
+	resp, err := svc.Search(context.Background(), SearchRequest{Subject: "CSCI"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []FacetValue{
+		{Value: "full", Count: 2},
+		{Value: "open", Count: 1},
+	}
+	if !slices.Equal(resp.Facets.Availability, expected) {
+		t.Errorf("availability facet = %+v, expected %+v", resp.Facets.Availability, expected)
+	}
+}
+
+func TestSearch_AggregateFacetFilters(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(db, queries, nil, seedGrades(t, db, queries))
+
+	tests := []struct {
+		name     string
+		req      SearchRequest
+		expected []string
+	}{
+		{
+			name:     "level",
+			req:      SearchRequest{Term: "202520", Level: []string{"300"}},
+			expected: []string{"202520:20002"},
+		},
+		{
+			name:     "levels as digits",
+			req:      SearchRequest{Term: "202520", Level: []string{"2,3"}},
+			expected: []string{"202520:20001", "202520:20002", "202520:20003"},
+		},
+		{
+			name:     "instructor name",
+			req:      SearchRequest{InstructorName: []string{"Dr. Smith"}},
+			expected: []string{"202510:10001", "202520:20001"},
+		},
+		{
+			name:     "open",
+			req:      SearchRequest{Term: "202520", Subject: "CSCI", Availability: "open"},
+			expected: []string{"202520:20001"},
+		},
+		{
+			name:     "full",
+			req:      SearchRequest{Term: "202520", Subject: "CSCI", Availability: "full"},
+			expected: []string{"202520:20002"},
+		},
+		{
+			// CSCI 301 has no grade data, so it never matches a band
+			name:     "gpa band",
+			req:      SearchRequest{Term: "202520", Level: []string{"2", "3"}, GPABand: []string{"2.5-3.0"}},
+			expected: []string{"202520:20003"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.Search(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := sectionKeys(resp); !slices.Equal(got, tt.expected) {
+				t.Errorf("sections = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSearch_AggregateFacetFilterErrors(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(db, queries, nil, nil)
+
+	tests := []struct {
+		name    string
+		req     SearchRequest
+		wantErr error
+	}{
+		{"bad level", SearchRequest{Level: []string{"350"}}, ErrInvalidLevel},
+		{"zero level", SearchRequest{Level: []string{"0"}}, ErrInvalidLevel},
+		{"bad availability", SearchRequest{Subject: "CSCI", Availability: "waitlist"}, ErrInvalidAvailability},
+		{"bad gpa band", SearchRequest{Subject: "CSCI", GPABand: []string{"4.0"}}, ErrInvalidGPABand},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Search(context.Background(), tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestGPABand(t *testing.T) {
+	tests := []struct {
+		gpa      float64
+		expected string
+	}{
+		{4.0, "3.5-4.0"},
+		{3.5, "3.5-4.0"},
+		{3.49, "3.0-3.5"},
+		{2.5, "2.5-3.0"},
+		{1.2, "0.0-2.5"},
+	}
+	for _, tt := range tests {
+		if got := gpaBand(tt.gpa); got != tt.expected {
+			t.Errorf("gpaBand(%v) = %q, expected %q", tt.gpa, got, tt.expected)
+		}
+	}
+}
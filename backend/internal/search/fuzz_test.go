@@ -2,8 +2,10 @@ package search
 
 import (
 	"context"
+	"slices"
 	"strings"
 	"testing"
+	"unicode"
 
 	"schedule-optimizer/internal/testutil"
 )
@@ -15,7 +17,7 @@ func FuzzSearch(f *testing.F) {
 	defer db.Close()
 	testutil.SeedTestData(f, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 	ctx := context.Background()
 
 	// Seed corpus with known interesting inputs
@@ -57,6 +59,52 @@ func FuzzSearch(f *testing.F) {
 	})
 }
 
+// FuzzParseQuery tests that any title/instructor input parses into a MATCH
+// expression SQLite accepts.
+// Run with: go test -fuzz=FuzzParseQuery -fuzztime=30s ./internal/search/...
+func FuzzParseQuery(f *testing.F) {
+	db, queries := testutil.SetupTestDB(f)
+	defer db.Close()
+	testutil.SeedTestData(f, db)
+
+	svc := NewService(db, queries, nil, nil)
+	ctx := context.Background()
+
+	f.Add("intro compsci", "smith")
+	f.Add("calc", "")
+	f.Add("", "Dr. Smiht")
+	f.Add(`"data" OR structures NEAR/2 -algo*`, "jones AND NOT")
+	f.Add("title:data subject_description:(x)", "^*")
+	f.Add("データ構造", "Ñoño")
+
+	f.Fuzz(func(t *testing.T, title, instructor string) {
+		titleTerms := parseQuery(title, svc.synonyms)
+		instrTerms := parseQuery(instructor, nil)
+		svc.addFuzzyCandidates(ctx, titleTerms, instrTerms)
+
+		for _, term := range append(slices.Clone(titleTerms), instrTerms...) {
+			for _, alt := range term.alts {
+				for _, w := range alt.words {
+					if w == "" || strings.IndexFunc(w, func(r rune) bool {
+						return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+					}) >= 0 {
+						t.Fatalf("unsafe word %q in query for %q/%q", w, title, instructor)
+					}
+				}
+			}
+		}
+
+		if len(titleTerms) == 0 && len(instrTerms) == 0 {
+			return
+		}
+		q := sectionQuery{titleTerms: titleTerms, instrTerms: instrTerms, limit: 10}
+		if _, err := q.run(ctx, db); err != nil {
+			query, args := q.build()
+			t.Fatalf("query for %q/%q failed: %v (match %q in %s)", title, instructor, err, args[0], query)
+		}
+	})
+}
+
 // verifyResponseInvariants checks that a SearchResponse is internally consistent.
 func verifyResponseInvariants(t *testing.T, resp *SearchResponse) {
 	t.Helper()
@@ -154,7 +202,7 @@ func TestSearch_ResponseInvariants(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 	ctx := context.Background()
 
 	tests := []struct {
@@ -212,7 +260,7 @@ func TestSearch_InvariantsWithUnicode(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 	ctx := context.Background()
 
 	unicodeInputs := []string{
@@ -0,0 +1,157 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+)
+
+// Fuzzy matching limits. Short words aren't expanded since almost every
+// three-letter word is within one edit of another.
+const (
+	minFuzzyLength     = 4
+	maxFuzzyCandidates = 3
+)
+
+// vocabTerm is one indexed word and the number of sections containing it.
+type vocabTerm struct {
+	word string
+	docs int
+}
+
+// vocabulary is the set of words in sections_fts, split by what a query field
+// searches: title and subject description, or instructor names. Both slices
+// are sorted by word.
+type vocabulary struct {
+	text  []vocabTerm
+	instr []vocabTerm
+}
+
+// loadVocabulary reads the full-text index vocabulary from sections_fts_terms.
+func loadVocabulary(ctx context.Context, db *sql.DB) (*vocabulary, error) {
+	rows, err := db.QueryContext(ctx, `SELECT term, col, doc FROM sections_fts_terms`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	text := make(map[string]int)
+	instr := make(map[string]int)
+	for rows.Next() {
+		var word string
+		var col string
+		var docs int
+		if err := rows.Scan(&word, &col, &docs); err != nil {
+			return nil, err
+		}
+		if col == "instructors" {
+			instr[word] += docs
+		} else {
+			text[word] += docs
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &vocabulary{text: sortedTerms(text), instr: sortedTerms(instr)}, nil
+}
+
+func sortedTerms(m map[string]int) []vocabTerm {
+	terms := make([]vocabTerm, 0, len(m))
+	for word, docs := range m {
+		terms = append(terms, vocabTerm{word: word, docs: docs})
+	}
+	slices.SortFunc(terms, func(a, b vocabTerm) int {
+		return strings.Compare(a.word, b.word)
+	})
+	return terms
+}
+
+// hasPrefix reports whether any word in terms starts with prefix.
+func hasPrefix(terms []vocabTerm, prefix string) bool {
+	i, _ := slices.BinarySearchFunc(terms, prefix, func(t vocabTerm, p string) int {
+		return strings.Compare(t.word, p)
+	})
+	return i < len(terms) && strings.HasPrefix(terms[i].word, prefix)
+}
+
+// fuzzyCandidates returns up to maxFuzzyCandidates words within edit distance
+// of token: one edit for 4-7 characters, two for longer words. A word also
+// counts if its leading characters are that close, so a misspelled partial
+// word still finds completions. Closest and most common words come first.
+func fuzzyCandidates(terms []vocabTerm, token string) []string {
+	tr := []rune(token)
+	if len(tr) < minFuzzyLength {
+		return nil
+	}
+	maxDist := 1
+	if len(tr) >= 8 {
+		maxDist = 2
+	}
+
+	type candidate struct {
+		word string
+		dist int
+		docs int
+	}
+	var found []candidate
+	for _, t := range terms {
+		wr := []rune(t.word)
+		if len(wr) < len(tr)-maxDist {
+			continue
+		}
+		dist := editDistance(tr, wr)
+		if len(wr) > len(tr) {
+			dist = min(dist, editDistance(tr, wr[:len(tr)]))
+		}
+		if dist <= maxDist {
+			found = append(found, candidate{word: t.word, dist: dist, docs: t.docs})
+		}
+	}
+
+	slices.SortFunc(found, func(a, b candidate) int {
+		if a.dist != b.dist {
+			return a.dist - b.dist
+		}
+		if a.docs != b.docs {
+			return b.docs - a.docs
+		}
+		return strings.Compare(a.word, b.word)
+	})
+
+	words := make([]string, 0, min(len(found), maxFuzzyCandidates))
+	for _, c := range found[:min(len(found), maxFuzzyCandidates)] {
+		words = append(words, c.word)
+	}
+	return words
+}
+
+// editDistance is the optimal string alignment distance between a and b:
+// insertions, deletions, substitutions, and adjacent transpositions each
+// cost one.
+func editDistance(a, b []rune) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(b)]
+}
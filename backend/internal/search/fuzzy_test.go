@@ -0,0 +1,232 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"schedule-optimizer/internal/testutil"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"smith", "smith", 0},
+		{"smith", "smiht", 1}, // transposition
+		{"smith", "smit", 1},
+		{"smith", "smyth", 1},
+		{"algorithms", "algorthims", 2},
+		{"café", "cafe", 1},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance([]rune(tt.a), []rune(tt.b)); got != tt.expected {
+			t.Errorf("editDistance(%q, %q) = %d, expected %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestFuzzyCandidates(t *testing.T) {
+	terms := sortedTerms(map[string]int{
+		"algebra":    3,
+		"algorithms": 2,
+		"calculus":   5,
+		"smith":      4,
+		"smyth":      1,
+	})
+
+	tests := []struct {
+		token    string
+		expected []string
+	}{
+		{"smiht", []string{"smith"}},
+		{"smth", []string{"smith", "smyth"}},
+		{"algoritm", []string{"algorithms"}}, // misspelled partial word
+		{"calclus", []string{"calculus"}},
+		{"xyz", nil}, // too short to expand
+		{"zzzzzz", nil},
+	}
+
+	for _, tt := range tests {
+		got := fuzzyCandidates(terms, tt.token)
+		if len(got) == 0 && len(tt.expected) == 0 {
+			continue
+		}
+		if !slices.Equal(got, tt.expected) {
+			t.Errorf("fuzzyCandidates(%q) = %v, expected %v", tt.token, got, tt.expected)
+		}
+	}
+}
+
+func TestHasPrefix(t *testing.T) {
+	terms := sortedTerms(map[string]int{"data": 1, "structures": 1})
+
+	if !hasPrefix(terms, "struc") {
+		t.Error("expected prefix match for struc")
+	}
+	if hasPrefix(terms, "strux") {
+		t.Error("unexpected prefix match for strux")
+	}
+	if hasPrefix(terms, "zz") {
+		t.Error("unexpected prefix match past end of vocabulary")
+	}
+}
+
+func TestParseSynonyms(t *testing.T) {
+	syn, err := ParseSynonyms(strings.NewReader(`
+# comment
+[subjects]
+cs = CSCI
+
+[words]
+Calc = calculus
+compsci = computer science, comp sci
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := syn.Subjects("cs"); !slices.Equal(got, []string{"CS", "CSCI"}) {
+		t.Errorf("Subjects(cs) = %v", got)
+	}
+	if got := syn.Subjects("MATH"); !slices.Equal(got, []string{"MATH"}) {
+		t.Errorf("Subjects(MATH) = %v", got)
+	}
+	if got := syn.Words("calc"); len(got) != 1 || !slices.Equal(got[0], []string{"calculus"}) {
+		t.Errorf("Words(calc) = %v", got)
+	}
+	if got := syn.Words("compsci"); len(got) != 2 || !slices.Equal(got[0], []string{"computer", "science"}) {
+		t.Errorf("Words(compsci) = %v", got)
+	}
+
+	invalid := []string{
+		"calc = calculus",         // outside a section
+		"[nope]",                  // unknown section
+		"[words]\nno equals sign", // malformed line
+		"[words]\ntwo words = x",  // multi-word key
+	}
+	for _, input := range invalid {
+		if _, err := ParseSynonyms(strings.NewReader(input)); err == nil {
+			t.Errorf("ParseSynonyms(%q) expected error", input)
+		}
+	}
+}
+
+func TestDefaultSynonyms(t *testing.T) {
+	syn := DefaultSynonyms()
+	if got := syn.Subjects("CS"); !slices.Contains(got, "CSCI") {
+		t.Errorf("expected CS -> CSCI in default synonyms, got %v", got)
+	}
+}
+
+func TestSearch_SubjectSynonym(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(db, queries, nil, nil)
+
+	resp, err := svc.Search(context.Background(), SearchRequest{
+		Term:    "202520",
+		Subject: "cs",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Sections) != 2 {
+		t.Errorf("expected 2 CSCI sections for CS, got %d", len(resp.Sections))
+	}
+}
+
+func TestSearch_WordSynonym(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(db, queries, nil, nil)
+	syn, err := ParseSynonyms(strings.NewReader("[words]\nlinalg = linear algebra\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.SetSynonyms(syn)
+
+	resp, err := svc.Search(context.Background(), SearchRequest{
+		Term:  "202520",
+		Title: "linalg",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].CourseKey != "MATH:204" {
+		t.Errorf("expected MATH:204 via synonym, got %v", resp.Results)
+	}
+}
+
+func TestSearch_FuzzyInstructor(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(db, queries, nil, nil)
+
+	resp, err := svc.Search(context.Background(), SearchRequest{
+		Term:       "202520",
+		Instructor: "smiht",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.Sections["202520:20001"]; !ok || len(resp.Sections) != 1 {
+		t.Errorf("expected Dr. Smith's section for misspelled name, got %v", resp.Sections)
+	}
+}
+
+func TestSearch_FuzzyRanksBelowExact(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(db, queries, nil, nil)
+	ctx := context.Background()
+
+	exact, err := svc.Search(ctx, SearchRequest{Term: "202520", Title: "algebra"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fuzzy, err := svc.Search(ctx, SearchRequest{Term: "202520", Title: "algebar"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(exact.Results) != 1 || len(fuzzy.Results) != 1 {
+		t.Fatalf("expected one result each, got %d and %d", len(exact.Results), len(fuzzy.Results))
+	}
+	if fuzzy.Results[0].RelevanceScore >= exact.Results[0].RelevanceScore {
+		t.Errorf("fuzzy score %f should be below exact score %f",
+			fuzzy.Results[0].RelevanceScore, exact.Results[0].RelevanceScore)
+	}
+}
+
+func TestSearch_VocabularyReloadsAfterScrape(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(db, queries, nil, nil)
+	ctx := context.Background()
+
+	if _, err := svc.Search(ctx, SearchRequest{Term: "202520", Title: "data"}); err != nil {
+		t.Fatal(err)
+	}
+	if svc.vocab == nil {
+		t.Fatal("expected vocabulary to be loaded")
+	}
+
+	svc.TermScraped("202520")
+	if svc.vocab != nil {
+		t.Error("expected vocabulary to be dropped after scrape")
+	}
+}
@@ -0,0 +1,115 @@
+package search
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// page selects one page of sorted courses.
+type page struct {
+	sort  []sortKey
+	after *courseSortData // Last course of the previous page, nil for the first page
+	size  int
+	query string // Fingerprint of the request the cursor belongs to
+}
+
+// cursorToken is the JSON inside an opaque cursor. It holds the last
+// returned course's sort values, so the next page starts strictly after it
+// in the same ordering even if earlier courses change between requests.
+type cursorToken struct {
+	Query        string  `json:"q"`
+	Score        float64 `json:"r"`
+	GPA          float64 `json:"g,omitempty"`
+	HasGPA       bool    `json:"h,omitempty"`
+	Seats        int     `json:"n,omitempty"`
+	LatestTerm   string  `json:"t"`
+	Subject      string  `json:"s"`
+	CourseNumber string  `json:"c"`
+	Title        string  `json:"i"`
+}
+
+// parsePage validates the page size and cursor of req.
+func parsePage(req SearchRequest, sortKeys []sortKey) (page, error) {
+	p := page{sort: sortKeys, size: MaxCourseResults, query: queryFingerprint(req)}
+	if req.PageSize != 0 {
+		if req.PageSize < 1 || req.PageSize > MaxCourseResults {
+			return p, fmt.Errorf("%w: %d", ErrInvalidPageSize, req.PageSize)
+		}
+		p.size = req.PageSize
+	}
+	if req.Cursor != "" {
+		after, err := decodeCursor(req.Cursor, p.query)
+		if err != nil {
+			return p, err
+		}
+		p.after = after
+	}
+	return p, nil
+}
+
+// queryFingerprint identifies the filters and ordering of req, so a cursor
+// can't be replayed against a different search.
+func queryFingerprint(req SearchRequest) string {
+	req.Cursor = ""
+	req.PageSize = 0
+	data, _ := json.Marshal(req)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// encodeCursor returns the cursor for the page after course.
+func encodeCursor(course *courseSortData, query string) string {
+	data, _ := json.Marshal(cursorToken{
+		Query:        query,
+		Score:        course.score,
+		GPA:          course.gpa,
+		HasGPA:       course.hasGPA,
+		Seats:        course.seats,
+		LatestTerm:   course.latestTerm,
+		Subject:      course.subject,
+		CourseNumber: course.courseNumber,
+		Title:        course.title,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a cursor issued for the search identified by query.
+func decodeCursor(cursor, query string) (*courseSortData, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var token cursorToken
+	if err := json.Unmarshal(data, &token); err != nil || token.Query != query {
+		return nil, ErrInvalidCursor
+	}
+	return &courseSortData{
+		subject:      token.Subject,
+		courseNumber: token.CourseNumber,
+		title:        token.Title,
+		score:        token.Score,
+		gpa:          token.GPA,
+		hasGPA:       token.HasGPA,
+		seats:        token.Seats,
+		latestTerm:   token.LatestTerm,
+	}, nil
+}
+
+// pageBounds returns the range of sorted courses on page p, and whether
+// more courses follow it.
+func pageBounds(sorted []*courseSortData, p page) (start, end int, more bool) {
+	if p.after != nil {
+		start = len(sorted)
+		if i := slices.IndexFunc(sorted, func(c *courseSortData) bool {
+			return compareCourses(c, p.after, p.sort) > 0
+		}); i >= 0 {
+			start = i
+		}
+	}
+	end = min(start+p.size, len(sorted))
+	return start, end, end < len(sorted)
+}
@@ -0,0 +1,191 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"schedule-optimizer/internal/testutil"
+)
+
+func TestSearch_Pagination(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+	seedConflictingSection(t, db)
+
+	svc := NewService(db, queries, nil, nil)
+	ctx := context.Background()
+
+	for _, sort := range []string{"", "-seats", "title", "-term,subject"} {
+		t.Run("sort="+sort, func(t *testing.T) {
+			req := SearchRequest{Level: []string{"2", "3"}, Sort: sort}
+			full, err := svc.Search(ctx, req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if full.NextCursor != "" {
+				t.Errorf("single page should have no cursor, got %q", full.NextCursor)
+			}
+
+			// Paging one course at a time visits the same courses in the same order
+			var paged []string
+			req.PageSize = 1
+			for range 10 {
+				resp, err := svc.Search(ctx, req)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if resp.Stats.MatchedCourses != len(full.Results) {
+					t.Errorf("matchedCourses = %d, expected %d", resp.Stats.MatchedCourses, len(full.Results))
+				}
+				paged = append(paged, resultKeys(resp)...)
+				if resp.NextCursor == "" {
+					break
+				}
+				req.Cursor = resp.NextCursor
+			}
+			if want := resultKeys(full); !slices.Equal(paged, want) {
+				t.Errorf("paged results = %v, expected %v", paged, want)
+			}
+		})
+	}
+}
+
+func TestSearch_PaginationStableCursor(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(db, queries, nil, nil)
+	ctx := context.Background()
+
+	req := SearchRequest{Term: "202520", Level: []string{"2", "3"}, Sort: "courseNumber", PageSize: 1}
+	first, err := svc.Search(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := resultKeys(first); !slices.Equal(got, []string{"MATH:204"}) {
+		t.Fatalf("first page = %v", got)
+	}
+
+	// Removing a course already shown doesn't shift the next page
+	if _, err := db.Exec(`DELETE FROM sections WHERE crn = '20003'`); err != nil {
+		t.Fatalf("failed to delete section: %v", err)
+	}
+	req.Cursor = first.NextCursor
+	second, err := svc.Search(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := resultKeys(second); !slices.Equal(got, []string{"CSCI:247"}) {
+		t.Errorf("second page = %v, expected [CSCI:247]", got)
+	}
+}
+
+func TestSearch_PaginationErrors(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(db, queries, nil, nil)
+	ctx := context.Background()
+
+	resp, err := svc.Search(ctx, SearchRequest{Subject: "CSCI", PageSize: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.NextCursor == "" {
+		t.Fatal("expected a next cursor")
+	}
+
+	tests := []struct {
+		name    string
+		req     SearchRequest
+		wantErr error
+	}{
+		{"cursor from another search", SearchRequest{Subject: "MATH", Cursor: resp.NextCursor}, ErrInvalidCursor},
+		{"cursor with another sort", SearchRequest{Subject: "CSCI", Sort: "title", Cursor: resp.NextCursor}, ErrInvalidCursor},
+		{"garbage cursor", SearchRequest{Subject: "CSCI", Cursor: "not a cursor"}, ErrInvalidCursor},
+		{"page too large", SearchRequest{Subject: "CSCI", PageSize: MaxCourseResults + 1}, ErrInvalidPageSize},
+		{"negative page", SearchRequest{Subject: "CSCI", PageSize: -1}, ErrInvalidPageSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.Search(ctx, tt.req); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// Page size doesn't change which search a cursor belongs to
+	if _, err := svc.Search(ctx, SearchRequest{Subject: "CSCI", PageSize: 5, Cursor: resp.NextCursor}); err != nil {
+		t.Errorf("cursor with a different page size: %v", err)
+	}
+}
+
+func TestSearch_PagesReuseRanking(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(db, queries, nil, nil)
+	ctx := context.Background()
+
+	req := SearchRequest{Subject: "CSCI", PageSize: 1}
+	first, err := svc.Search(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.Stats.MatchedCourses != 2 {
+		t.Fatalf("matchedCourses = %d, expected 2", first.Stats.MatchedCourses)
+	}
+
+	// A course added after the first page isn't ranked into later ones, but
+	// a page's sections are read fresh
+	_, err = db.Exec(`
+		INSERT INTO sections (term, crn, subject, course_number, title, credit_hours_low, enrollment, max_enrollment, seats_available, is_open)
+		VALUES ('202520', '20005', 'CSCI', '101', 'Intro', 4, 0, 30, 30, 1);
+		UPDATE sections SET seats_available = 7 WHERE crn IN ('20001', '20002');
+	`)
+	if err != nil {
+		t.Fatalf("failed to update sections: %v", err)
+	}
+	req.Cursor = first.NextCursor
+	second, err := svc.Search(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.Stats.MatchedCourses != 2 || len(second.Results) != 1 {
+		t.Errorf("second page matched %d courses with %d results, expected 2 and 1", second.Stats.MatchedCourses, len(second.Results))
+	}
+	for key, section := range second.Sections {
+		if section.Term == "202520" && section.SeatsAvailable != 7 {
+			t.Errorf("section %s seats = %d, expected 7", key, section.SeatsAvailable)
+		}
+	}
+
+	// A scrape drops the ranking
+	svc.TermScraped("202520")
+	req.Cursor = ""
+	resp, err := svc.Search(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Stats.MatchedCourses != 3 {
+		t.Errorf("matchedCourses after scrape = %d, expected 3", resp.Stats.MatchedCourses)
+	}
+
+	// Uncached searches always see the database
+	if _, err := db.Exec(`DELETE FROM sections WHERE crn = '20005'`); err != nil {
+		t.Fatalf("failed to delete section: %v", err)
+	}
+	if resp, err = svc.SearchUncached(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Stats.MatchedCourses != 2 {
+		t.Errorf("uncached matchedCourses = %d, expected 2", resp.Stats.MatchedCourses)
+	}
+}
@@ -0,0 +1,492 @@
+package search
+
+import (
+	"cmp"
+	"container/heap"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ftsRank is FTS5's built-in BM25 with per-column weights for sections_fts
+// (title, subject_description, instructors). It returns lower values for
+// better matches.
+const ftsRank = "bm25(sections_fts, 1.0, 0.5, 1.0)"
+
+// matchKind is how a query word matched: as typed, through a synonym, or
+// through a fuzzy (typo-tolerant) candidate.
+type matchKind int
+
+const (
+	matchExact matchKind = iota
+	matchSynonym
+	matchFuzzy
+)
+
+// matchKindWeights scale BM25 so expansions rank below what was typed.
+var matchKindWeights = [...]float64{
+	matchExact:   1.0,
+	matchSynonym: 0.8,
+	matchFuzzy:   0.5,
+}
+
+// termAlt is one way a query word may match: a single word or a phrase.
+type termAlt struct {
+	words  []string
+	kind   matchKind
+	prefix bool // match the last word as a prefix
+}
+
+// queryTerm is one word of a title or instructor filter. A section must
+// match at least one of its alternatives.
+type queryTerm struct {
+	word string
+	alts []termAlt
+}
+
+// sectionQuery holds the filters for one search query against a single scope.
+type sectionQuery struct {
+	term                *string
+	subjects            []string
+	courseNumberPattern *string
+	titleTerms          []queryTerm
+	instrTerms          []queryTerm
+	openSeats           bool
+	minCredits          *int
+	maxCredits          *int
+	days                []int  // MeetingTimeInfo.Days indexes; meetings must fall only on these
+	startAfter          string // "HHMM"; no meeting may start earlier
+	endBefore           string // "HHMM"; no meeting may end later
+	attributes          []string
+	campuses            []string
+	scheduleTypes       []string
+	deliveryModes       []string
+	levels              []string // Leading course number digits
+	instructorNames     []string
+	availability        string  // "open", "full", or "" for either
+	ids                 []int64 // Only these sections
+	score               func(*sectionRow) float64
+	limit               int // Rows kept, highest score first; 0 keeps every row
+}
+
+// hasText reports whether the query uses the full-text index.
+func (q sectionQuery) hasText() bool {
+	return len(q.titleTerms) > 0 || len(q.instrTerms) > 0
+}
+
+// build returns the SQL and bound args. Text filters go through the
+// sections_fts index instead of scanning sections with LIKE.
+func (q sectionQuery) build() (string, []any) {
+	var sb strings.Builder
+	var conds []string
+	var args []any
+
+	sb.WriteString(`SELECT
+    s.id, s.term, s.crn, s.subject, s.course_number, s.title,
+    s.credit_hours_low, s.credit_hours_high,
+    s.enrollment, s.max_enrollment, s.seats_available, s.wait_count, s.is_open,
+    s.schedule_type, s.campus,
+    i.name AS instructor_name, i.email AS instructor_email`)
+	if q.hasText() {
+		sb.WriteString(`,
+    sections_fts.title, sections_fts.subject_description, sections_fts.instructors,
+    ` + ftsRank + `
+FROM sections_fts
+JOIN sections s ON s.id = sections_fts.rowid`)
+		conds = append(conds, "sections_fts MATCH ?")
+		args = append(args, buildMatchExpr(q.titleTerms, q.instrTerms))
+	} else {
+		sb.WriteString(`
+FROM sections s`)
+	}
+	sb.WriteString(`
+LEFT JOIN instructors i ON s.id = i.section_id AND i.is_primary = 1`)
+
+	// Sections dropped from Banner stay stored for history but aren't offered
+	conds = append(conds, "s.removed_at IS NULL")
+	if len(q.ids) > 0 {
+		conds = append(conds, "s.id IN (SELECT value FROM json_each(?))")
+		args = append(args, idList(q.ids))
+	}
+	if q.term != nil {
+		conds = append(conds, "s.term = ?")
+		args = append(args, *q.term)
+	}
+	if len(q.subjects) > 0 {
+		conds = append(conds, "s.subject IN ("+placeholders(len(q.subjects))+")")
+		args = appendStrings(args, q.subjects)
+	}
+	if q.courseNumberPattern != nil {
+		conds = append(conds, "s.course_number LIKE ?")
+		args = append(args, *q.courseNumberPattern)
+	}
+	if q.openSeats {
+		conds = append(conds, "s.seats_available > 0")
+	}
+	if q.minCredits != nil {
+		conds = append(conds, "s.credit_hours_low >= ?")
+		args = append(args, *q.minCredits)
+	}
+	if q.maxCredits != nil {
+		conds = append(conds, "s.credit_hours_low <= ?")
+		args = append(args, *q.maxCredits)
+	}
+	if len(q.attributes) > 0 {
+		conds = append(conds, "EXISTS (SELECT 1 FROM section_attributes a WHERE a.section_id = s.id AND a.code IN ("+placeholders(len(q.attributes))+"))")
+		args = appendStrings(args, q.attributes)
+	}
+	if len(q.campuses) > 0 {
+		conds = append(conds, "s.campus IN ("+placeholders(len(q.campuses))+")")
+		args = appendStrings(args, q.campuses)
+	}
+	if len(q.scheduleTypes) > 0 {
+		conds = append(conds, "s.schedule_type IN ("+placeholders(len(q.scheduleTypes))+")")
+		args = appendStrings(args, q.scheduleTypes)
+	}
+	if len(q.deliveryModes) > 0 {
+		conds = append(conds, "s.instructional_method IN ("+placeholders(len(q.deliveryModes))+")")
+		args = appendStrings(args, q.deliveryModes)
+	}
+	if len(q.levels) > 0 {
+		var levelConds []string
+		for _, digit := range q.levels {
+			levelConds = append(levelConds, "s.course_number LIKE ?")
+			args = append(args, digit+"%")
+		}
+		conds = append(conds, "("+strings.Join(levelConds, " OR ")+")")
+	}
+	if len(q.instructorNames) > 0 {
+		conds = append(conds, "i.name IN ("+placeholders(len(q.instructorNames))+")")
+		args = appendStrings(args, q.instructorNames)
+	}
+	switch q.availability {
+	case "open":
+		conds = append(conds, "s.seats_available > 0")
+	case "full":
+		conds = append(conds, "COALESCE(s.seats_available, 0) <= 0")
+	}
+	if len(q.days) > 0 {
+		// Meets on at least one allowed day and on none of the others
+		var allowed, other []string
+		for i, col := range dayColumns {
+			if slices.Contains(q.days, i) {
+				allowed = append(allowed, "m."+col+" = 1")
+			} else {
+				other = append(other, "m."+col+" = 1")
+			}
+		}
+		conds = append(conds, "EXISTS (SELECT 1 FROM meeting_times m WHERE m.section_id = s.id AND ("+strings.Join(allowed, " OR ")+"))")
+		if len(other) > 0 {
+			conds = append(conds, "NOT EXISTS (SELECT 1 FROM meeting_times m WHERE m.section_id = s.id AND ("+strings.Join(other, " OR ")+"))")
+		}
+	}
+	if q.startAfter != "" {
+		conds = append(conds, "NOT EXISTS (SELECT 1 FROM meeting_times m WHERE m.section_id = s.id AND m.start_time != '' AND m.start_time < ?)")
+		args = append(args, q.startAfter)
+	}
+	if q.endBefore != "" {
+		conds = append(conds, "NOT EXISTS (SELECT 1 FROM meeting_times m WHERE m.section_id = s.id AND m.end_time != '' AND m.end_time > ?)")
+		args = append(args, q.endBefore)
+	}
+
+	if len(conds) > 0 {
+		sb.WriteString("\nWHERE ")
+		sb.WriteString(strings.Join(conds, "\n    AND "))
+	}
+	return sb.String(), args
+}
+
+// idList encodes section IDs as a JSON array, bound as one parameter and
+// read back with json_each, so long lists don't hit SQLite's parameter limit.
+func idList(ids []int64) string {
+	data, _ := json.Marshal(ids)
+	return string(data)
+}
+
+// placeholders returns n comma-separated bind parameters.
+func placeholders(n int) string {
+	return "?" + strings.Repeat(", ?", n-1)
+}
+
+func appendStrings(args []any, values []string) []any {
+	for _, v := range values {
+		args = append(args, v)
+	}
+	return args
+}
+
+// run executes the query and scans the rows, scoring each with q.score. When
+// more than q.limit rows match, the lowest-scoring are dropped as they're
+// scanned, so the limit cuts the least relevant matches rather than the
+// oldest terms. Rows are returned most recent term first, then by subject,
+// course number, and CRN.
+func (q sectionQuery) run(ctx context.Context, db *sql.DB) ([]*sectionRow, error) {
+	query, args := q.build()
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	top := topRows{limit: q.limit}
+	for rows.Next() {
+		var (
+			r                                                         sectionRow
+			creditsLow, creditsHigh, enrollment, maxEnrollment, seats sql.NullInt64
+			waitCount, isOpen                                         sql.NullInt64
+			scheduleType, campus, instructorName, instructorEmail     sql.NullString
+			ftsTitle, ftsSubject, ftsInstructors                      string
+			rank                                                      float64
+		)
+		dest := []any{
+			&r.ID, &r.Term, &r.CRN, &r.Subject, &r.CourseNumber, &r.Title,
+			&creditsLow, &creditsHigh,
+			&enrollment, &maxEnrollment, &seats, &waitCount, &isOpen,
+			&scheduleType, &campus,
+			&instructorName, &instructorEmail,
+		}
+		if q.hasText() {
+			dest = append(dest, &ftsTitle, &ftsSubject, &ftsInstructors, &rank)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		r.Credits = int(nullInt(creditsLow))
+		r.CreditsHigh = int(nullInt(creditsHigh))
+		r.Enrollment = int(nullInt(enrollment))
+		r.MaxEnrollment = int(nullInt(maxEnrollment))
+		r.SeatsAvailable = int(nullInt(seats))
+		r.WaitCount = int(nullInt(waitCount))
+		r.IsOpen = nullInt(isOpen) == 1
+		r.ScheduleType = nullString(scheduleType)
+		r.Campus = nullString(campus)
+		r.Instructor = nullString(instructorName)
+		r.InstructorEmail = nullString(instructorEmail)
+		if q.hasText() {
+			titleKinds := matchKinds(q.titleTerms, ftsTitle, ftsSubject)
+			instrKinds := matchKinds(q.instrTerms, ftsInstructors)
+			kinds := append(titleKinds, instrKinds...)
+			r.TextScore = -rank * kindWeight(kinds)
+			r.ExactTerms, r.SynonymTerms, r.FuzzyTerms = countMatchKinds(kinds)
+		}
+		if q.score != nil {
+			r.RelevanceScore = q.score(&r)
+		}
+		top.add(&r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return top.rows(), nil
+}
+
+// compareRowOrder orders rows as they're listed: most recent term first,
+// then subject, course number, and CRN.
+func compareRowOrder(a, b *sectionRow) int {
+	return cmp.Or(
+		cmp.Compare(b.Term, a.Term),
+		cmp.Compare(a.Subject, b.Subject),
+		cmp.Compare(a.CourseNumber, b.CourseNumber),
+		cmp.Compare(a.CRN, b.CRN),
+	)
+}
+
+// ranksBefore reports whether a is kept over b when rows are cut to a limit:
+// higher relevance first, then listing order.
+func ranksBefore(a, b *sectionRow) bool {
+	if a.RelevanceScore != b.RelevanceScore {
+		return a.RelevanceScore > b.RelevanceScore
+	}
+	return compareRowOrder(a, b) < 0
+}
+
+// rowHeap is a heap of rows with the lowest-ranked at the root.
+type rowHeap []*sectionRow
+
+func (h rowHeap) Len() int           { return len(h) }
+func (h rowHeap) Less(i, j int) bool { return ranksBefore(h[j], h[i]) }
+func (h rowHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *rowHeap) Push(x any)        { *h = append(*h, x.(*sectionRow)) }
+func (h *rowHeap) Pop() any {
+	old := *h
+	r := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return r
+}
+
+// topRows keeps the limit highest-ranked rows added to it, or every row when
+// limit is 0.
+type topRows struct {
+	limit int
+	heap  rowHeap
+}
+
+func (t *topRows) add(r *sectionRow) {
+	switch {
+	case t.limit <= 0:
+		t.heap = append(t.heap, r)
+	case len(t.heap) < t.limit:
+		heap.Push(&t.heap, r)
+	case ranksBefore(r, t.heap[0]):
+		t.heap[0] = r
+		heap.Fix(&t.heap, 0)
+	}
+}
+
+// rows returns the kept rows in listing order.
+func (t *topRows) rows() []*sectionRow {
+	rows := []*sectionRow(t.heap)
+	slices.SortFunc(rows, compareRowOrder)
+	return rows
+}
+
+// tokenize lowercases input and splits it on anything that isn't a letter or
+// digit, matching the unicode61 tokenizer. Duplicates are dropped.
+func tokenize(input string) []string {
+	fields := splitWords(input)
+	seen := make(map[string]bool, len(fields))
+	tokens := fields[:0]
+	for _, f := range fields {
+		if !seen[f] {
+			seen[f] = true
+			tokens = append(tokens, f)
+		}
+	}
+	return tokens
+}
+
+// splitWords lowercases input and splits it into words as tokenize does,
+// keeping duplicates and order.
+func splitWords(input string) []string {
+	return strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// parseQuery splits a title or instructor filter into query terms. Each word
+// matches as a prefix, and words with synonyms also match their expansions.
+func parseQuery(input string, syn *Synonyms) []queryTerm {
+	tokens := tokenize(input)
+	terms := make([]queryTerm, len(tokens))
+	for i, tok := range tokens {
+		terms[i] = queryTerm{
+			word: tok,
+			alts: []termAlt{{words: []string{tok}, kind: matchExact, prefix: true}},
+		}
+		for _, expansion := range syn.Words(tok) {
+			terms[i].alts = append(terms[i].alts, termAlt{words: expansion, kind: matchSynonym, prefix: true})
+		}
+	}
+	return terms
+}
+
+// buildMatchExpr builds an FTS5 MATCH expression requiring every term. Title
+// words may also match the subject description so "comp" finds Computer
+// Science courses regardless of title wording. Every alternative is a quoted
+// phrase restricted to the term's columns, so query words are never read as
+// FTS5 operators.
+func buildMatchExpr(titleTerms, instrTerms []queryTerm) string {
+	groups := make([]string, 0, len(titleTerms)+len(instrTerms))
+
+	addGroup := func(t queryTerm, cols string) {
+		parts := make([]string, 0, len(t.alts))
+		for _, alt := range t.alts {
+			phrase := `"` + strings.Join(alt.words, " ") + `"`
+			if alt.prefix {
+				phrase += "*"
+			}
+			parts = append(parts, phrase)
+		}
+		groups = append(groups, cols+" : ("+strings.Join(parts, " OR ")+")")
+	}
+
+	for _, t := range titleTerms {
+		addGroup(t, "{title subject_description}")
+	}
+	for _, t := range instrTerms {
+		addGroup(t, "{instructors}")
+	}
+	return strings.Join(groups, " AND ")
+}
+
+// matchKinds reports, for each term, the best way it matched the given
+// column texts, or -1 if none of its alternatives appear. bm25() only scores
+// the row as a whole, so the kinds are recovered by matching the
+// alternatives against the indexed text.
+func matchKinds(terms []queryTerm, texts ...string) []matchKind {
+	cols := make([][]string, len(texts))
+	for i, text := range texts {
+		cols[i] = splitWords(text)
+	}
+
+	kinds := make([]matchKind, len(terms))
+	for i, t := range terms {
+		kinds[i] = -1
+		for _, alt := range t.alts {
+			if kinds[i] >= 0 && kinds[i] <= alt.kind {
+				continue
+			}
+			for _, words := range cols {
+				if alt.matches(words) {
+					kinds[i] = alt.kind
+					break
+				}
+			}
+		}
+	}
+	return kinds
+}
+
+// matches reports whether the alternative's words appear consecutively in
+// words, the last one as a prefix when the alternative is a prefix match.
+func (a termAlt) matches(words []string) bool {
+	n := len(a.words)
+	for start := 0; start+n <= len(words); start++ {
+		ok := true
+		for j, w := range a.words {
+			word := words[start+j]
+			if word != w && !(a.prefix && j == n-1 && strings.HasPrefix(word, w)) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// kindWeight scales a row's BM25 score by how its terms matched, averaging
+// matchKindWeights so expansions rank below what was typed.
+func kindWeight(kinds []matchKind) float64 {
+	if len(kinds) == 0 {
+		return 1
+	}
+	var sum float64
+	for _, k := range kinds {
+		if k >= 0 {
+			sum += matchKindWeights[k]
+		}
+	}
+	return sum / float64(len(kinds))
+}
+
+// countMatchKinds reports how many query terms a row matched as typed,
+// through a synonym only, or through a fuzzy candidate only.
+func countMatchKinds(kinds []matchKind) (exact, synonym, fuzzy int) {
+	for _, k := range kinds {
+		switch k {
+		case matchExact:
+			exact++
+		case matchSynonym:
+			synonym++
+		case matchFuzzy:
+			fuzzy++
+		}
+	}
+	return exact, synonym, fuzzy
+}
@@ -0,0 +1,115 @@
+package search
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	// rankedSearchTTL is how long a search's ranking serves later pages
+	// before the query runs again.
+	rankedSearchTTL = 5 * time.Minute
+
+	// maxRankedSearches caps cached rankings; the oldest is dropped first.
+	maxRankedSearches = 64
+)
+
+// rankedCourse is one matched course: the values it's sorted by and its
+// matched sections in listing order.
+type rankedCourse struct {
+	key        string // "CSCI:247"
+	sort       courseSortData
+	sectionIDs []int64
+}
+
+// rankedSearch is every match of a search, grouped by course and sorted.
+// Only IDs are kept; a page's sections are read again when it's built.
+type rankedSearch struct {
+	courses         []*rankedCourse
+	matchedSections int
+	sectionLimitHit bool
+	facets          SearchFacets
+	rankedAt        time.Time
+}
+
+// rankCourses groups rows by course and sorts the courses by keys, with
+// course number and subject as tiebreakers. A course's relevance is its best
+// section's.
+func (s *Service) rankCourses(rows []*sectionRow, keys []sortKey) []*rankedCourse {
+	byKey := make(map[string]*rankedCourse)
+	var courses []*rankedCourse
+	for _, row := range rows {
+		key := row.Subject + ":" + row.CourseNumber
+		course, ok := byKey[key]
+		if !ok {
+			course = &rankedCourse{
+				key: key,
+				sort: courseSortData{
+					subject:      row.Subject,
+					courseNumber: row.CourseNumber,
+					title:        row.Title,
+				},
+			}
+			if s.gradeService != nil && s.gradeService.IsLoaded() {
+				course.sort.gpa, _, _ = s.gradeService.LookupCourseGPA(row.Subject, row.CourseNumber)
+				course.sort.hasGPA = course.sort.gpa > 0
+			}
+			byKey[key] = course
+			courses = append(courses, course)
+		}
+
+		course.sectionIDs = append(course.sectionIDs, row.ID)
+		if row.RelevanceScore > course.sort.score {
+			course.sort.score = row.RelevanceScore
+		}
+		if row.SeatsAvailable > 0 {
+			course.sort.seats += row.SeatsAvailable
+		}
+		if row.Term > course.sort.latestTerm {
+			course.sort.latestTerm = row.Term
+		}
+	}
+
+	slices.SortFunc(courses, func(a, b *rankedCourse) int {
+		return compareCourses(&a.sort, &b.sort, keys)
+	})
+	return courses
+}
+
+// cachedRanking returns the ranking stored for a query fingerprint, or nil,
+// and the generation to pass to storeRanking.
+func (s *Service) cachedRanking(query string) (*rankedSearch, uint64) {
+	s.rankedMu.Lock()
+	defer s.rankedMu.Unlock()
+	ranked, ok := s.ranked[query]
+	if !ok || time.Since(ranked.rankedAt) >= rankedSearchTTL {
+		return nil, s.rankedGen
+	}
+	return ranked, s.rankedGen
+}
+
+// storeRanking caches a ranking, dropping expired ones and then the oldest
+// if the cache is full. A ranking from before the latest scrape, generation
+// gen, isn't stored.
+func (s *Service) storeRanking(query string, gen uint64, ranked *rankedSearch) {
+	s.rankedMu.Lock()
+	defer s.rankedMu.Unlock()
+	if gen != s.rankedGen {
+		return
+	}
+
+	oldest := ""
+	for q, r := range s.ranked {
+		if time.Since(r.rankedAt) >= rankedSearchTTL {
+			delete(s.ranked, q)
+			continue
+		}
+		if oldest == "" || r.rankedAt.Before(s.ranked[oldest].rankedAt) {
+			oldest = q
+		}
+	}
+	if len(s.ranked) >= maxRankedSearches {
+		delete(s.ranked, oldest)
+	}
+	s.ranked[query] = ranked
+}
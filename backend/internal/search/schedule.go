@@ -0,0 +1,187 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"schedule-optimizer/internal/cache"
+	"schedule-optimizer/internal/generator"
+	"schedule-optimizer/internal/store"
+)
+
+// dayColumns are the meeting_times day flags, indexed like MeetingTimeInfo.Days.
+var dayColumns = [7]string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// dayLetters maps Banner day letters to MeetingTimeInfo.Days indexes.
+var dayLetters = map[rune]int{'U': 0, 'M': 1, 'T': 2, 'W': 3, 'R': 4, 'F': 5, 'S': 6}
+
+// parseDays converts a day string such as "TR" or "mwf" to Days indexes.
+func parseDays(input string) ([]int, error) {
+	var days []int
+	seen := [7]bool{}
+	for _, r := range strings.ToUpper(input) {
+		day, ok := dayLetters[r]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidDays, input)
+		}
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+	return days, nil
+}
+
+// normalizeTime converts "10:30" or "1030" to the "1030" form stored in
+// meeting_times.
+func normalizeTime(input string) (string, error) {
+	t := strings.ReplaceAll(input, ":", "")
+	if len(t) == 3 {
+		t = "0" + t // "930" -> "0930"
+	}
+	if len(t) != 4 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidTime, input)
+	}
+	hours, err1 := strconv.Atoi(t[:2])
+	minutes, err2 := strconv.Atoi(t[2:])
+	if err1 != nil || err2 != nil || hours < 0 || hours > 23 || minutes < 0 || minutes > 59 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidTime, input)
+	}
+	return t, nil
+}
+
+// parseBlockedTimes parses "day:start-end" entries such as "1:0900-1200",
+// using generator.BlockedTime's day numbering (0=Mon through 4=Fri).
+func parseBlockedTimes(entries []string) ([]generator.BlockedTime, error) {
+	blocked := make([]generator.BlockedTime, 0, len(entries))
+	for _, entry := range entries {
+		dayStr, window, ok1 := strings.Cut(entry, ":")
+		start, end, ok2 := strings.Cut(window, "-")
+		day, err := strconv.Atoi(dayStr)
+		if !ok1 || !ok2 || err != nil || day < 0 || day > 4 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidBlockedTime, entry)
+		}
+		startTime, err1 := normalizeTime(start)
+		endTime, err2 := normalizeTime(end)
+		if err1 != nil || err2 != nil || startTime >= endTime {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidBlockedTime, entry)
+		}
+		blocked = append(blocked, generator.BlockedTime{Day: day, StartTime: startTime, EndTime: endTime})
+	}
+	return blocked, nil
+}
+
+// splitList flattens repeated and comma-separated query values.
+func splitList(values []string) []string {
+	var result []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+	return result
+}
+
+// busyMask returns the times already taken by the given sections of term.
+// Sections come from the schedule cache when the term is loaded, otherwise
+// from the database.
+func (s *Service) busyMask(ctx context.Context, term string, crns []string) (generator.TimeMask, error) {
+	var busy generator.TimeMask
+	var ids []int64
+	for _, crn := range crns {
+		if s.cache != nil && s.cache.IsTermLoaded(term) {
+			course, ok := s.cache.GetCourse(term, crn)
+			if !ok {
+				return busy, fmt.Errorf("%w: %s", ErrCRNNotFound, crn)
+			}
+			busy = busy.Merge(generator.CourseMask(course))
+			continue
+		}
+		section, err := s.queries.GetSectionByTermAndCRN(ctx, store.GetSectionByTermAndCRNParams{Term: term, Crn: crn})
+		// Removed sections aren't cached either, so both paths reject them
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && section.RemovedAt.Valid) {
+			return busy, fmt.Errorf("%w: %s", ErrCRNNotFound, crn)
+		}
+		if err != nil {
+			return busy, err
+		}
+		ids = append(ids, section.ID)
+	}
+
+	if len(ids) > 0 {
+		meetings, err := s.meetingsBySection(ctx, ids)
+		if err != nil {
+			return busy, err
+		}
+		for _, m := range meetings {
+			busy = busy.Merge(generator.FromMeetingTimes(m))
+		}
+	}
+	return busy, nil
+}
+
+// filterConflicts drops rows that overlap busy, and the sections in exclude.
+// Cached terms use precomputed masks; other rows load meeting times in one query.
+func (s *Service) filterConflicts(ctx context.Context, rows []*sectionRow, busy generator.TimeMask, exclude map[string]bool) ([]*sectionRow, error) {
+	masks := make(map[int64]generator.TimeMask, len(rows))
+	var uncached []int64
+	for _, row := range rows {
+		if s.cache != nil && s.cache.IsTermLoaded(row.Term) {
+			if course, ok := s.cache.GetCourse(row.Term, row.CRN); ok {
+				masks[row.ID] = generator.CourseMask(course)
+				continue
+			}
+		}
+		uncached = append(uncached, row.ID)
+	}
+
+	if len(uncached) > 0 {
+		meetings, err := s.meetingsBySection(ctx, uncached)
+		if err != nil {
+			return nil, err
+		}
+		for id, m := range meetings {
+			masks[id] = generator.FromMeetingTimes(m)
+		}
+	}
+
+	kept := rows[:0]
+	for _, row := range rows {
+		if exclude[row.Term+":"+row.CRN] || masks[row.ID].Conflicts(busy) {
+			continue
+		}
+		kept = append(kept, row)
+	}
+	return kept, nil
+}
+
+// meetingsBySection loads meeting times in the cache's format for mask building.
+func (s *Service) meetingsBySection(ctx context.Context, ids []int64) (map[int64][]cache.MeetingTime, error) {
+	rows, err := s.queries.GetMeetingTimesBySectionIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	meetings := make(map[int64][]cache.MeetingTime, len(ids))
+	for _, m := range rows {
+		meetings[m.SectionID] = append(meetings[m.SectionID], cache.MeetingTime{
+			Days: [7]bool{
+				m.Sunday.Valid && m.Sunday.Int64 != 0,
+				m.Monday.Valid && m.Monday.Int64 != 0,
+				m.Tuesday.Valid && m.Tuesday.Int64 != 0,
+				m.Wednesday.Valid && m.Wednesday.Int64 != 0,
+				m.Thursday.Valid && m.Thursday.Int64 != 0,
+				m.Friday.Valid && m.Friday.Int64 != 0,
+				m.Saturday.Valid && m.Saturday.Int64 != 0,
+			},
+			StartTime: nullString(m.StartTime),
+			EndTime:   nullString(m.EndTime),
+		})
+	}
+	return meetings, nil
+}
@@ -0,0 +1,271 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+
+	"schedule-optimizer/internal/cache"
+	"schedule-optimizer/internal/generator"
+	"schedule-optimizer/internal/testutil"
+)
+
+// seedConflictingSection adds CSCI 345 (CRN 20004) meeting MWF 1000-1050 in
+// 202520, the same time as CRN 20001.
+func seedConflictingSection(t *testing.T, db *sql.DB) {
+	t.Helper()
+	_, err := db.Exec(`
+		INSERT INTO sections (id, term, crn, subject, course_number, title, credit_hours_low, seats_available, is_open)
+		VALUES (5, '202520', '20004', 'CSCI', '345', 'Operating Systems', 4, 10, 1);
+		INSERT INTO meeting_times (section_id, start_time, end_time, monday, wednesday, friday)
+		VALUES (5, '1000', '1050', 1, 1, 1);
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed section: %v", err)
+	}
+}
+
+func sectionKeys(resp *SearchResponse) []string {
+	var keys []string
+	for key := range resp.Sections {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func TestParseDays(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []int
+		wantErr  bool
+	}{
+		{"TR", []int{2, 4}, false},
+		{"mwf", []int{1, 3, 5}, false},
+		{"MM", []int{1}, false},
+		{"US", []int{0, 6}, false},
+		{"MX", nil, true},
+		{"M,W", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseDays(tt.input)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidDays) {
+				t.Errorf("parseDays(%q) expected ErrInvalidDays, got %v", tt.input, err)
+			}
+			continue
+		}
+		if err != nil || !slices.Equal(got, tt.expected) {
+			t.Errorf("parseDays(%q) = %v, %v; expected %v", tt.input, got, err, tt.expected)
+		}
+	}
+}
+
+func TestNormalizeTime(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"1000", "1000", false},
+		{"10:30", "1030", false},
+		{"930", "0930", false},
+		{"9:30", "0930", false},
+		{"2400", "", true},
+		{"1060", "", true},
+		{"noon", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := normalizeTime(tt.input)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidTime) {
+				t.Errorf("normalizeTime(%q) expected ErrInvalidTime, got %v", tt.input, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.expected {
+			t.Errorf("normalizeTime(%q) = %q, %v; expected %q", tt.input, got, err, tt.expected)
+		}
+	}
+}
+
+func TestParseBlockedTimes(t *testing.T) {
+	got, err := parseBlockedTimes([]string{"0:0900-1000", "4:13:00-17:00"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []generator.BlockedTime{
+		{Day: 0, StartTime: "0900", EndTime: "1000"},
+		{Day: 4, StartTime: "1300", EndTime: "1700"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("parseBlockedTimes = %v, expected %v", got, want)
+	}
+
+	for _, input := range []string{"5:0900-1000", "0:1000-0900", "0:0900", "x:0900-1000", "0900-1000"} {
+		if _, err := parseBlockedTimes([]string{input}); !errors.Is(err, ErrInvalidBlockedTime) {
+			t.Errorf("parseBlockedTimes(%q) expected ErrInvalidBlockedTime, got %v", input, err)
+		}
+	}
+}
+
+func TestSearch_DaysFilter(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(db, queries, nil, nil)
+	ctx := context.Background()
+
+	// 20002 has a meeting time but no days, so it never matches a day filter
+	resp, err := svc.Search(ctx, SearchRequest{Term: "202520", Subject: "CSCI", Days: "MWF"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := sectionKeys(resp); !slices.Equal(got, []string{"202520:20001"}) {
+		t.Errorf("MWF sections = %v", got)
+	}
+
+	// Meeting on a day outside the set excludes the section
+	resp, err = svc.Search(ctx, SearchRequest{Term: "202520", Subject: "CSCI", Days: "MW"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Sections) != 0 {
+		t.Errorf("expected no MW-only sections, got %v", sectionKeys(resp))
+	}
+}
+
+func TestSearch_TimeWindowFilter(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(db, queries, nil, nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		req      SearchRequest
+		expected []string
+	}{
+		{
+			name:     "nothing before 10am",
+			req:      SearchRequest{Term: "202520", CourseNumber: "2*", StartAfter: "10:00"},
+			expected: []string{"202520:20001"},
+		},
+		{
+			name:     "done by 3pm",
+			req:      SearchRequest{Term: "202520", Subject: "CSCI", EndBefore: "1500"},
+			expected: []string{"202520:20001"},
+		},
+		{
+			name:     "afternoon",
+			req:      SearchRequest{Term: "202520", Subject: "CSCI", StartAfter: "1200"},
+			expected: []string{"202520:20002"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.Search(ctx, tt.req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := sectionKeys(resp); !slices.Equal(got, tt.expected) {
+				t.Errorf("sections = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSearch_FitsSchedule(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+	seedConflictingSection(t, db)
+
+	uncached := NewService(db, queries, nil, nil)
+	scheduleCache := cache.NewScheduleCache(queries, nil)
+	if err := scheduleCache.LoadTerm(context.Background(), "202520"); err != nil {
+		t.Fatalf("failed to load term: %v", err)
+	}
+	cached := NewService(db, queries, scheduleCache, nil)
+
+	tests := []struct {
+		name     string
+		req      SearchRequest
+		expected []string
+	}{
+		{
+			// 20004 conflicts with 20001; 20001 itself is excluded as already taken
+			name:     "crns",
+			req:      SearchRequest{Term: "202520", Subject: "CSCI", CRNs: []string{"20001"}},
+			expected: []string{"202520:20002"},
+		},
+		{
+			name:     "non-conflicting crn",
+			req:      SearchRequest{Term: "202520", Subject: "CSCI", CRNs: []string{"20003"}},
+			expected: []string{"202520:20001", "202520:20002", "202520:20004"},
+		},
+		{
+			name:     "blocked monday morning",
+			req:      SearchRequest{Term: "202520", Subject: "CSCI", Blocked: []string{"0:0800-1030"}},
+			expected: []string{"202520:20002"},
+		},
+		{
+			name:     "blocked across terms",
+			req:      SearchRequest{Subject: "CSCI", CourseNumber: "247", Blocked: []string{"2:1000-1100"}},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		for name, svc := range map[string]*Service{"uncached": uncached, "cached": cached} {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				resp, err := svc.Search(context.Background(), tt.req)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got := sectionKeys(resp); !slices.Equal(got, tt.expected) {
+					t.Errorf("sections = %v, expected %v", got, tt.expected)
+				}
+			})
+		}
+	}
+}
+
+func TestSearch_ScheduleFilterErrors(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(db, queries, nil, nil)
+
+	tests := []struct {
+		name    string
+		req     SearchRequest
+		wantErr error
+	}{
+		{"bad days", SearchRequest{Subject: "CSCI", Days: "MXF"}, ErrInvalidDays},
+		{"bad start", SearchRequest{Subject: "CSCI", StartAfter: "25:00"}, ErrInvalidTime},
+		{"bad end", SearchRequest{Subject: "CSCI", EndBefore: "5pm"}, ErrInvalidTime},
+		{"bad block", SearchRequest{Subject: "CSCI", Blocked: []string{"6:0900-1000"}}, ErrInvalidBlockedTime},
+		{"crns without term", SearchRequest{Subject: "CSCI", CRNs: []string{"20001"}}, ErrCRNsRequireTerm},
+		{"unknown crn", SearchRequest{Term: "202520", Subject: "CSCI", CRNs: []string{"99999"}}, ErrCRNNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Search(context.Background(), tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"schedule-optimizer/internal/jobs"
+	"schedule-optimizer/internal/stats/grades"
 )
 
 // Scorer calculates relevance scores for search results.
@@ -108,6 +109,145 @@ func (s *MatchQualityScorer) Name() string {
 	return "match_quality"
 }
 
+// TextRelevanceScorer scales the full-text BM25 score so better title and
+// instructor matches rank above weaker ones.
+type TextRelevanceScorer struct {
+	weight float64
+}
+
+// NewTextRelevanceScorer creates a new text relevance scorer with default settings.
+func NewTextRelevanceScorer() *TextRelevanceScorer {
+	return &TextRelevanceScorer{
+		weight: 10.0,
+	}
+}
+
+// Score implements Scorer.
+func (s *TextRelevanceScorer) Score(section *sectionRow, req *SearchRequest) float64 {
+	return s.weight * section.TextScore
+}
+
+// Name implements Scorer.
+func (s *TextRelevanceScorer) Name() string {
+	return "text_relevance"
+}
+
+// FuzzyMatchScorer ranks sections that matched the search words as typed
+// above ones that only matched through synonyms, and both above typo
+// corrections, which earn nothing.
+type FuzzyMatchScorer struct {
+	exactBonus   float64
+	synonymBonus float64
+}
+
+// NewFuzzyMatchScorer creates a new fuzzy match scorer with default settings.
+func NewFuzzyMatchScorer() *FuzzyMatchScorer {
+	return &FuzzyMatchScorer{
+		exactBonus:   20.0,
+		synonymBonus: 12.0,
+	}
+}
+
+// Score implements Scorer.
+func (s *FuzzyMatchScorer) Score(section *sectionRow, req *SearchRequest) float64 {
+	return s.exactBonus*float64(section.ExactTerms) + s.synonymBonus*float64(section.SynonymTerms)
+}
+
+// Name implements Scorer.
+func (s *FuzzyMatchScorer) Name() string {
+	return "fuzzy_match"
+}
+
+// GPAScorer ranks sections with higher historical GPAs first, using the
+// course+instructor average when there is one. Sections without grade data
+// score 0. Off unless weighted in the request.
+type GPAScorer struct {
+	gradeService *grades.Service
+	maxScore     float64 // Score for a 4.0
+}
+
+// NewGPAScorer creates a new GPA scorer with default settings. gradeService may be nil.
+func NewGPAScorer(gradeService *grades.Service) *GPAScorer {
+	return &GPAScorer{
+		gradeService: gradeService,
+		maxScore:     40.0,
+	}
+}
+
+// Score implements Scorer.
+func (s *GPAScorer) Score(section *sectionRow, req *SearchRequest) float64 {
+	if s.gradeService == nil || !s.gradeService.IsLoaded() {
+		return 0
+	}
+	gpa, _, _ := s.gradeService.LookupSectionGPA(section.Subject, section.CourseNumber, section.Instructor)
+	return s.maxScore * gpa / 4.0
+}
+
+// Name implements Scorer.
+func (s *GPAScorer) Name() string {
+	return "gpa"
+}
+
+// SeatsScorer ranks sections with more open seats first. The score saturates
+// so a 300-seat lecture doesn't bury everything else. Off unless weighted in the request.
+type SeatsScorer struct {
+	maxScore  float64
+	halfSeats float64 // Open seats that earn half of maxScore
+}
+
+// NewSeatsScorer creates a new seats scorer with default settings.
+func NewSeatsScorer() *SeatsScorer {
+	return &SeatsScorer{
+		maxScore:  30.0,
+		halfSeats: 10.0,
+	}
+}
+
+// Score implements Scorer.
+func (s *SeatsScorer) Score(section *sectionRow, req *SearchRequest) float64 {
+	if section.SeatsAvailable <= 0 {
+		return 0
+	}
+	seats := float64(section.SeatsAvailable)
+	return s.maxScore * seats / (seats + s.halfSeats)
+}
+
+// Name implements Scorer.
+func (s *SeatsScorer) Name() string {
+	return "seats"
+}
+
+// OfferedThisTermScorer boosts sections in the current term, so courses
+// students can actually take now rank above past offerings. Unlike
+// RecencyScorer it's a flat bonus. Off unless weighted in the request.
+type OfferedThisTermScorer struct {
+	bonus       float64
+	currentTerm func() string
+}
+
+// NewOfferedThisTermScorer creates a new offered-this-term scorer with default settings.
+func NewOfferedThisTermScorer() *OfferedThisTermScorer {
+	return &OfferedThisTermScorer{
+		bonus: 50.0,
+		currentTerm: func() string {
+			return jobs.CurrentTermCode(time.Now())
+		},
+	}
+}
+
+// Score implements Scorer.
+func (s *OfferedThisTermScorer) Score(section *sectionRow, req *SearchRequest) float64 {
+	if section.Term == s.currentTerm() {
+		return s.bonus
+	}
+	return 0
+}
+
+// Name implements Scorer.
+func (s *OfferedThisTermScorer) Name() string {
+	return "offered_this_term"
+}
+
 // termDistance calculates the number of terms between two term codes.
 func termDistance(term1, term2 string) int {
 	y1, q1, err1 := jobs.ParseTermCode(term1)
@@ -8,133 +8,394 @@ import (
 	"log/slog"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
+	"schedule-optimizer/internal/cache"
+	"schedule-optimizer/internal/generator"
 	"schedule-optimizer/internal/jobs"
 	"schedule-optimizer/internal/stats/grades"
 	"schedule-optimizer/internal/store"
 )
 
 var (
-	ErrNoFilters      = errors.New("at least one search filter is required (subject, courseNumber, title, or instructor)")
-	ErrTooManyTokens  = errors.New("too many search terms (max 3 words per field)")
+	ErrNoFilters      = errors.New("at least one search filter is required (subject, courseNumber, title, instructor, or attributes)")
 	ErrInvalidTerm    = errors.New("invalid term code")
 	ErrInvalidYear    = errors.New("invalid academic year")
 	ErrWildcardOnly   = errors.New("search filter cannot be only wildcards")
 	ErrFilterTooShort = errors.New("search filter must be at least 2 characters (excluding wildcards)")
+
+	ErrInvalidDays        = errors.New("invalid days (use letters U M T W R F S)")
+	ErrInvalidTime        = errors.New("invalid time (use HHMM or HH:MM)")
+	ErrInvalidBlockedTime = errors.New("invalid blocked time (use day:HHMM-HHMM, day 0-4 for Mon-Fri)")
+	ErrCRNsRequireTerm    = errors.New("crns filter requires a term")
+	ErrCRNNotFound        = errors.New("crn not found in term")
+
+	ErrInvalidLevel        = errors.New("invalid level (use 100 through 900)")
+	ErrInvalidAvailability = errors.New("invalid availability (use open or full)")
+	ErrInvalidGPABand      = errors.New("invalid gpaBand (use 0.0-2.5, 2.5-3.0, 3.0-3.5, or 3.5-4.0)")
+
+	ErrInvalidSort   = errors.New("invalid sort key (use relevance, gpa, seats, courseNumber, subject, title, or term, with - for descending)")
+	ErrInvalidWeight = errors.New("invalid scorer weight (use name:weight with a weight from 0 to 10)")
+
+	ErrInvalidCursor   = errors.New("invalid cursor (cursors only work with the search that returned them)")
+	ErrInvalidPageSize = errors.New("invalid pageSize (use 1 to 200)")
 )
 
 // Service handles course search operations.
 type Service struct {
 	db           *sql.DB
 	queries      *store.Queries
+	cache        *cache.ScheduleCache // Optional; cached terms use precomputed time masks
 	scorers      []Scorer
 	gradeService *grades.Service
+	synonyms     *Synonyms
+
+	vocabMu sync.Mutex
+	vocab   *vocabulary // loaded on first text search, dropped after scrapes
+
+	rankedMu  sync.Mutex
+	ranked    map[string]*rankedSearch // Query fingerprint -> ranking, dropped after scrapes
+	rankedGen uint64                   // Increases on every scrape, so rankings read before one aren't stored
 }
 
 // NewService creates a new search service.
-func NewService(db *sql.DB, queries *store.Queries, gradeService *grades.Service) *Service {
+func NewService(db *sql.DB, queries *store.Queries, scheduleCache *cache.ScheduleCache, gradeService *grades.Service) *Service {
 	return &Service{
 		db:           db,
 		queries:      queries,
+		cache:        scheduleCache,
 		gradeService: gradeService,
+		synonyms:     DefaultSynonyms(),
+		ranked:       make(map[string]*rankedSearch),
 		scorers: []Scorer{
 			NewRecencyScorer(),
 			NewMatchQualityScorer(),
+			NewTextRelevanceScorer(),
+			NewFuzzyMatchScorer(),
+			NewGPAScorer(gradeService),
+			NewSeatsScorer(),
+			NewOfferedThisTermScorer(),
 		},
 	}
 }
 
-// Search performs a course search with the given parameters.
+// SetSynonyms replaces the synonym dictionary. Call before serving requests.
+func (s *Service) SetSynonyms(syn *Synonyms) {
+	s.synonyms = syn
+}
+
+// TermScraped implements jobs.ScrapeListener. New sections may add words, so
+// the fuzzy-match vocabulary is reloaded on the next search, and cached
+// rankings are dropped.
+func (s *Service) TermScraped(term string) {
+	s.vocabMu.Lock()
+	s.vocab = nil
+	s.vocabMu.Unlock()
+
+	s.rankedMu.Lock()
+	clear(s.ranked)
+	s.rankedGen++
+	s.rankedMu.Unlock()
+}
+
+// Search performs a course search with the given parameters. A ranking
+// cached by an earlier page of the same search is reused.
 func (s *Service) Search(ctx context.Context, req SearchRequest) (*SearchResponse, error) {
+	return s.search(ctx, req, true)
+}
+
+// SearchUncached is Search without the ranking cache, for callers that need
+// results as of now, such as saved-search alerts.
+func (s *Service) SearchUncached(ctx context.Context, req SearchRequest) (*SearchResponse, error) {
+	return s.search(ctx, req, false)
+}
+
+func (s *Service) search(ctx context.Context, req SearchRequest, cached bool) (*SearchResponse, error) {
 	startTime := time.Now()
 
 	if err := s.validateRequest(req); err != nil {
 		return nil, err
 	}
-
-	titleTokens, err := splitTokens(req.Title, MaxTokens)
+	sortKeys, err := parseSort(req.Sort)
 	if err != nil {
-		return nil, fmt.Errorf("title: %w", err)
+		return nil, err
 	}
-	instrTokens, err := splitTokens(req.Instructor, MaxTokens)
+	weights, err := s.parseWeights(splitList(req.Weights))
 	if err != nil {
-		return nil, fmt.Errorf("instructor: %w", err)
+		return nil, err
+	}
+	page, err := parsePage(req, sortKeys)
+	if err != nil {
+		return nil, err
 	}
 
+	// Later pages of a recent search reuse its ranking
+	ranked, gen := s.cachedRanking(page.query)
+	if ranked != nil && cached {
+		return s.buildResponse(ctx, ranked, page, startTime)
+	}
+
+	titleTerms := parseQuery(req.Title, s.synonyms)
+	instrTerms := parseQuery(req.Instructor, nil)
+
 	terms, err := s.resolveTerms(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
-	courseNumberPattern := buildCourseNumberPattern(req.CourseNumber)
+	// A text filter with no searchable characters can't match anything.
+	if (req.Title != "" && len(titleTerms) == 0) || (req.Instructor != "" && len(instrTerms) == 0) {
+		return s.buildResponse(ctx, &rankedSearch{facets: newSearchFacets()}, page, startTime)
+	}
 
-	var allResults []*store.SearchSectionsRow
-	var sectionLimitHit bool
+	s.addFuzzyCandidates(ctx, titleTerms, instrTerms)
+
+	query := sectionQuery{
+		courseNumberPattern: buildCourseNumberPattern(req.CourseNumber),
+		titleTerms:          titleTerms,
+		instrTerms:          instrTerms,
+		openSeats:           req.OpenSeats,
+		minCredits:          req.MinCredits,
+		maxCredits:          req.MaxCredits,
+		limit:               MaxSectionFetch,
+	}
+	// Rows are scored as they're scanned so the fetch limit keeps the most relevant
+	query.score = func(row *sectionRow) float64 {
+		var total float64
+		for _, scorer := range s.scorers {
+			if weight := weights[scorer.Name()]; weight != 0 {
+				total += weight * scorer.Score(row, &req)
+			}
+		}
+		return total
+	}
+	if req.Subject != "" {
+		query.subjects = s.synonyms.Subjects(req.Subject)
+	}
+	query.attributes = splitList(req.Attributes)
+	query.campuses = splitList(req.Campus)
+	query.scheduleTypes = splitList(req.ScheduleType)
+	query.deliveryModes = splitList(req.DeliveryMode)
+	query.instructorNames = splitList(req.InstructorName)
+	gpaBands, err := applyFacetFilters(&query, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyScheduleFilters(&query, req); err != nil {
+		return nil, err
+	}
 
-	if len(terms) == 0 {
-		// All-time search (no term filter)
-		results, err := s.executeSearch(ctx, nil, req, courseNumberPattern, titleTokens, instrTokens, MaxSectionFetch)
+	// "Fits my schedule": resolve busy times before querying so bad input fails fast
+	crns := splitList(req.CRNs)
+	if len(crns) > 0 && req.Term == "" {
+		return nil, ErrCRNsRequireTerm
+	}
+	blocked, err := parseBlockedTimes(splitList(req.Blocked))
+	if err != nil {
+		return nil, err
+	}
+	busy := generator.FromBlockedTimes(blocked)
+	if len(crns) > 0 {
+		crnMask, err := s.busyMask(ctx, req.Term, crns)
 		if err != nil {
 			return nil, err
 		}
-		allResults = results
-	} else if len(terms) == 1 {
-		// Single term search
-		results, err := s.executeSearch(ctx, &terms[0], req, courseNumberPattern, titleTokens, instrTokens, MaxSectionFetch)
+		busy = busy.Merge(crnMask)
+	}
+
+	var rows []*sectionRow
+	var sectionLimitHit bool
+
+	if len(terms) <= 1 {
+		// Single term, or all-time search when no term filter
+		if len(terms) == 1 {
+			query.term = &terms[0]
+		}
+		results, err := query.run(ctx, s.db)
 		if err != nil {
 			return nil, err
 		}
-		allResults = results
+		rows = results
 	} else {
-		// Multi-term search (year scope) - search each term and combine
+		// Multi-term search (year scope) - search each term and keep the
+		// most relevant across all of them
+		top := topRows{limit: MaxSectionFetch}
 		for _, term := range terms {
-			termCopy := term
-			results, err := s.executeSearch(ctx, &termCopy, req, courseNumberPattern, titleTokens, instrTokens, MaxSectionFetch)
+			query.term = &term
+			results, err := query.run(ctx, s.db)
 			if err != nil {
 				return nil, err
 			}
-			allResults = append(allResults, results...)
-		}
-		// Safety limit after combining
-		if len(allResults) > MaxSectionFetch {
-			allResults = allResults[:MaxSectionFetch]
+			for _, row := range results {
+				top.add(row)
+			}
 		}
+		rows = top.rows()
 	}
 
-	if len(allResults) >= MaxSectionFetch {
+	if len(rows) >= MaxSectionFetch {
 		sectionLimitHit = true
 	}
 
-	// Convert to sectionRow for scoring and grouping
-	rows := make([]*sectionRow, len(allResults))
-	for i, row := range allResults {
-		rows[i] = rowToSectionRow(row)
+	if len(crns) > 0 || len(blocked) > 0 {
+		exclude := make(map[string]bool, len(crns))
+		for _, crn := range crns {
+			exclude[req.Term+":"+crn] = true
+		}
+		rows, err = s.filterConflicts(ctx, rows, busy, exclude)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(gpaBands) > 0 {
+		rows = s.filterGPABands(rows, gpaBands)
+	}
+
+	// Facets cover every match, not just the returned page
+	facets, err := s.computeFacets(ctx, rows)
+	if err != nil {
+		slog.Error("Failed to compute search facets", "error", err, "sectionCount", len(rows))
+		facets = newSearchFacets()
 	}
 
-	// Apply scoring to all sections
-	for _, row := range rows {
-		var totalScore float64
-		for _, scorer := range s.scorers {
-			totalScore += scorer.Score(row, &req)
+	ranked = &rankedSearch{
+		courses:         s.rankCourses(rows, page.sort),
+		matchedSections: len(rows),
+		sectionLimitHit: sectionLimitHit,
+		facets:          facets,
+		rankedAt:        time.Now(),
+	}
+	if cached {
+		s.storeRanking(page.query, gen, ranked)
+	}
+	return s.buildResponse(ctx, ranked, page, startTime)
+}
+
+// applyFacetFilters validates the level and availability filters and adds
+// them to query. GPA bands can't be filtered in SQL, so they're returned for
+// filterGPABands.
+func applyFacetFilters(query *sectionQuery, req SearchRequest) ([]string, error) {
+	for _, level := range splitList(req.Level) {
+		// "300" or just "3"
+		if (len(level) != 1 && level[1:] != "00") || level[0] < '1' || level[0] > '9' {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidLevel, level)
+		}
+		query.levels = append(query.levels, level[:1])
+	}
+
+	switch req.Availability {
+	case "", "open", "full":
+		query.availability = req.Availability
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAvailability, req.Availability)
+	}
+
+	bands := splitList(req.GPABand)
+	for _, band := range bands {
+		if !slices.ContainsFunc(gpaBands, func(b gpaBandRange) bool { return b.value == band }) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidGPABand, band)
 		}
-		row.RelevanceScore = totalScore
 	}
+	return bands, nil
+}
 
-	return s.buildResponse(ctx, rows, sectionLimitHit, startTime)
+// applyScheduleFilters validates the day and time-of-day filters and adds
+// them to query.
+func applyScheduleFilters(query *sectionQuery, req SearchRequest) error {
+	var err error
+	if req.Days != "" {
+		if query.days, err = parseDays(req.Days); err != nil {
+			return err
+		}
+	}
+	if req.StartAfter != "" {
+		if query.startAfter, err = normalizeTime(req.StartAfter); err != nil {
+			return err
+		}
+	}
+	if req.EndBefore != "" {
+		if query.endBefore, err = normalizeTime(req.EndBefore); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// buildResponse groups sections by course and builds the normalized response.
-func (s *Service) buildResponse(ctx context.Context, rows []*sectionRow, sectionLimitHit bool, startTime time.Time) (*SearchResponse, error) {
-	// Batch-fetch meeting times for all sections in one query
-	sectionIDs := make([]int64, len(rows))
-	idToKey := make(map[int64]string, len(rows))
-	for i, row := range rows {
-		sectionIDs[i] = row.ID
-		idToKey[row.ID] = row.Term + ":" + row.CRN
+// addFuzzyCandidates gives query words that match nothing in the index, and
+// have no synonyms, alternatives within a small edit distance. Search still
+// runs without them if the vocabulary can't be loaded.
+func (s *Service) addFuzzyCandidates(ctx context.Context, titleTerms, instrTerms []queryTerm) {
+	if len(titleTerms) == 0 && len(instrTerms) == 0 {
+		return
+	}
+
+	vocab, err := s.vocabulary(ctx)
+	if err != nil {
+		slog.Warn("Fuzzy search unavailable", "error", err)
+		return
 	}
 
-	meetingsByKey := make(map[string][]MeetingTimeInfo, len(rows))
+	expand := func(terms []queryTerm, vocabTerms []vocabTerm) {
+		for i := range terms {
+			t := &terms[i]
+			if len(t.alts) > 1 || hasPrefix(vocabTerms, t.word) {
+				continue
+			}
+			for _, word := range fuzzyCandidates(vocabTerms, t.word) {
+				t.alts = append(t.alts, termAlt{words: []string{word}, kind: matchFuzzy})
+			}
+		}
+	}
+	expand(titleTerms, vocab.text)
+	expand(instrTerms, vocab.instr)
+}
+
+// vocabulary returns the index vocabulary, loading it if needed.
+func (s *Service) vocabulary(ctx context.Context) (*vocabulary, error) {
+	s.vocabMu.Lock()
+	defer s.vocabMu.Unlock()
+
+	if s.vocab == nil {
+		vocab, err := loadVocabulary(ctx, s.db)
+		if err != nil {
+			return nil, err
+		}
+		s.vocab = vocab
+	}
+	return s.vocab, nil
+}
+
+// buildResponse builds the normalized response for one page of a ranked
+// search, reading the page's sections from the database.
+func (s *Service) buildResponse(ctx context.Context, ranked *rankedSearch, p page, startTime time.Time) (*SearchResponse, error) {
+	sorted := make([]*courseSortData, len(ranked.courses))
+	for i, course := range ranked.courses {
+		sorted[i] = &course.sort
+	}
+	start, end, more := pageBounds(sorted, p)
+	pageCourses := ranked.courses[start:end]
+
+	var sectionIDs []int64
+	for _, course := range pageCourses {
+		sectionIDs = append(sectionIDs, course.sectionIDs...)
+	}
+	rowsByID := make(map[int64]*sectionRow, len(sectionIDs))
+	if len(sectionIDs) > 0 {
+		rows, err := sectionQuery{ids: sectionIDs}.run(ctx, s.db)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			rowsByID[row.ID] = row
+		}
+	}
+	idToKey := make(map[int64]string, len(rowsByID))
+	for id, row := range rowsByID {
+		idToKey[id] = row.Term + ":" + row.CRN
+	}
+
+	meetingsByKey := make(map[string][]MeetingTimeInfo, len(sectionIDs))
 	meetingRows, err := s.queries.GetMeetingTimesBySectionIDs(ctx, sectionIDs)
 	if err != nil {
 		slog.Error("Failed to fetch meeting times for search results", "error", err, "sectionCount", len(sectionIDs))
@@ -158,152 +419,100 @@ func (s *Service) buildResponse(ctx context.Context, rows []*sectionRow, section
 		})
 	}
 
-	courses := make(map[string]CourseInfo)
-	sections := make(map[string]SectionInfo)
+	courses := make(map[string]CourseInfo, len(pageCourses))
+	sections := make(map[string]SectionInfo, len(sectionIDs))
+	results := make([]CourseRef, 0, len(pageCourses))
 
-	// Track course refs with their max scores for ordering
-	type courseRefData struct {
-		courseKey    string
-		sectionKeys []string
-		maxScore    float64
-	}
-	courseRefMap := make(map[string]*courseRefData)
+	for _, course := range pageCourses {
+		sectionKeys := make([]string, 0, len(course.sectionIDs))
 
-	for _, row := range rows {
-		courseKey := row.Subject + ":" + row.CourseNumber
-		sectionKey := row.Term + ":" + row.CRN // Unique across terms
+		for _, id := range course.sectionIDs {
+			row, ok := rowsByID[id]
+			if !ok {
+				continue // Removed from Banner since the search was ranked
+			}
+			if _, ok := courses[course.key]; !ok {
+				ci := CourseInfo{
+					Subject:      row.Subject,
+					CourseNumber: row.CourseNumber,
+					Title:        row.Title,
+					Credits:      row.Credits,
+					CreditsHigh:  row.CreditsHigh,
+				}
+				if s.gradeService != nil && s.gradeService.IsLoaded() {
+					ci.GPA, ci.PassRate, _ = s.gradeService.LookupCourseGPA(row.Subject, row.CourseNumber)
+				}
+				courses[course.key] = ci
+			}
+			sectionKey := row.Term + ":" + row.CRN // Unique across terms
 
-		// Add course if not seen
-		if _, exists := courses[courseKey]; !exists {
-			ci := CourseInfo{
-				Subject:      row.Subject,
-				CourseNumber: row.CourseNumber,
-				Title:        row.Title,
-				Credits:      row.Credits,
-				CreditsHigh:  row.CreditsHigh,
+			// Add section (keyed by term:crn for cross-term uniqueness)
+			meetings := meetingsByKey[sectionKey]
+			if meetings == nil {
+				meetings = []MeetingTimeInfo{}
 			}
-			if s.gradeService != nil && s.gradeService.IsLoaded() {
-				ci.GPA, ci.PassRate, _ = s.gradeService.LookupCourseGPA(row.Subject, row.CourseNumber)
+			si := SectionInfo{
+				CRN:             row.CRN,
+				Term:            row.Term,
+				CourseKey:       course.key,
+				Instructor:      row.Instructor,
+				InstructorEmail: row.InstructorEmail,
+				Enrollment:      row.Enrollment,
+				MaxEnrollment:   row.MaxEnrollment,
+				SeatsAvailable:  row.SeatsAvailable,
+				WaitCount:       row.WaitCount,
+				IsOpen:          row.IsOpen,
+				Campus:          row.Campus,
+				ScheduleType:    row.ScheduleType,
+				MeetingTimes:    meetings,
 			}
-			courses[courseKey] = ci
-			courseRefMap[courseKey] = &courseRefData{
-				courseKey:    courseKey,
-				sectionKeys: []string{},
-				maxScore:    0,
+			if s.gradeService != nil && s.gradeService.IsLoaded() {
+				si.GPA, si.PassRate, si.GPASource = s.gradeService.LookupSectionGPA(
+					row.Subject, row.CourseNumber, row.Instructor,
+				)
 			}
+			sections[sectionKey] = si
+			sectionKeys = append(sectionKeys, sectionKey)
 		}
-
-		// Add section (keyed by term:crn for cross-term uniqueness)
-		meetings := meetingsByKey[sectionKey]
-		if meetings == nil {
-			meetings = []MeetingTimeInfo{}
-		}
-		si := SectionInfo{
-			CRN:             row.CRN,
-			Term:            row.Term,
-			CourseKey:       courseKey,
-			Instructor:      row.Instructor,
-			InstructorEmail: row.InstructorEmail,
-			Enrollment:      row.Enrollment,
-			MaxEnrollment:   row.MaxEnrollment,
-			SeatsAvailable:  row.SeatsAvailable,
-			WaitCount:       row.WaitCount,
-			IsOpen:          row.IsOpen,
-			Campus:          row.Campus,
-			ScheduleType:    row.ScheduleType,
-			MeetingTimes:    meetings,
-		}
-		if s.gradeService != nil && s.gradeService.IsLoaded() {
-			si.GPA, si.PassRate, si.GPASource = s.gradeService.LookupSectionGPA(
-				row.Subject, row.CourseNumber, row.Instructor,
-			)
-		}
-		sections[sectionKey] = si
-
-		// Update course ref
-		ref := courseRefMap[courseKey]
-		ref.sectionKeys = append(ref.sectionKeys, sectionKey)
-		if row.RelevanceScore > ref.maxScore {
-			ref.maxScore = row.RelevanceScore
+		if len(sectionKeys) == 0 {
+			continue
 		}
-	}
 
-	// Build results slice sorted by relevance score
-	results := make([]CourseRef, 0, len(courseRefMap))
-	for _, ref := range courseRefMap {
 		results = append(results, CourseRef{
-			CourseKey:      ref.courseKey,
-			SectionKeys:    ref.sectionKeys,
-			RelevanceScore: ref.maxScore,
+			CourseKey:      course.key,
+			SectionKeys:    sectionKeys,
+			RelevanceScore: course.sort.score,
 		})
 	}
 
-	// Sort by relevance score descending, then course number + subject as tiebreakers
-	slices.SortFunc(results, func(a, b CourseRef) int {
-		if a.RelevanceScore > b.RelevanceScore {
-			return -1
-		}
-		if a.RelevanceScore < b.RelevanceScore {
-			return 1
-		}
-		aParts := strings.SplitN(a.CourseKey, ":", 2)
-		bParts := strings.SplitN(b.CourseKey, ":", 2)
-		// Course number asc
-		if c := strings.Compare(aParts[1], bParts[1]); c != 0 {
-			return c
-		}
-		// Subject asc
-		return strings.Compare(aParts[0], bParts[0])
-	})
-
-	// Build warning from whichever limits were hit
-	courseLimitHit := len(results) > MaxCourseResults
-	if courseLimitHit {
-		results = results[:MaxCourseResults]
-
-		// Remove sections and courses that are no longer in results
-		keepCourses := make(map[string]bool)
-		keepSectionKeys := make(map[string]bool)
-		for _, ref := range results {
-			keepCourses[ref.CourseKey] = true
-			for _, sectionKey := range ref.SectionKeys {
-				keepSectionKeys[sectionKey] = true
-			}
-		}
-
-		for key := range courses {
-			if !keepCourses[key] {
-				delete(courses, key)
-			}
-		}
-		for sectionKey := range sections {
-			if !keepSectionKeys[sectionKey] {
-				delete(sections, sectionKey)
-			}
+	var warning, nextCursor string
+	if ranked.sectionLimitHit {
+		warning = SectionWarningMessage
+		// The cut keeps the most relevant rows, so any other order only
+		// covers those rather than every match
+		if p.sort[0].field != SortRelevance {
+			warning = SortWarningMessage
 		}
 	}
-
-	var warning string
-	switch {
-	case sectionLimitHit && courseLimitHit:
-		warning = SectionWarningMessage + " " + CourseWarningMessage
-	case sectionLimitHit:
-		warning = SectionWarningMessage
-	case courseLimitHit:
-		warning = CourseWarningMessage
+	if more {
+		nextCursor = encodeCursor(&pageCourses[len(pageCourses)-1].sort, p.query)
 	}
 
 	return &SearchResponse{
-		Courses:  courses,
-		Sections: sections,
-		Results:  results,
-		Total:    len(results),
-		Warning:  warning,
+		Courses:    courses,
+		Sections:   sections,
+		Results:    results,
+		Total:      len(results),
+		NextCursor: nextCursor,
+		Warning:    warning,
+		Facets:     ranked.facets,
 		Stats: SearchStats{
 			TotalSections:   len(sections),
 			TotalCourses:    len(courses),
+			MatchedSections: ranked.matchedSections,
+			MatchedCourses:  len(ranked.courses),
 			TimeMs:          float64(time.Since(startTime).Microseconds()) / 1000.0,
-			SectionLimitHit: sectionLimitHit,
+			SectionLimitHit: ranked.sectionLimitHit,
 		},
 	}, nil
 }
@@ -315,8 +524,11 @@ func (s *Service) validateRequest(req SearchRequest) error {
 	courseNumValid := isValidFilter(req.CourseNumber, 1) // Allow single digit for level search
 	titleValid := isValidFilter(req.Title, 2)
 	instructorValid := isValidFilter(req.Instructor, 2)
+	attributesValid := len(splitList(req.Attributes)) > 0 // e.g. every GUR course in a term
+	// Facet filters narrow like a course number level or instructor search
+	facetValid := len(splitList(req.Level)) > 0 || len(splitList(req.InstructorName)) > 0
 
-	hasFilter := subjectValid || courseNumValid || titleValid || instructorValid
+	hasFilter := subjectValid || courseNumValid || titleValid || instructorValid || attributesValid || facetValid
 
 	if !hasFilter {
 		// Check if they provided filters that were rejected
@@ -366,93 +578,6 @@ func (s *Service) resolveTerms(ctx context.Context, req SearchRequest) ([]string
 	return nil, nil
 }
 
-// executeSearch runs the sqlc query with the given parameters.
-func (s *Service) executeSearch(
-	ctx context.Context,
-	term *string,
-	req SearchRequest,
-	courseNumberPattern *string,
-	titleTokens, instrTokens []any,
-	limit int,
-) ([]*store.SearchSectionsRow, error) {
-	params := store.SearchSectionsParams{
-		ResultLimit: int64(limit),
-	}
-
-	// Set term filter
-	if term != nil {
-		params.Term = *term
-	}
-
-	// Set subject filter
-	if req.Subject != "" {
-		params.Subject = strings.ToUpper(req.Subject)
-	}
-
-	// Set course number pattern
-	if courseNumberPattern != nil {
-		params.CourseNumber = *courseNumberPattern
-	}
-
-	// Set title tokens
-	if len(titleTokens) >= 1 {
-		params.TitleT1 = titleTokens[0]
-	}
-	if len(titleTokens) >= 2 {
-		params.TitleT2 = titleTokens[1]
-	}
-	if len(titleTokens) >= 3 {
-		params.TitleT3 = titleTokens[2]
-	}
-
-	// Set instructor tokens
-	if len(instrTokens) >= 1 {
-		params.InstrT1 = instrTokens[0]
-	}
-	if len(instrTokens) >= 2 {
-		params.InstrT2 = instrTokens[1]
-	}
-	if len(instrTokens) >= 3 {
-		params.InstrT3 = instrTokens[2]
-	}
-
-	// Set open seats filter
-	if req.OpenSeats {
-		params.OpenSeats = 1
-	}
-
-	// Set credit range
-	if req.MinCredits != nil {
-		params.MinCredits = int64(*req.MinCredits)
-	}
-	if req.MaxCredits != nil {
-		params.MaxCredits = int64(*req.MaxCredits)
-	}
-
-	return s.queries.SearchSections(ctx, params)
-}
-
-// splitTokens splits input on spaces and hyphens into up to max tokens.
-func splitTokens(input string, max int) ([]any, error) {
-	if input == "" {
-		return nil, nil
-	}
-
-	tokens := strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
-		return r == ' ' || r == '-'
-	})
-
-	if len(tokens) > max {
-		return nil, ErrTooManyTokens
-	}
-
-	result := make([]any, len(tokens))
-	for i, t := range tokens {
-		result[i] = t
-	}
-	return result, nil
-}
-
 // buildCourseNumberPattern converts user input to a LIKE pattern.
 // Wildcards (*) can appear anywhere and are converted to SQL %.
 // Examples:
@@ -489,29 +614,6 @@ func buildCourseNumberPattern(input string) *string {
 	return &input
 }
 
-// rowToSectionRow converts a database row to the internal sectionRow type.
-func rowToSectionRow(row *store.SearchSectionsRow) *sectionRow {
-	return &sectionRow{
-		ID:              row.ID,
-		CRN:             row.Crn,
-		Term:            row.Term,
-		Subject:         row.Subject,
-		CourseNumber:    row.CourseNumber,
-		Title:           row.Title,
-		Credits:         int(nullInt(row.CreditHoursLow)),
-		CreditsHigh:     int(nullInt(row.CreditHoursHigh)),
-		Enrollment:      int(nullInt(row.Enrollment)),
-		MaxEnrollment:   int(nullInt(row.MaxEnrollment)),
-		SeatsAvailable:  int(nullInt(row.SeatsAvailable)),
-		WaitCount:       int(nullInt(row.WaitCount)),
-		IsOpen:          nullInt(row.IsOpen) == 1,
-		Campus:          nullString(row.Campus),
-		ScheduleType:    nullString(row.ScheduleType),
-		Instructor:      nullString(row.InstructorName),
-		InstructorEmail: nullString(row.InstructorEmail),
-	}
-}
-
 // Helper functions for null types
 func nullString(s sql.NullString) string {
 	if s.Valid {
@@ -2,6 +2,7 @@ package search
 
 import (
 	"context"
+	"slices"
 	"strings"
 	"testing"
 
@@ -13,7 +14,7 @@ func TestSearch_NoFilters(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	_, err := svc.Search(context.Background(), SearchRequest{
 		Term: "202520",
@@ -29,7 +30,7 @@ func TestSearch_WildcardOnlyFilter(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	// Wildcard-only course number should be rejected
 	_, err := svc.Search(context.Background(), SearchRequest{
@@ -67,7 +68,7 @@ func TestSearch_FilterMinimumLength(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	// Single character subject should be rejected (min 2)
 	_, err := svc.Search(context.Background(), SearchRequest{
@@ -100,29 +101,130 @@ func TestSearch_FilterMinimumLength(t *testing.T) {
 	}
 }
 
-func TestSearch_TooManyTokens(t *testing.T) {
+func TestSearch_ManyTokens(t *testing.T) {
 	db, queries := testutil.SetupTestDB(t)
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
-	_, err := svc.Search(context.Background(), SearchRequest{
+	// No token cap: every token must prefix-match somewhere in the title
+	resp, err := svc.Search(context.Background(), SearchRequest{
 		Term:  "202520",
-		Title: "one two three four",
+		Title: "dat data struc structures",
 	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Sections) != 1 {
+		t.Errorf("expected 1 section, got %d", len(resp.Sections))
+	}
+	if _, ok := resp.Sections["202520:20001"]; !ok {
+		t.Error("expected CRN 20001 in results")
+	}
+}
+
+func TestSearch_TitlePrefix(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(db, queries, nil, nil)
 
-	if err == nil || err.Error() != "title: too many search terms (max 3 words per field)" {
-		t.Errorf("expected too many tokens error for title, got %v", err)
+	resp, err := svc.Search(context.Background(), SearchRequest{
+		Title: "algo",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].CourseKey != "CSCI:301" {
+		t.Errorf("expected CSCI:301 from prefix match, got %v", resp.Results)
 	}
 
-	_, err = svc.Search(context.Background(), SearchRequest{
-		Term:       "202520",
-		Instructor: "a b c d",
+	// Mid-word fragments don't match the index
+	resp, err = svc.Search(context.Background(), SearchRequest{
+		Title: "gorithm",
 	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Results) != 0 {
+		t.Errorf("expected no results for mid-word fragment, got %d", len(resp.Results))
+	}
+}
 
-	if err == nil || err.Error() != "instructor: too many search terms (max 3 words per field)" {
-		t.Errorf("expected too many tokens error for instructor, got %v", err)
+func TestSearch_TextScore(t *testing.T) {
+	db, _ := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	rows, err := sectionQuery{
+		titleTerms: parseQuery("data", nil),
+		limit:      MaxSectionFetch,
+	}.run(context.Background(), db)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	for _, r := range rows {
+		if r.TextScore <= 0 {
+			t.Errorf("expected positive text score for CRN %s, got %f", r.CRN, r.TextScore)
+		}
+	}
+}
+
+func TestSectionQuery_LimitKeepsMostRelevant(t *testing.T) {
+	db, _ := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	// The older term scores higher, so it's kept over more recent ones
+	query := sectionQuery{
+		subjects: []string{"CSCI", "MATH"},
+		score: func(r *sectionRow) float64 {
+			if r.Term == "202510" {
+				return 2
+			}
+			if r.CRN == "20003" {
+				return 1
+			}
+			return 0
+		},
+		limit: 2,
+	}
+	rows, err := query.run(context.Background(), db)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, r := range rows {
+		got = append(got, r.CRN)
+	}
+	// Kept rows are listed most recent term first
+	if !slices.Equal(got, []string{"20003", "10001"}) {
+		t.Errorf("kept rows = %v, expected [20003 10001]", got)
+	}
+}
+
+func TestSearch_NoSearchableCharacters(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(db, queries, nil, nil)
+
+	resp, err := svc.Search(context.Background(), SearchRequest{
+		Term:    "202520",
+		Subject: "CSCI",
+		Title:   "!!",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Results) != 0 {
+		t.Errorf("expected no results, got %d", len(resp.Results))
 	}
 }
 
@@ -131,7 +233,7 @@ func TestSearch_InvalidTerm(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	_, err := svc.Search(context.Background(), SearchRequest{
 		Term:    "999999",
@@ -148,7 +250,7 @@ func TestSearch_SubjectFilter(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	resp, err := svc.Search(context.Background(), SearchRequest{
 		Term:    "202520",
@@ -189,7 +291,7 @@ func TestSearch_CourseNumberExact(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	resp, err := svc.Search(context.Background(), SearchRequest{
 		Term:         "202520",
@@ -220,7 +322,7 @@ func TestSearch_CourseNumberWildcard(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	// Test explicit wildcard "2*"
 	resp, err := svc.Search(context.Background(), SearchRequest{
@@ -250,7 +352,7 @@ func TestSearch_CourseNumberAutoWildcard(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	// Test auto-wildcard for 1-2 digit input
 	resp, err := svc.Search(context.Background(), SearchRequest{
@@ -272,7 +374,7 @@ func TestSearch_TitleToken(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	resp, err := svc.Search(context.Background(), SearchRequest{
 		Term:  "202520",
@@ -300,7 +402,7 @@ func TestSearch_InstructorToken(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	resp, err := svc.Search(context.Background(), SearchRequest{
 		Term:       "202520",
@@ -327,7 +429,7 @@ func TestSearch_InstructorMultipleTokens(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	// "Dr Smith" should match "Dr. Smith" (both tokens must match)
 	resp, err := svc.Search(context.Background(), SearchRequest{
@@ -349,7 +451,7 @@ func TestSearch_OpenSeatsFilter(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	// Without open_seats filter
 	resp, err := svc.Search(context.Background(), SearchRequest{
@@ -386,12 +488,38 @@ func TestSearch_OpenSeatsFilter(t *testing.T) {
 	}
 }
 
+func TestSearch_ExcludesRemoved(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	if _, err := db.Exec(`UPDATE sections SET removed_at = CURRENT_TIMESTAMP WHERE crn = '20002'`); err != nil {
+		t.Fatalf("update section: %v", err)
+	}
+
+	svc := NewService(db, queries, nil, nil)
+	for _, req := range []SearchRequest{
+		{Term: "202520", Subject: "CSCI"},
+		{Term: "202520", Title: "Algorithms"},
+	} {
+		resp, err := svc.Search(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, section := range resp.Sections {
+			if section.CRN == "20002" {
+				t.Errorf("%+v: removed section returned", req)
+			}
+		}
+	}
+}
+
 func TestSearch_CreditRange(t *testing.T) {
 	db, queries := testutil.SetupTestDB(t)
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	minCredits := 5
 	resp, err := svc.Search(context.Background(), SearchRequest{
@@ -416,7 +544,7 @@ func TestSearch_CombinedFilters(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	resp, err := svc.Search(context.Background(), SearchRequest{
 		Term:         "202520",
@@ -439,7 +567,7 @@ func TestSearch_NoResults(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	resp, err := svc.Search(context.Background(), SearchRequest{
 		Term:    "202520",
@@ -472,7 +600,7 @@ func TestSearch_NoMeetingTimes(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	resp, err := svc.Search(context.Background(), SearchRequest{
 		Term:         "202520",
@@ -507,7 +635,7 @@ func TestSearch_AllTimeScope(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	// Search without term (all-time)
 	resp, err := svc.Search(context.Background(), SearchRequest{
@@ -547,7 +675,7 @@ func TestSearch_SingleTermScoring(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	// Single term search should still have relevance scores (from MatchQualityScorer)
 	resp, err := svc.Search(context.Background(), SearchRequest{
@@ -572,7 +700,7 @@ func TestSearch_CourseGrouping(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	// Search for CSCI without term - should group sections by course
 	resp, err := svc.Search(context.Background(), SearchRequest{
@@ -623,7 +751,7 @@ func TestSearch_ResponseStructure(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	resp, err := svc.Search(context.Background(), SearchRequest{
 		Term:    "202520",
@@ -724,7 +852,7 @@ func TestSearch_YearScope(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	// Search with year scope (2025 = Fall 2024 through Summer 2025)
 	// Our test data has terms 202520 (Spring 2025) and 202510 (Winter 2025)
@@ -749,7 +877,7 @@ func TestSearch_SectionKeyUniqueness(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	// Search across terms for CSCI 247 which exists in both 202520 and 202510
 	resp, err := svc.Search(context.Background(), SearchRequest{
@@ -790,7 +918,7 @@ func TestSearch_StatsPopulated(t *testing.T) {
 	defer db.Close()
 	testutil.SeedTestData(t, db)
 
-	svc := NewService(db, queries, nil)
+	svc := NewService(db, queries, nil, nil)
 
 	resp, err := svc.Search(context.Background(), SearchRequest{
 		Term:    "202520",
@@ -815,39 +943,65 @@ func TestSearch_StatsPopulated(t *testing.T) {
 
 // Helper function tests
 
-func TestSplitTokens(t *testing.T) {
+func TestTokenize(t *testing.T) {
 	tests := []struct {
 		input    string
-		max      int
-		expected int
-		wantErr  bool
+		expected []string
 	}{
-		{"", 3, 0, false},
-		{"hello", 3, 1, false},
-		{"hello world", 3, 2, false},
-		{"hello-world", 3, 2, false},
-		{"hello world again", 3, 3, false},
-		{"a b c d", 3, 0, true}, // Too many tokens
-		{"See-Mong Tan", 3, 3, false},
+		{"", nil},
+		{"hello", []string{"hello"}},
+		{"Hello World", []string{"hello", "world"}},
+		{"hello-world", []string{"hello", "world"}},
+		{"a b c d e", []string{"a", "b", "c", "d", "e"}},
+		{"See-Mong Tan", []string{"see", "mong", "tan"}},
+		{"data data", []string{"data"}},
+		{`"OR" NEAR*`, []string{"or", "near"}},
+		{"***", nil},
 	}
 
 	for _, tt := range tests {
-		tokens, err := splitTokens(tt.input, tt.max)
-		if tt.wantErr {
-			if err == nil {
-				t.Errorf("splitTokens(%q, %d) expected error", tt.input, tt.max)
-			}
-		} else {
-			if err != nil {
-				t.Errorf("splitTokens(%q, %d) unexpected error: %v", tt.input, tt.max, err)
-			}
-			if len(tokens) != tt.expected {
-				t.Errorf("splitTokens(%q, %d) = %d tokens, expected %d", tt.input, tt.max, len(tokens), tt.expected)
-			}
+		tokens := tokenize(tt.input)
+		if !slices.Equal(tokens, tt.expected) {
+			t.Errorf("tokenize(%q) = %v, expected %v", tt.input, tokens, tt.expected)
 		}
 	}
 }
 
+func TestBuildMatchExpr(t *testing.T) {
+	title := []queryTerm{{
+		word: "cs",
+		alts: []termAlt{
+			{words: []string{"cs"}, kind: matchExact, prefix: true},
+			{words: []string{"computer", "science"}, kind: matchSynonym, prefix: true},
+		},
+	}}
+	instr := []queryTerm{{
+		word: "smiht",
+		alts: []termAlt{
+			{words: []string{"smiht"}, kind: matchExact, prefix: true},
+			{words: []string{"smith"}, kind: matchFuzzy},
+		},
+	}}
+
+	got := buildMatchExpr(title, instr)
+	want := `{title subject_description} : ("cs"* OR "computer science"*) AND {instructors} : ("smiht"* OR "smith")`
+	if got != want {
+		t.Errorf("buildMatchExpr = %q, expected %q", got, want)
+	}
+
+	kinds := matchKinds(title, "Intro to Computer Science", "Computer Science")
+	if !slices.Equal(kinds, []matchKind{matchSynonym}) {
+		t.Errorf("title kinds = %v, expected synonym", kinds)
+	}
+	kinds = matchKinds(instr, "Jane Smith")
+	if !slices.Equal(kinds, []matchKind{matchFuzzy}) {
+		t.Errorf("instructor kinds = %v, expected fuzzy", kinds)
+	}
+	if kinds := matchKinds(title, "CS Seminar", "Science Computer"); !slices.Equal(kinds, []matchKind{matchExact}) {
+		t.Errorf("kinds = %v, expected exact to beat out-of-order synonym words", kinds)
+	}
+}
+
 func TestBuildCourseNumberPattern(t *testing.T) {
 	tests := []struct {
 		input    string
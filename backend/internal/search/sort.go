@@ -0,0 +1,138 @@
+package search
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Sort keys accepted in SearchRequest.Sort.
+const (
+	SortRelevance    = "relevance"
+	SortGPA          = "gpa"
+	SortSeats        = "seats"
+	SortCourseNumber = "courseNumber"
+	SortSubject      = "subject"
+	SortTitle        = "title"
+	SortTerm         = "term"
+)
+
+// sortKey is one parsed sort field. desc reverses the field's natural order.
+type sortKey struct {
+	field string
+	desc  bool
+}
+
+// defaultSort ranks by relevance, best first.
+var defaultSort = []sortKey{{field: SortRelevance, desc: true}}
+
+// MaxScorerWeight caps per-request scorer weights.
+const MaxScorerWeight = 10.0
+
+// defaultScorerWeights holds weights for scorers that aren't at 1 by
+// default. The opt-in scorers change ranking a lot, so they only apply when
+// a request weights them.
+var defaultScorerWeights = map[string]float64{
+	"gpa":               0,
+	"seats":             0,
+	"offered_this_term": 0,
+}
+
+// parseSort parses a comma-separated list of sort keys such as
+// "-gpa,courseNumber". A leading "-" sorts that key descending.
+func parseSort(input string) ([]sortKey, error) {
+	if input == "" {
+		return defaultSort, nil
+	}
+	var keys []sortKey
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		key := sortKey{field: strings.TrimPrefix(part, "-"), desc: strings.HasPrefix(part, "-")}
+		switch key.field {
+		case SortRelevance, SortGPA, SortSeats, SortCourseNumber, SortSubject, SortTitle, SortTerm:
+			keys = append(keys, key)
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrInvalidSort, part)
+		}
+	}
+	return keys, nil
+}
+
+// parseWeights parses "name:weight" entries into per-scorer weights, starting
+// from the defaults. A weight of 0 turns a scorer off.
+func (s *Service) parseWeights(entries []string) (map[string]float64, error) {
+	weights := make(map[string]float64, len(s.scorers))
+	for _, scorer := range s.scorers {
+		weight, ok := defaultScorerWeights[scorer.Name()]
+		if !ok {
+			weight = 1
+		}
+		weights[scorer.Name()] = weight
+	}
+
+	for _, entry := range entries {
+		name, value, ok := strings.Cut(entry, ":")
+		if _, known := weights[name]; !ok || !known {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidWeight, entry)
+		}
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil || weight < 0 || weight > MaxScorerWeight {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidWeight, entry)
+		}
+		weights[name] = weight
+	}
+	return weights, nil
+}
+
+// courseSortData holds the per-course values that sort keys compare.
+type courseSortData struct {
+	subject      string
+	courseNumber string
+	title        string
+	score        float64 // Best section relevance score
+	gpa          float64
+	hasGPA       bool
+	seats        int    // Open seats across matched sections
+	latestTerm   string // Most recent matched term
+}
+
+// compareCourses orders a before b by keys, then by course number and
+// subject. Courses without grade data sort last by GPA in either direction.
+func compareCourses(a, b *courseSortData, keys []sortKey) int {
+	for _, key := range keys {
+		var c int
+		switch key.field {
+		case SortRelevance:
+			c = cmp.Compare(a.score, b.score)
+		case SortGPA:
+			if a.hasGPA != b.hasGPA {
+				if a.hasGPA {
+					return -1
+				}
+				return 1
+			}
+			c = cmp.Compare(a.gpa, b.gpa)
+		case SortSeats:
+			c = cmp.Compare(a.seats, b.seats)
+		case SortCourseNumber:
+			c = strings.Compare(a.courseNumber, b.courseNumber)
+		case SortSubject:
+			c = strings.Compare(a.subject, b.subject)
+		case SortTitle:
+			c = strings.Compare(strings.ToLower(a.title), strings.ToLower(b.title))
+		case SortTerm:
+			c = strings.Compare(a.latestTerm, b.latestTerm)
+		}
+		if key.desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	if c := strings.Compare(a.courseNumber, b.courseNumber); c != 0 {
+		return c
+	}
+	return strings.Compare(a.subject, b.subject)
+}
@@ -0,0 +1,199 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"schedule-optimizer/internal/testutil"
+)
+
+func resultKeys(resp *SearchResponse) []string {
+	keys := make([]string, len(resp.Results))
+	for i, ref := range resp.Results {
+		keys[i] = ref.CourseKey
+	}
+	return keys
+}
+
+func TestParseSort(t *testing.T) {
+	got, err := parseSort("-gpa, courseNumber")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []sortKey{{field: SortGPA, desc: true}, {field: SortCourseNumber}}
+	if !slices.Equal(got, want) {
+		t.Errorf("parseSort = %v, expected %v", got, want)
+	}
+
+	if got, _ := parseSort(""); !slices.Equal(got, defaultSort) {
+		t.Errorf("empty sort = %v, expected default", got)
+	}
+
+	for _, input := range []string{"easiest", "gpa,", "+gpa"} {
+		if _, err := parseSort(input); !errors.Is(err, ErrInvalidSort) {
+			t.Errorf("parseSort(%q) expected ErrInvalidSort, got %v", input, err)
+		}
+	}
+}
+
+func TestParseWeights(t *testing.T) {
+	svc := NewService(nil, nil, nil, nil)
+
+	weights, err := svc.parseWeights([]string{"gpa:2", "recency:0"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]float64{"gpa": 2, "recency": 0, "match_quality": 1, "seats": 0}
+	for name, weight := range expected {
+		if weights[name] != weight {
+			t.Errorf("weight %s = %v, expected %v", name, weights[name], weight)
+		}
+	}
+
+	for _, entry := range []string{"gpa", "unknown:1", "gpa:x", "gpa:-1", "gpa:11"} {
+		if _, err := svc.parseWeights([]string{entry}); !errors.Is(err, ErrInvalidWeight) {
+			t.Errorf("parseWeights(%q) expected ErrInvalidWeight, got %v", entry, err)
+		}
+	}
+}
+
+func TestSearch_Sort(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(db, queries, nil, seedGrades(t, db, queries))
+
+	tests := []struct {
+		name     string
+		sort     string
+		expected []string
+	}{
+		{"course number", "courseNumber", []string{"MATH:204", "CSCI:247", "CSCI:301"}},
+		{"course number descending", "-courseNumber", []string{"CSCI:301", "CSCI:247", "MATH:204"}},
+		// CSCI 301 has no grade data and sorts last either way
+		{"easiest first", "-gpa", []string{"CSCI:247", "MATH:204", "CSCI:301"}},
+		{"hardest first", "gpa", []string{"MATH:204", "CSCI:247", "CSCI:301"}},
+		{"most seats", "-seats", []string{"MATH:204", "CSCI:247", "CSCI:301"}},
+		{"subject then title", "subject,title", []string{"CSCI:301", "CSCI:247", "MATH:204"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.Search(context.Background(), SearchRequest{Term: "202520", Level: []string{"2", "3"}, Sort: tt.sort})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := resultKeys(resp); !slices.Equal(got, tt.expected) {
+				t.Errorf("results = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSearch_Weights(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(db, queries, nil, seedGrades(t, db, queries))
+	ctx := context.Background()
+
+	// Without weights every course scores the same for a level filter
+	resp, err := svc.Search(ctx, SearchRequest{Term: "202520", Level: []string{"2", "3"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := resultKeys(resp); !slices.Equal(got, []string{"MATH:204", "CSCI:247", "CSCI:301"}) {
+		t.Errorf("unweighted results = %v", got)
+	}
+
+	resp, err = svc.Search(ctx, SearchRequest{Term: "202520", Level: []string{"2", "3"}, Weights: []string{"gpa:1"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := resultKeys(resp); !slices.Equal(got, []string{"CSCI:247", "MATH:204", "CSCI:301"}) {
+		t.Errorf("gpa-weighted results = %v", got)
+	}
+
+	resp, err = svc.Search(ctx, SearchRequest{Term: "202520", Level: []string{"2", "3"}, Weights: []string{"seats:1"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := resultKeys(resp); !slices.Equal(got, []string{"MATH:204", "CSCI:247", "CSCI:301"}) {
+		t.Errorf("seat-weighted results = %v", got)
+	}
+	if resp.Results[2].RelevanceScore != 0 {
+		t.Errorf("full section should score 0, got %f", resp.Results[2].RelevanceScore)
+	}
+}
+
+func TestSeatsScorer(t *testing.T) {
+	scorer := NewSeatsScorer()
+	req := &SearchRequest{}
+
+	few := scorer.Score(&sectionRow{SeatsAvailable: 2}, req)
+	many := scorer.Score(&sectionRow{SeatsAvailable: 50}, req)
+	if few >= many {
+		t.Errorf("expected more seats to score higher, got %f >= %f", few, many)
+	}
+	if many >= scorer.maxScore {
+		t.Errorf("expected score below max, got %f", many)
+	}
+	if got := scorer.Score(&sectionRow{SeatsAvailable: -3}, req); got != 0 {
+		t.Errorf("expected 0 for no seats, got %f", got)
+	}
+}
+
+func TestOfferedThisTermScorer(t *testing.T) {
+	scorer := NewOfferedThisTermScorer()
+	scorer.currentTerm = func() string { return "202520" }
+	req := &SearchRequest{}
+
+	if got := scorer.Score(&sectionRow{Term: "202520"}, req); got != scorer.bonus {
+		t.Errorf("current term score = %f, expected %f", got, scorer.bonus)
+	}
+	if got := scorer.Score(&sectionRow{Term: "202510"}, req); got != 0 {
+		t.Errorf("past term score = %f, expected 0", got)
+	}
+}
+
+func TestGPAScorer_NoGrades(t *testing.T) {
+	if got := NewGPAScorer(nil).Score(&sectionRow{Subject: "CSCI", CourseNumber: "247"}, &SearchRequest{}); got != 0 {
+		t.Errorf("expected 0 without grade data, got %f", got)
+	}
+}
+
+func TestSearch_SortWarningAtLimit(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	svc := NewService(db, queries, nil, nil)
+	ranked := &rankedSearch{sectionLimitHit: true, facets: newSearchFacets()}
+
+	tests := []struct {
+		sort     string
+		expected string
+	}{
+		{"", SectionWarningMessage},
+		{"-relevance,gpa", SectionWarningMessage},
+		{"-seats", SortWarningMessage},
+		{"courseNumber,-relevance", SortWarningMessage},
+	}
+	for _, tt := range tests {
+		keys, err := parseSort(tt.sort)
+		if err != nil {
+			t.Fatalf("parseSort(%q): %v", tt.sort, err)
+		}
+		resp, err := svc.buildResponse(context.Background(), ranked, page{sort: keys, size: MaxCourseResults}, time.Now())
+		if err != nil {
+			t.Fatalf("buildResponse: %v", err)
+		}
+		if resp.Warning != tt.expected {
+			t.Errorf("sort %q warning = %q, expected %q", tt.sort, resp.Warning, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package search
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+//go:embed synonyms.txt
+var defaultSynonyms string
+
+// Synonyms maps abbreviations to what they stand for. Subject entries expand
+// the subject filter ("CS" -> "CSCI"); word entries add alternatives for a
+// title word ("calc" -> "calculus"). Loaded from an INI-style file, see
+// synonyms.txt for the format.
+type Synonyms struct {
+	subjects map[string][]string   // upper-case input -> subject codes
+	words    map[string][][]string // lower-case token -> tokenized expansions
+}
+
+// DefaultSynonyms returns the built-in dictionary.
+func DefaultSynonyms() *Synonyms {
+	syn, err := ParseSynonyms(strings.NewReader(defaultSynonyms))
+	if err != nil {
+		panic("search: invalid built-in synonyms: " + err.Error())
+	}
+	return syn
+}
+
+// LoadSynonyms reads a synonyms file from disk.
+func LoadSynonyms(path string) (*Synonyms, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open synonyms file: %w", err)
+	}
+	defer f.Close()
+	return ParseSynonyms(f)
+}
+
+// ParseSynonyms parses the synonyms file format: "[subjects]" and "[words]"
+// sections of "key = value, value" lines, with "#" comments.
+func ParseSynonyms(r io.Reader) (*Synonyms, error) {
+	syn := &Synonyms{
+		subjects: make(map[string][]string),
+		words:    make(map[string][][]string),
+	}
+
+	section := ""
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			if section != "subjects" && section != "words" {
+				return nil, fmt.Errorf("line %d: unknown section %q", lineNum, section)
+			}
+			continue
+		}
+
+		key, values, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected \"key = value\"", lineNum)
+		}
+
+		for _, v := range strings.Split(values, ",") {
+			v = strings.TrimSpace(v)
+			if v == "" {
+				continue
+			}
+			switch section {
+			case "subjects":
+				k := strings.ToUpper(key)
+				syn.subjects[k] = append(syn.subjects[k], strings.ToUpper(v))
+			case "words":
+				keyTokens := tokenize(key)
+				if len(keyTokens) != 1 {
+					return nil, fmt.Errorf("line %d: word key %q must be a single word", lineNum, key)
+				}
+				if expansion := tokenize(v); len(expansion) > 0 {
+					syn.words[keyTokens[0]] = append(syn.words[keyTokens[0]], expansion)
+				}
+			default:
+				return nil, fmt.Errorf("line %d: entry outside of a section", lineNum)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read synonyms: %w", err)
+	}
+	return syn, nil
+}
+
+// Subjects returns the subject codes to search for a subject filter, always
+// including the input itself.
+func (s *Synonyms) Subjects(subject string) []string {
+	subject = strings.ToUpper(subject)
+	codes := []string{subject}
+	if s == nil {
+		return codes
+	}
+	for _, code := range s.subjects[subject] {
+		if code != subject {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// Words returns the expansions for a lower-case title token. Each expansion
+// is one or more tokens.
+func (s *Synonyms) Words(token string) [][]string {
+	if s == nil {
+		return nil
+	}
+	return s.words[token]
+}
@@ -7,15 +7,44 @@ type SearchRequest struct {
 	Year int    `form:"year"` // Academic year (e.g., 2025 = Fall 2024 through Summer 2025)
 
 	// Filters (at least one required)
-	Subject      string `form:"subject"`      // Exact match (e.g., "CSCI")
+	Subject      string `form:"subject"`      // Exact match (e.g., "CSCI"), plus subject synonyms ("CS")
 	CourseNumber string `form:"courseNumber"` // Supports wildcards: "2*", "201"
-	Title        string `form:"title"`        // Token-based search (max 3 tokens)
-	Instructor   string `form:"instructor"`   // Token-based search (max 3 tokens)
+	Title        string `form:"title"`        // Full-text prefix search over title and subject description, with synonyms and typo tolerance
+	Instructor   string `form:"instructor"`   // Full-text prefix search over instructor names, with typo tolerance
 
 	// Additional filters
 	OpenSeats  bool `form:"openSeats"`  // Only sections with available seats
 	MinCredits *int `form:"minCredits"` // Minimum credit hours
 	MaxCredits *int `form:"maxCredits"` // Maximum credit hours
+
+	// Schedule filters
+	Days       string   `form:"days"`       // Meets only on these days, e.g. "TR" (U M T W R F S)
+	StartAfter string   `form:"startAfter"` // No meeting starts before this time ("1000" or "10:00")
+	EndBefore  string   `form:"endBefore"`  // No meeting ends after this time
+	CRNs       []string `form:"crns"`       // Fits around these sections of Term (repeated or comma-separated)
+	Blocked    []string `form:"blocked"`    // Fits around these blocks, "day:HHMM-HHMM" with day 0=Mon through 4=Fri
+
+	// Section filters, repeated or comma-separated. A section matches if it has
+	// any of the values given for a filter; values come from the response facets.
+	Attributes   []string `form:"attributes"`   // Attribute codes (e.g., GUR designations)
+	Campus       []string `form:"campus"`       // Campus names
+	ScheduleType []string `form:"scheduleType"` // Schedule types (e.g., "Lecture", "Laboratory")
+	DeliveryMode []string `form:"deliveryMode"` // Instructional method codes
+
+	// Facet filters, repeated or comma-separated like the section filters.
+	// The term facet maps back to Term.
+	Level          []string `form:"level"`          // Course levels: "100" through "900"
+	InstructorName []string `form:"instructorName"` // Exact primary instructor names
+	Availability   string   `form:"availability"`   // "open" or "full"
+	GPABand        []string `form:"gpaBand"`        // Course GPA bands, see gpaBands
+
+	// Ordering
+	Sort    string   `form:"sort"`    // Comma-separated sort keys, "-" prefix for descending (e.g., "-gpa,courseNumber"); default "-relevance"
+	Weights []string `form:"weights"` // Scorer weights as "name:weight" (e.g., "gpa:2,recency:0"); see Scorer.Name
+
+	// Pagination
+	Cursor   string `form:"cursor"`   // NextCursor from the previous page of the same search
+	PageSize int    `form:"pageSize"` // Courses per page, 1 to MaxCourseResults (default MaxCourseResults)
 }
 
 // CourseInfo contains course-level data sent once per unique course code.
@@ -68,18 +97,44 @@ type CourseRef struct {
 
 // SearchResponse is the normalized wire format for search results.
 type SearchResponse struct {
-	Courses  map[string]CourseInfo  `json:"courses"`
-	Sections map[string]SectionInfo `json:"sections"`
-	Results  []CourseRef            `json:"results"`
-	Total    int                    `json:"total"`             // Number of courses returned
-	Warning  string                 `json:"warning,omitempty"` // Set if results truncated
-	Stats    SearchStats            `json:"stats"`
+	Courses    map[string]CourseInfo  `json:"courses"`
+	Sections   map[string]SectionInfo `json:"sections"`
+	Results    []CourseRef            `json:"results"`
+	Total      int                    `json:"total"`                // Number of courses returned
+	NextCursor string                 `json:"nextCursor,omitempty"` // Pass as cursor for the next page; empty on the last page
+	Warning    string                 `json:"warning,omitempty"`    // Set if the section fetch limit was hit
+	Facets     SearchFacets           `json:"facets"`
+	Stats      SearchStats            `json:"stats"`
+}
+
+// FacetValue is one bucket of a facet.
+type FacetValue struct {
+	Value string `json:"value"`           // Filter value to send back in SearchRequest
+	Label string `json:"label,omitempty"` // Display text, when it differs from Value
+	Count int    `json:"count"`           // Number of matching courses
+}
+
+// SearchFacets counts matching courses by section property, across all
+// matches rather than only the returned page. Buckets are sorted by count,
+// except Level and GPABand (ascending) and Term (most recent first).
+type SearchFacets struct {
+	Attributes   []FacetValue `json:"attributes"`
+	Campus       []FacetValue `json:"campus"`
+	ScheduleType []FacetValue `json:"scheduleType"`
+	DeliveryMode []FacetValue `json:"deliveryMode"`
+	Level        []FacetValue `json:"level"`
+	Term         []FacetValue `json:"term"`
+	Instructor   []FacetValue `json:"instructor"`   // Top MaxInstructorFacet primary instructors
+	Availability []FacetValue `json:"availability"` // "open" counts courses with any open section
+	GPABand      []FacetValue `json:"gpaBand"`      // Course GPA from grade data; empty when not loaded
 }
 
 // SearchStats contains timing and count information about the search.
 type SearchStats struct {
-	TotalSections   int     `json:"totalSections"`             // Number of sections found
-	TotalCourses    int     `json:"totalCourses"`              // Number of unique courses
+	TotalSections   int     `json:"totalSections"`             // Number of sections returned
+	TotalCourses    int     `json:"totalCourses"`              // Number of unique courses returned
+	MatchedSections int     `json:"matchedSections"`           // Sections matched across all pages
+	MatchedCourses  int     `json:"matchedCourses"`            // Courses matched across all pages
 	TimeMs          float64 `json:"timeMs"`                    // Search duration in milliseconds
 	SectionLimitHit bool    `json:"sectionLimitHit,omitempty"` // True if section fetch limit was reached
 }
@@ -103,14 +158,18 @@ type sectionRow struct {
 	ScheduleType    string
 	Instructor      string
 	InstructorEmail string
+	TextScore       float64 // BM25 score from the full-text index, 0 without a text filter
+	ExactTerms      int     // Text filter words matched as typed
+	SynonymTerms    int     // Text filter words matched only through a synonym
+	FuzzyTerms      int     // Text filter words matched only through typo tolerance
 	RelevanceScore  float64
 }
 
 // Constants for validation
 const (
-	MaxCourseResults      = 200
-	MaxSectionFetch       = 2000 // Safety limit for SQL query
-	MaxTokens             = 3
-	CourseWarningMessage  = "Showing maximum results. Try narrowing your search for better results."
+	MaxCourseResults      = 200   // Maximum and default page size
+	MaxSectionFetch       = 20000 // Sections kept per search, most relevant first; every kept row is ranked before paging
+	MaxInstructorFacet    = 25
 	SectionWarningMessage = "Showing maximum sections. Try narrowing your search for better results."
+	SortWarningMessage    = "Too many sections match to sort them all; only the most relevant are sorted. Try narrowing your search."
 )
@@ -1,6 +1,10 @@
 package server
 
 import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
 	"schedule-optimizer/internal/config"
 
 	"github.com/gin-contrib/cors"
@@ -18,3 +22,20 @@ func SetupMiddleware(r *gin.Engine, cfg *config.Config) {
 	}
 	r.Use(cors.New(corsConfig))
 }
+
+// AdminAuth requires a matching bearer token on admin routes.
+// Admin routes respond 404 when no token is configured so they aren't discoverable.
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Not found"})
+			return
+		}
+		provided, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"schedule-optimizer/internal/api"
+	"schedule-optimizer/internal/config"
 	"schedule-optimizer/internal/static"
 
 	"github.com/gin-contrib/gzip"
@@ -9,20 +10,46 @@ import (
 )
 
 // RegisterRoutes sets up all API routes and static file serving.
-func RegisterRoutes(r *gin.Engine, h *api.Handlers) {
+func RegisterRoutes(r *gin.Engine, h *api.Handlers, cfg *config.Config) {
 	apiGroup := r.Group("/api")
 	apiGroup.Use(gzip.Gzip(gzip.DefaultCompression))
 	{
 		apiGroup.GET("/health", h.Health)
 		apiGroup.GET("/terms", h.GetTerms)
+		apiGroup.GET("/terms/:term/changes", h.GetTermChanges)
 		apiGroup.GET("/subjects", h.GetSubjects)
 		apiGroup.GET("/course/:subject/:courseNumber", h.GetCourse)
+		apiGroup.GET("/course/:subject/:courseNumber/history", h.GetCourseHistory)
+		apiGroup.GET("/course/:subject/:courseNumber/prerequisites", h.GetCoursePrerequisites)
 		apiGroup.GET("/search", h.Search)
 		apiGroup.GET("/crn/:crn", h.GetCRN)
+		apiGroup.GET("/crn/:crn/history", h.GetCRNHistory)
+		apiGroup.GET("/instructors", h.SearchInstructors)
+		apiGroup.GET("/instructors/:name", h.GetInstructor)
 		apiGroup.POST("/courses/validate", h.ValidateCourses)
 		apiGroup.POST("/generate", h.Generate)
+		apiGroup.POST("/prerequisites/eligible", h.GetEligibleCourses)
 		apiGroup.GET("/announcement", h.GetAnnouncement)
 		apiGroup.POST("/feedback", h.SubmitFeedback)
+		apiGroup.POST("/saved-searches", h.CreateSavedSearch)
+		apiGroup.GET("/saved-searches", h.ListSavedSearches)
+		apiGroup.DELETE("/saved-searches/:id", h.DeleteSavedSearch)
+		apiGroup.GET("/saved-searches/alerts", h.GetSavedSearchAlerts)
+	}
+
+	// Outside apiGroup because gzip buffers writes, which would hold back events
+	r.GET("/api/saved-searches/alerts/stream", h.StreamSavedSearchAlerts)
+
+	adminGroup := apiGroup.Group("/admin")
+	adminGroup.Use(AdminAuth(cfg.AdminToken))
+	{
+		adminGroup.GET("/cache", h.GetCacheMetrics)
+		adminGroup.GET("/scrape-runs", h.GetScrapeRuns)
+		adminGroup.GET("/jobs", h.GetJobs)
+		adminGroup.POST("/jobs/pause", h.PauseJobs)
+		adminGroup.POST("/jobs/resume", h.ResumeJobs)
+		adminGroup.POST("/jobs/:name/trigger", h.TriggerJob)
+		adminGroup.POST("/terms/:term/scrape", h.TriggerTermScrape)
 	}
 
 	// Serve static files (compiled frontend)
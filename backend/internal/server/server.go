@@ -11,14 +11,17 @@ import (
 	"syscall"
 	"time"
 
+	"schedule-optimizer/internal/alerts"
 	"schedule-optimizer/internal/api"
 	"schedule-optimizer/internal/cache"
 	"schedule-optimizer/internal/config"
 	"schedule-optimizer/internal/db"
 	"schedule-optimizer/internal/generator"
 	"schedule-optimizer/internal/jobs"
+	"schedule-optimizer/internal/prereq"
 	"schedule-optimizer/internal/search"
 	"schedule-optimizer/internal/stats"
+	"schedule-optimizer/internal/stats/catalog"
 	"schedule-optimizer/internal/stats/grades"
 	"schedule-optimizer/internal/store"
 
@@ -51,9 +54,53 @@ func Run() {
 	defer stop()
 
 	gradeService := grades.NewService(database, queries, cfg.GradeDataPath)
-	_ = stats.NewService(gradeService) // TODO: pass to handlers when stats endpoints are added
+	statsService := stats.NewService(gradeService, catalog.NewService(queries, gradeService))
 
-	jobsService := jobs.Setup(ctx, cfg, queries, gradeService)
+	// Cache is created before jobs so scrapes can trigger background reloads
+	scheduleCache := cache.NewScheduleCache(queries, gradeService)
+	scheduleCache.SetPolicy(cache.Policy{
+		MaxTerms: cfg.CacheMaxTerms,
+		MaxBytes: int64(cfg.CacheMaxMB) << 20,
+		// Terms that haven't ended are what nearly every generate request targets
+		IsPinned: func(term string) bool {
+			return jobs.GetTermPhase(term, time.Now()) != jobs.PhasePast
+		},
+	})
+	scheduleCache.SetMaskFunc(generator.MeetingMask)
+	if err := scheduleCache.SetSnapshotDir(cfg.CacheSnapshotDir); err != nil {
+		slog.Warn("Cache snapshots disabled", "error", err)
+	}
+	go func() {
+		if n := scheduleCache.WarmFromSnapshots(ctx); n > 0 {
+			slog.Info("Warmed schedule cache", "terms", n)
+		}
+	}()
+
+	// Search also listens for scrapes, to refresh its fuzzy-match vocabulary
+	searchService := search.NewService(database, queries, scheduleCache, gradeService)
+	if cfg.SearchSynonymsPath != "" {
+		if syn, err := search.LoadSynonyms(cfg.SearchSynonymsPath); err != nil {
+			slog.Warn("Using built-in search synonyms", "error", err)
+		} else {
+			searchService.SetSynonyms(syn)
+		}
+	}
+
+	// Saved searches are rerun after scrapes to find changes worth alerting on
+	alertsService := alerts.NewService(queries, searchService)
+
+	// Prerequisite graphs are rebuilt from the details each scrape stores;
+	// graphs never built or built by an older parser are rebuilt here
+	prereqService := prereq.NewService(database, queries)
+	go func() {
+		if n, err := prereqService.RebuildStale(ctx); err != nil {
+			slog.Error("Failed to rebuild stale prerequisite graphs", "error", err)
+		} else if n > 0 {
+			slog.Info("Rebuilt stale prerequisite graphs", "terms", n)
+		}
+	}()
+
+	jobsService := jobs.Setup(ctx, cfg, database, queries, gradeService, scheduleCache, searchService, alertsService, prereqService)
 
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -62,17 +109,21 @@ func Run() {
 	r := gin.Default()
 	SetupMiddleware(r, cfg)
 
-	scheduleCache := cache.NewScheduleCache(queries, gradeService)
 	generatorService := generator.NewService(scheduleCache, queries)
-	searchService := search.NewService(database, queries, gradeService)
-	handlers := api.NewHandlers(database, scheduleCache, generatorService, queries, searchService, gradeService)
+	generatorService.SetPrerequisites(prereqService)
+	handlers := api.NewHandlers(database, scheduleCache, generatorService, queries, searchService, statsService)
+	handlers.SetAlerts(alertsService)
+	handlers.SetPrerequisites(prereqService)
+	handlers.SetJobs(jobsService)
 
-	RegisterRoutes(r, handlers)
+	RegisterRoutes(r, handlers, cfg)
 
 	srv := &http.Server{
 		Addr:    fmt.Sprintf(":%s", cfg.Port),
 		Handler: r,
 	}
+	// Alert streams stay open until their subscriptions are closed
+	srv.RegisterOnShutdown(alertsService.Close)
 
 	go func() {
 		slog.Info("Server listening", "port", cfg.Port)
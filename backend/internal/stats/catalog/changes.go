@@ -0,0 +1,90 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"schedule-optimizer/internal/store"
+)
+
+var ErrSectionNotFound = errors.New("section not found in term")
+
+// MaxTermChanges caps changes returned per term change feed request.
+const MaxTermChanges = 500
+
+// SectionTimeline returns the changes recorded for a section across scrapes.
+func (s *Service) SectionTimeline(ctx context.Context, term, crn string) (*SectionTimeline, error) {
+	rows, err := s.queries.GetSectionHistory(ctx, store.GetSectionHistoryParams{Term: term, Crn: crn})
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := &SectionTimeline{
+		Term:    term,
+		CRN:     crn,
+		Changes: make([]SectionChange, len(rows)),
+	}
+	for i, row := range rows {
+		timeline.Changes[i] = SectionChange{
+			ID:       row.ID,
+			Kind:     row.Kind,
+			OldValue: optionalString(row.OldValue),
+			NewValue: optionalString(row.NewValue),
+			At:       row.CreatedAt.Time,
+		}
+	}
+
+	section, err := s.queries.GetSectionByTermAndCRN(ctx, store.GetSectionByTermAndCRNParams{Term: term, Crn: crn})
+	switch {
+	case err == nil:
+		timeline.Subject = section.Subject
+		timeline.CourseNumber = section.CourseNumber
+		timeline.Title = section.Title
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, err
+	case len(rows) == 0:
+		return nil, ErrSectionNotFound
+	}
+	return timeline, nil
+}
+
+// TermChanges returns a term's section changes with IDs above after, oldest
+// first, optionally only of one kind. limit is clamped to MaxTermChanges.
+func (s *Service) TermChanges(ctx context.Context, term string, after int64, kind string, limit int) ([]SectionChange, error) {
+	if limit <= 0 || limit > MaxTermChanges {
+		limit = MaxTermChanges
+	}
+	rows, err := s.queries.GetTermChanges(ctx, store.GetTermChangesParams{
+		Term:  term,
+		After: after,
+		Kind:  kind,
+		Limit: int64(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]SectionChange, len(rows))
+	for i, row := range rows {
+		changes[i] = SectionChange{
+			ID:           row.ID,
+			CRN:          row.Crn,
+			Subject:      row.Subject.String,
+			CourseNumber: row.CourseNumber.String,
+			Title:        row.Title.String,
+			Kind:         row.Kind,
+			OldValue:     optionalString(row.OldValue),
+			NewValue:     optionalString(row.NewValue),
+			At:           row.CreatedAt.Time,
+		}
+	}
+	return changes, nil
+}
+
+func optionalString(ns sql.NullString) *string {
+	if !ns.Valid {
+		return nil
+	}
+	return &ns.String
+}
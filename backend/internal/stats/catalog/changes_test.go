@@ -0,0 +1,95 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"schedule-optimizer/internal/testutil"
+)
+
+// seedHistory records changes to seed sections, plus 20099, which is no
+// longer stored.
+func seedHistory(t *testing.T, db *sql.DB) {
+	t.Helper()
+	_, err := db.Exec(`
+		INSERT INTO section_history (term, crn, kind, old_value, new_value) VALUES
+			('202520', '20001', 'added', NULL, 'Data Structures'),
+			('202520', '20002', 'added', NULL, 'Algorithms'),
+			('202520', '20001', 'seats', '5', '0'),
+			('202510', '10001', 'seats', '3', '2'),
+			('202520', '20099', 'added', NULL, 'Cancelled Seminar');
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed history: %v", err)
+	}
+}
+
+func TestSectionTimeline(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+	seedHistory(t, db)
+
+	svc := NewService(queries, nil)
+	ctx := context.Background()
+
+	timeline, err := svc.SectionTimeline(ctx, "202520", "20001")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if timeline.Subject != "CSCI" || timeline.Title != "Data Structures" || len(timeline.Changes) != 2 {
+		t.Fatalf("timeline = %+v", timeline)
+	}
+	seats := timeline.Changes[1]
+	if seats.Kind != "seats" || *seats.OldValue != "5" || *seats.NewValue != "0" || seats.At.IsZero() {
+		t.Errorf("seats change = %+v", seats)
+	}
+	if timeline.Changes[0].OldValue != nil {
+		t.Errorf("added change has old value %q", *timeline.Changes[0].OldValue)
+	}
+
+	// History outlives the section row
+	timeline, err = svc.SectionTimeline(ctx, "202520", "20099")
+	if err != nil || len(timeline.Changes) != 1 || timeline.Subject != "" {
+		t.Errorf("timeline = %+v, err = %v", timeline, err)
+	}
+	// A stored section without history has an empty timeline
+	timeline, err = svc.SectionTimeline(ctx, "202520", "20003")
+	if err != nil || len(timeline.Changes) != 0 {
+		t.Errorf("timeline = %+v, err = %v", timeline, err)
+	}
+	if _, err := svc.SectionTimeline(ctx, "202520", "99999"); !errors.Is(err, ErrSectionNotFound) {
+		t.Errorf("expected ErrSectionNotFound, got %v", err)
+	}
+}
+
+func TestTermChanges(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+	seedHistory(t, db)
+
+	svc := NewService(queries, nil)
+	ctx := context.Background()
+
+	changes, err := svc.TermChanges(ctx, "202520", 0, "", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 4 || changes[0].CRN != "20001" || changes[0].CourseNumber != "247" {
+		t.Fatalf("changes = %+v", changes)
+	}
+
+	// Polling after the first change, one at a time
+	changes, err = svc.TermChanges(ctx, "202520", changes[0].ID, "", 1)
+	if err != nil || len(changes) != 1 || changes[0].CRN != "20002" {
+		t.Errorf("changes = %+v, err = %v", changes, err)
+	}
+
+	changes, err = svc.TermChanges(ctx, "202520", 0, "seats", 0)
+	if err != nil || len(changes) != 1 || changes[0].Kind != "seats" || changes[0].Title != "Data Structures" {
+		t.Errorf("seat changes = %+v, err = %v", changes, err)
+	}
+}
@@ -0,0 +1,97 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"schedule-optimizer/internal/store"
+)
+
+var ErrCourseNotFound = errors.New("course not found in any term")
+
+// CourseHistory returns every scraped offering of a course with enrollment
+// trends, typical meeting patterns, and grade aggregates.
+func (s *Service) CourseHistory(ctx context.Context, subject, courseNumber string) (*CourseHistory, error) {
+	rows, err := s.queries.GetCourseHistory(ctx, store.GetCourseHistoryParams{
+		Subject:      subject,
+		CourseNumber: courseNumber,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrCourseNotFound
+	}
+
+	history := &CourseHistory{
+		Subject:          subject,
+		CourseNumber:     courseNumber,
+		Title:            rows[0].Title,
+		Credits:          int(rows[0].CreditHoursLow.Int64),
+		Offerings:        []TermOffering{},
+		InstructorGrades: []InstructorGrade{},
+	}
+
+	// Rows are ordered by term, so each term's sections are contiguous
+	sectionIDs := make([]int64, 0, len(rows))
+	instructors := make(map[string]bool)
+	var offering *TermOffering
+	for _, row := range rows {
+		sectionIDs = append(sectionIDs, row.ID)
+		if offering == nil || offering.Term != row.Term {
+			history.Offerings = append(history.Offerings, TermOffering{
+				Term:        row.Term,
+				Description: row.TermDescription.String,
+				Instructors: []string{},
+			})
+			offering = &history.Offerings[len(history.Offerings)-1]
+		}
+		offering.Sections++
+		offering.Enrollment += row.Enrollment.Int64
+		offering.MaxEnrollment += row.MaxEnrollment.Int64
+		offering.WaitCount += row.WaitCount.Int64
+		if name := row.InstructorName.String; name != "" {
+			if !slices.Contains(offering.Instructors, name) {
+				offering.Instructors = append(offering.Instructors, name)
+			}
+			instructors[name] = true
+		}
+	}
+	for i := range history.Offerings {
+		o := &history.Offerings[i]
+		if o.MaxEnrollment > 0 {
+			o.FillRate = float64(o.Enrollment) / float64(o.MaxEnrollment)
+		}
+		slices.Sort(o.Instructors)
+	}
+
+	history.MeetingPatterns, err = s.meetingPatterns(ctx, sectionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.gradesLoaded() {
+		gradeSubject := s.gradeService.MapSubject(subject)
+		history.Grades = summarize(s.gradeService.GetAggregate("course", gradeSubject, courseNumber, ""))
+
+		names := make([]string, 0, len(instructors))
+		for name := range instructors {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			gradeName := s.gradeService.MapInstructor(name)
+			if gradeName == "" {
+				continue
+			}
+			if agg := s.gradeService.GetAggregate("course_professor", gradeSubject, courseNumber, gradeName); agg != nil {
+				history.InstructorGrades = append(history.InstructorGrades, InstructorGrade{
+					Instructor: name,
+					Grades:     summarize(agg),
+				})
+			}
+		}
+	}
+	return history, nil
+}
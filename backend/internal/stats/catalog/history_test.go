@@ -0,0 +1,122 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+
+	"schedule-optimizer/internal/stats/grades"
+	"schedule-optimizer/internal/store"
+	"schedule-optimizer/internal/testutil"
+)
+
+// seedGrades maps CSCI to the grade-data subject CS and Dr. Smith to
+// "Smith, John", with course and course+professor aggregates for CS 247.
+func seedGrades(t *testing.T, db *sql.DB, queries *store.Queries) *grades.Service {
+	t.Helper()
+	_, err := db.Exec(`
+		INSERT INTO subject_mappings (banner_subject, grade_subject, match_count) VALUES ('CSCI', 'CS', 50);
+		INSERT INTO instructor_mappings (banner_name, grade_name, match_count) VALUES ('Dr. Smith', 'Smith, John', 30);
+		INSERT INTO grade_aggregates (level, subject, course_number, instructor,
+			sections_count, students_count, cnt_a, cnt_am, cnt_bp, cnt_b, cnt_bm,
+			cnt_cp, cnt_c, cnt_cm, cnt_dp, cnt_d, cnt_dm, cnt_f, cnt_w, cnt_p, cnt_np, cnt_s, cnt_u,
+			gpa, pass_rate)
+		VALUES
+			('course_professor', 'CS', '247', 'Smith, John', 5, 120, 40, 20, 15, 15, 10, 5, 5, 3, 2, 2, 1, 2, 0, 0, 0, 0, 0, 3.45, NULL),
+			('course', 'CS', '247', '', 10, 250, 80, 40, 30, 30, 20, 10, 10, 6, 4, 4, 2, 4, 0, 0, 0, 0, 0, 3.30, NULL);
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed grades: %v", err)
+	}
+	gradeService := grades.NewService(db, queries, "")
+	if err := gradeService.LoadFromDB(context.Background()); err != nil {
+		t.Fatalf("failed to load grades: %v", err)
+	}
+	return gradeService
+}
+
+func TestCourseHistory(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	// A second 202520 section of CSCI 247 with a different pattern and instructor
+	_, err := db.Exec(`
+		INSERT INTO sections (id, term, crn, subject, course_number, title, credit_hours_low, enrollment, max_enrollment)
+		VALUES (5, '202520', '20005', 'CSCI', '247', 'Data Structures', 4, 15, 30);
+		INSERT INTO instructors (section_id, name, is_primary) VALUES (5, 'Dr. Adams', 1);
+		INSERT INTO meeting_times (section_id, start_time, end_time, tuesday, thursday) VALUES (5, '1200', '1350', 1, 1);
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed section: %v", err)
+	}
+
+	svc := NewService(queries, seedGrades(t, db, queries))
+	history, err := svc.CourseHistory(context.Background(), "CSCI", "247")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if history.Title != "Data Structures" || history.Credits != 4 {
+		t.Errorf("course = %q (%d credits)", history.Title, history.Credits)
+	}
+
+	expected := []TermOffering{
+		{
+			Term: "202520", Description: "Spring 2025", Sections: 2,
+			Enrollment: 40, MaxEnrollment: 60, FillRate: 40.0 / 60.0,
+			Instructors: []string{"Dr. Adams", "Dr. Smith"},
+		},
+		{
+			Term: "202510", Description: "Winter 2025", Sections: 1,
+			Enrollment: 28, MaxEnrollment: 30, FillRate: 28.0 / 30.0,
+			Instructors: []string{"Dr. Smith"},
+		},
+	}
+	if !slices.EqualFunc(history.Offerings, expected, func(a, b TermOffering) bool {
+		return a.Term == b.Term && a.Description == b.Description && a.Sections == b.Sections &&
+			a.Enrollment == b.Enrollment && a.MaxEnrollment == b.MaxEnrollment &&
+			a.FillRate == b.FillRate && slices.Equal(a.Instructors, b.Instructors)
+	}) {
+		t.Errorf("offerings = %+v, expected %+v", history.Offerings, expected)
+	}
+
+	patterns := []PatternCount{{Pattern: "MWF 1000-1050", Sections: 2}, {Pattern: "TR 1200-1350", Sections: 1}}
+	if !slices.Equal(history.MeetingPatterns, patterns) {
+		t.Errorf("meeting patterns = %+v, expected %+v", history.MeetingPatterns, patterns)
+	}
+
+	if history.Grades == nil || history.Grades.GPA != 3.30 || history.Grades.Distribution["A"] != 80 {
+		t.Errorf("course grades = %+v", history.Grades)
+	}
+	if _, ok := history.Grades.Distribution["P"]; ok {
+		t.Error("distribution should omit grades nobody received")
+	}
+
+	// Dr. Adams has no instructor mapping, so only Dr. Smith has course+professor grades
+	if len(history.InstructorGrades) != 1 || history.InstructorGrades[0].Instructor != "Dr. Smith" ||
+		history.InstructorGrades[0].Grades.GPA != 3.45 {
+		t.Errorf("instructor grades = %+v", history.InstructorGrades)
+	}
+}
+
+func TestCourseHistory_NoGrades(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(queries, nil)
+	history, err := svc.CourseHistory(context.Background(), "MATH", "204")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history.Offerings) != 1 || history.Grades != nil || len(history.InstructorGrades) != 0 {
+		t.Errorf("history = %+v", history)
+	}
+
+	if _, err := svc.CourseHistory(context.Background(), "CSCI", "999"); !errors.Is(err, ErrCourseNotFound) {
+		t.Errorf("expected ErrCourseNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"schedule-optimizer/internal/store"
+)
+
+var (
+	ErrInstructorNotFound = errors.New("instructor not found in any term")
+	ErrQueryTooShort      = errors.New("instructor search must be at least 2 characters")
+)
+
+// MaxInstructorMatches caps instructor search results.
+const MaxInstructorMatches = 20
+
+// SearchInstructors finds instructors whose name contains query, most
+// sections taught first.
+func (s *Service) SearchInstructors(ctx context.Context, query string) ([]InstructorMatch, error) {
+	query = strings.TrimSpace(query)
+	if len([]rune(query)) < 2 {
+		return nil, ErrQueryTooShort
+	}
+
+	// Escape LIKE wildcards so "%" and "_" match literally
+	pattern := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(query)
+	rows, err := s.queries.SearchInstructors(ctx, store.SearchInstructorsParams{
+		Name:  "%" + pattern + "%",
+		Limit: MaxInstructorMatches,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]InstructorMatch, len(rows))
+	for i, row := range rows {
+		matches[i] = InstructorMatch{
+			Name:       row.Name,
+			Courses:    int(row.CourseCount),
+			Sections:   int(row.SectionCount),
+			LatestTerm: row.LatestTerm,
+		}
+	}
+	return matches, nil
+}
+
+// InstructorProfile returns the courses an instructor has taught and when,
+// their usual meeting times and class sizes, and grade aggregates compared
+// with each course's average. name is the Banner name as returned by
+// SearchInstructors.
+func (s *Service) InstructorProfile(ctx context.Context, name string) (*InstructorProfile, error) {
+	rows, err := s.queries.GetInstructorSections(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrInstructorNotFound
+	}
+
+	profile := &InstructorProfile{
+		Name:     name,
+		Sections: len(rows),
+		Terms:    []string{},
+		Courses:  []TaughtCourse{},
+	}
+
+	// Rows are ordered by term descending, so courses and terms are appended
+	// most recent first
+	sectionIDs := make([]int64, 0, len(rows))
+	courseIndex := make(map[string]int)
+	var totalEnrollment, totalCapacity int64
+	for _, row := range rows {
+		sectionIDs = append(sectionIDs, row.ID)
+		if profile.Email == "" {
+			profile.Email = row.Email.String
+		}
+		if !slices.Contains(profile.Terms, row.Term) {
+			profile.Terms = append(profile.Terms, row.Term)
+		}
+		totalEnrollment += row.Enrollment.Int64
+		totalCapacity += row.MaxEnrollment.Int64
+		profile.LargestClass = max(profile.LargestClass, row.Enrollment.Int64)
+
+		key := row.Subject + ":" + row.CourseNumber
+		i, ok := courseIndex[key]
+		if !ok {
+			i = len(profile.Courses)
+			courseIndex[key] = i
+			profile.Courses = append(profile.Courses, TaughtCourse{
+				Subject:      row.Subject,
+				CourseNumber: row.CourseNumber,
+				Title:        row.Title,
+				Terms:        []string{},
+			})
+		}
+		course := &profile.Courses[i]
+		if !slices.Contains(course.Terms, row.Term) {
+			course.Terms = append(course.Terms, row.Term)
+		}
+		course.Sections++
+		// Summed here, averaged below
+		course.AvgEnrollment += float64(row.Enrollment.Int64)
+	}
+	profile.AvgEnrollment = float64(totalEnrollment) / float64(len(rows))
+	profile.AvgCapacity = float64(totalCapacity) / float64(len(rows))
+	for i := range profile.Courses {
+		profile.Courses[i].AvgEnrollment /= float64(profile.Courses[i].Sections)
+	}
+
+	profile.MeetingPatterns, err = s.meetingPatterns(ctx, sectionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.gradesLoaded() {
+		s.addInstructorGrades(profile)
+	}
+	return profile, nil
+}
+
+// addInstructorGrades fills in grade aggregates using the instructor's
+// grade-data name. Unmapped instructors get none.
+func (s *Service) addInstructorGrades(profile *InstructorProfile) {
+	profile.GradeName = s.gradeService.MapInstructor(profile.Name)
+	if profile.GradeName == "" {
+		return
+	}
+	profile.Grades = summarize(s.gradeService.GetAggregate("professor", "", "", profile.GradeName))
+
+	for i := range profile.Courses {
+		course := &profile.Courses[i]
+		gradeSubject := s.gradeService.MapSubject(course.Subject)
+		instructor := summarize(s.gradeService.GetAggregate("course_professor", gradeSubject, course.CourseNumber, profile.GradeName))
+		if instructor == nil {
+			continue
+		}
+		comparison := &GradeComparison{
+			Instructor: instructor,
+			Course:     summarize(s.gradeService.GetAggregate("course", gradeSubject, course.CourseNumber, "")),
+		}
+		if comparison.Course != nil {
+			diff := instructor.GPA - comparison.Course.GPA
+			comparison.GPADiff = &diff
+		}
+		course.Grades = comparison
+	}
+}
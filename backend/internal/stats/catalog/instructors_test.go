@@ -0,0 +1,124 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"schedule-optimizer/internal/testutil"
+)
+
+func TestSearchInstructors(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	svc := NewService(queries, nil)
+	ctx := context.Background()
+
+	matches, err := svc.SearchInstructors(ctx, "dr.")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Dr. Smith taught two sections, so ranks first
+	expected := []InstructorMatch{
+		{Name: "Dr. Smith", Courses: 1, Sections: 2, LatestTerm: "202520"},
+		{Name: "Dr. Brown", Courses: 1, Sections: 1, LatestTerm: "202520"},
+		{Name: "Dr. Jones", Courses: 1, Sections: 1, LatestTerm: "202520"},
+	}
+	if !slices.Equal(matches, expected) {
+		t.Errorf("matches = %+v, expected %+v", matches, expected)
+	}
+
+	// LIKE wildcards in the query match literally
+	matches, err = svc.SearchInstructors(ctx, "D%")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("expected no matches for a literal %%, got %+v", matches)
+	}
+
+	if _, err := svc.SearchInstructors(ctx, " s "); !errors.Is(err, ErrQueryTooShort) {
+		t.Errorf("expected ErrQueryTooShort, got %v", err)
+	}
+}
+
+func TestInstructorProfile(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+	gradeService := seedGrades(t, db, queries)
+	_, err := db.Exec(`
+		INSERT INTO grade_aggregates (level, subject, course_number, instructor,
+			sections_count, students_count, cnt_a, cnt_am, cnt_bp, cnt_b, cnt_bm,
+			cnt_cp, cnt_c, cnt_cm, cnt_dp, cnt_d, cnt_dm, cnt_f, cnt_w, cnt_p, cnt_np, cnt_s, cnt_u,
+			gpa, pass_rate)
+		VALUES ('professor', '', '', 'Smith, John', 20, 500, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 3.2, NULL);
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed professor grades: %v", err)
+	}
+	if err := gradeService.LoadFromDB(context.Background()); err != nil {
+		t.Fatalf("failed to reload grades: %v", err)
+	}
+
+	svc := NewService(queries, gradeService)
+	profile, err := svc.InstructorProfile(context.Background(), "Dr. Smith")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if profile.Email != "smith@wwu.edu" || profile.GradeName != "Smith, John" || profile.Sections != 2 {
+		t.Errorf("profile = %+v", profile)
+	}
+	if !slices.Equal(profile.Terms, []string{"202520", "202510"}) {
+		t.Errorf("terms = %v", profile.Terms)
+	}
+	if profile.AvgEnrollment != 26.5 || profile.AvgCapacity != 30 || profile.LargestClass != 28 {
+		t.Errorf("class sizes = %v avg, %v capacity, %v largest", profile.AvgEnrollment, profile.AvgCapacity, profile.LargestClass)
+	}
+	if !slices.Equal(profile.MeetingPatterns, []PatternCount{{Pattern: "MWF 1000-1050", Sections: 2}}) {
+		t.Errorf("meeting patterns = %+v", profile.MeetingPatterns)
+	}
+	if profile.Grades == nil || profile.Grades.GPA != 3.2 {
+		t.Errorf("professor grades = %+v", profile.Grades)
+	}
+
+	if len(profile.Courses) != 1 {
+		t.Fatalf("courses = %+v", profile.Courses)
+	}
+	course := profile.Courses[0]
+	if course.Subject != "CSCI" || course.CourseNumber != "247" || course.Sections != 2 ||
+		!slices.Equal(course.Terms, []string{"202520", "202510"}) {
+		t.Errorf("course = %+v", course)
+	}
+	if course.Grades == nil || course.Grades.Instructor.GPA != 3.45 || course.Grades.Course.GPA != 3.30 {
+		t.Fatalf("course grades = %+v", course.Grades)
+	}
+	if math.Abs(*course.Grades.GPADiff-0.15) > 1e-9 {
+		t.Errorf("gpa diff = %v, expected 0.15", *course.Grades.GPADiff)
+	}
+}
+
+func TestInstructorProfile_Unmapped(t *testing.T) {
+	db, queries := testutil.SetupTestDB(t)
+	defer db.Close()
+	testutil.SeedTestData(t, db)
+
+	// Dr. Jones has no instructor mapping, so no grades even with data loaded
+	svc := NewService(queries, seedGrades(t, db, queries))
+	profile, err := svc.InstructorProfile(context.Background(), "Dr. Jones")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profile.GradeName != "" || profile.Grades != nil || profile.Courses[0].Grades != nil {
+		t.Errorf("expected no grades, got %+v", profile)
+	}
+
+	if _, err := svc.InstructorProfile(context.Background(), "Dr. Nobody"); !errors.Is(err, ErrInstructorNotFound) {
+		t.Errorf("expected ErrInstructorNotFound, got %v", err)
+	}
+}
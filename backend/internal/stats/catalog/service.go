@@ -0,0 +1,123 @@
+// Package catalog builds course and instructor histories from scraped
+// sections across terms, joined with grade aggregates.
+package catalog
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"schedule-optimizer/internal/stats/grades"
+	"schedule-optimizer/internal/store"
+)
+
+// MaxMeetingPatterns caps the meeting patterns returned per history.
+const MaxMeetingPatterns = 5
+
+// Service answers catalog history queries.
+type Service struct {
+	queries      *store.Queries
+	gradeService *grades.Service // Optional; grade fields are omitted when nil or not loaded
+}
+
+// NewService creates a new catalog service.
+func NewService(queries *store.Queries, gradeService *grades.Service) *Service {
+	return &Service{queries: queries, gradeService: gradeService}
+}
+
+// gradesLoaded reports whether grade aggregates are available.
+func (s *Service) gradesLoaded() bool {
+	return s.gradeService != nil && s.gradeService.IsLoaded()
+}
+
+// summarize converts an aggregate for the client, or returns nil.
+func summarize(agg *grades.GradeAggregate) *GradeSummary {
+	if agg == nil {
+		return nil
+	}
+	summary := &GradeSummary{
+		GPA:          agg.GPA,
+		PassRate:     agg.PassRate,
+		Sections:     agg.Sections,
+		Students:     agg.Students,
+		Distribution: make(map[string]int),
+	}
+	counts := []struct {
+		grade string
+		count int
+	}{
+		{"A", agg.CntA}, {"A-", agg.CntAM},
+		{"B+", agg.CntBP}, {"B", agg.CntB}, {"B-", agg.CntBM},
+		{"C+", agg.CntCP}, {"C", agg.CntC}, {"C-", agg.CntCM},
+		{"D+", agg.CntDP}, {"D", agg.CntD}, {"D-", agg.CntDM},
+		{"F", agg.CntF}, {"W", agg.CntW},
+		{"P", agg.CntP}, {"NP", agg.CntNP}, {"S", agg.CntS}, {"U", agg.CntU},
+	}
+	for _, c := range counts {
+		if c.count > 0 {
+			summary.Distribution[c.grade] = c.count
+		}
+	}
+	return summary
+}
+
+// dayOrder is the Banner letter for each meeting_times day column, in the
+// order patterns list them.
+var dayOrder = []struct {
+	letter string
+	meets  func(m *store.GetMeetingTimesBySectionIDsRow) bool
+}{
+	{"M", func(m *store.GetMeetingTimesBySectionIDsRow) bool { return m.Monday.Int64 != 0 }},
+	{"T", func(m *store.GetMeetingTimesBySectionIDsRow) bool { return m.Tuesday.Int64 != 0 }},
+	{"W", func(m *store.GetMeetingTimesBySectionIDsRow) bool { return m.Wednesday.Int64 != 0 }},
+	{"R", func(m *store.GetMeetingTimesBySectionIDsRow) bool { return m.Thursday.Int64 != 0 }},
+	{"F", func(m *store.GetMeetingTimesBySectionIDsRow) bool { return m.Friday.Int64 != 0 }},
+	{"S", func(m *store.GetMeetingTimesBySectionIDsRow) bool { return m.Saturday.Int64 != 0 }},
+	{"U", func(m *store.GetMeetingTimesBySectionIDsRow) bool { return m.Sunday.Int64 != 0 }},
+}
+
+// meetingPatterns counts sections by weekly pattern, most common first.
+// Meetings without days or times (async, TBA) are left out of a section's
+// pattern; sections with none of either aren't counted.
+func (s *Service) meetingPatterns(ctx context.Context, sectionIDs []int64) ([]PatternCount, error) {
+	meetings, err := s.queries.GetMeetingTimesBySectionIDs(ctx, sectionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	bySection := make(map[int64][]string)
+	for _, m := range meetings {
+		var days strings.Builder
+		for _, d := range dayOrder {
+			if d.meets(m) {
+				days.WriteString(d.letter)
+			}
+		}
+		if days.Len() == 0 || !m.StartTime.Valid || !m.EndTime.Valid || m.StartTime.String == "" {
+			continue
+		}
+		bySection[m.SectionID] = append(bySection[m.SectionID], days.String()+" "+m.StartTime.String+"-"+m.EndTime.String)
+	}
+
+	counts := make(map[string]int)
+	for _, parts := range bySection {
+		slices.Sort(parts)
+		counts[strings.Join(parts, ", ")]++
+	}
+
+	patterns := make([]PatternCount, 0, len(counts))
+	for pattern, n := range counts {
+		patterns = append(patterns, PatternCount{Pattern: pattern, Sections: n})
+	}
+	slices.SortFunc(patterns, func(a, b PatternCount) int {
+		if c := cmp.Compare(b.Sections, a.Sections); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Pattern, b.Pattern)
+	})
+	if len(patterns) > MaxMeetingPatterns {
+		patterns = patterns[:MaxMeetingPatterns]
+	}
+	return patterns, nil
+}
@@ -0,0 +1,115 @@
+package catalog
+
+import "time"
+
+// CourseHistory is everything known about a course across scraped terms.
+type CourseHistory struct {
+	Subject          string            `json:"subject"`
+	CourseNumber     string            `json:"courseNumber"`
+	Title            string            `json:"title"` // From the most recent offering
+	Credits          int               `json:"credits"`
+	Offerings        []TermOffering    `json:"offerings"` // Most recent first
+	MeetingPatterns  []PatternCount    `json:"meetingPatterns"`
+	Grades           *GradeSummary     `json:"grades,omitempty"` // Course-level aggregate, nil without grade data
+	InstructorGrades []InstructorGrade `json:"instructorGrades"`
+}
+
+// TermOffering summarizes a course's sections in one term.
+type TermOffering struct {
+	Term          string   `json:"term"`
+	Description   string   `json:"description,omitempty"`
+	Sections      int      `json:"sections"`
+	Enrollment    int64    `json:"enrollment"`
+	MaxEnrollment int64    `json:"maxEnrollment"`
+	FillRate      float64  `json:"fillRate"` // Enrollment / MaxEnrollment, 0 when capacity is unknown
+	WaitCount     int64    `json:"waitCount"`
+	Instructors   []string `json:"instructors"` // Primary instructors, sorted
+}
+
+// PatternCount is a weekly meeting pattern such as "MWF 1000-1050" and how
+// many sections used it.
+type PatternCount struct {
+	Pattern  string `json:"pattern"`
+	Sections int    `json:"sections"`
+}
+
+// InstructorGrade is the course+instructor grade aggregate for one instructor.
+type InstructorGrade struct {
+	Instructor string        `json:"instructor"` // Banner name
+	Grades     *GradeSummary `json:"grades"`
+}
+
+// GradeSummary is the client view of a grades.GradeAggregate.
+type GradeSummary struct {
+	GPA          float64        `json:"gpa"`
+	PassRate     *float64       `json:"passRate,omitempty"`
+	Sections     int            `json:"sections"`
+	Students     int            `json:"students"`
+	Distribution map[string]int `json:"distribution"` // Letter grade to count, nonzero grades only
+}
+
+// InstructorMatch is one instructor search result.
+type InstructorMatch struct {
+	Name       string `json:"name"`
+	Courses    int    `json:"courses"`
+	Sections   int    `json:"sections"`
+	LatestTerm string `json:"latestTerm"`
+}
+
+// InstructorProfile is what an instructor has taught across scraped terms.
+type InstructorProfile struct {
+	Name            string         `json:"name"`
+	Email           string         `json:"email,omitempty"`
+	GradeName       string         `json:"gradeName,omitempty"` // Name in the grade data, when mapped
+	Sections        int            `json:"sections"`
+	Terms           []string       `json:"terms"` // Terms taught, most recent first
+	AvgEnrollment   float64        `json:"avgEnrollment"`
+	AvgCapacity     float64        `json:"avgCapacity"`
+	LargestClass    int64          `json:"largestClass"`
+	Courses         []TaughtCourse `json:"courses"` // Most recently taught first
+	MeetingPatterns []PatternCount `json:"meetingPatterns"`
+	Grades          *GradeSummary  `json:"grades,omitempty"` // Professor-level aggregate across all courses
+}
+
+// TaughtCourse is one course an instructor has taught.
+type TaughtCourse struct {
+	Subject       string           `json:"subject"`
+	CourseNumber  string           `json:"courseNumber"`
+	Title         string           `json:"title"`
+	Terms         []string         `json:"terms"` // Most recent first
+	Sections      int              `json:"sections"`
+	AvgEnrollment float64          `json:"avgEnrollment"`
+	Grades        *GradeComparison `json:"grades,omitempty"`
+}
+
+// GradeComparison sets an instructor's course+professor aggregate against
+// the course-level average across all instructors.
+type GradeComparison struct {
+	Instructor *GradeSummary `json:"instructor"`
+	Course     *GradeSummary `json:"course,omitempty"`
+	GPADiff    *float64      `json:"gpaDiff,omitempty"` // Instructor GPA minus course GPA
+}
+
+// SectionTimeline is every change recorded for one section.
+type SectionTimeline struct {
+	Term         string          `json:"term"`
+	CRN          string          `json:"crn"`
+	Subject      string          `json:"subject,omitempty"` // Empty if the section is no longer stored
+	CourseNumber string          `json:"courseNumber,omitempty"`
+	Title        string          `json:"title,omitempty"`
+	Changes      []SectionChange `json:"changes"` // Oldest first
+}
+
+// SectionChange is one difference a scrape found in a section. Kinds are the
+// scraper.Change constants.
+type SectionChange struct {
+	ID           int64     `json:"id"`
+	CRN          string    `json:"crn,omitempty"` // Term change feed only
+	Subject      string    `json:"subject,omitempty"`
+	CourseNumber string    `json:"courseNumber,omitempty"`
+	Title        string    `json:"title,omitempty"`
+	Kind         string    `json:"kind"`
+	OldValue     *string   `json:"oldValue,omitempty"` // Omitted for "added"
+	NewValue     *string   `json:"newValue,omitempty"`
+	At           time.Time `json:"at"`
+}
@@ -251,6 +251,22 @@ func (s *Service) GetAggregate(level, subject, courseNumber, instructor string)
 	return nil
 }
 
+// MapSubject returns the grade-data subject for a Banner subject, for use
+// with GetAggregate. Returns the input unchanged if no mapping exists.
+func (s *Service) MapSubject(bannerSubject string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.mapSubject(bannerSubject)
+}
+
+// MapInstructor returns the grade-data name for a Banner instructor name, for
+// use with GetAggregate. Returns empty string if no mapping exists.
+func (s *Service) MapInstructor(bannerName string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.mapInstructor(bannerName)
+}
+
 // mapSubject translates a Banner subject to the grade-data subject.
 // Returns the input unchanged if no mapping exists.
 func (s *Service) mapSubject(bannerSubject string) string {
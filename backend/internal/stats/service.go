@@ -1,14 +1,18 @@
 // Package stats composes sub-services for statistics and analytics.
 package stats
 
-import "schedule-optimizer/internal/stats/grades"
+import (
+	"schedule-optimizer/internal/stats/catalog"
+	"schedule-optimizer/internal/stats/grades"
+)
 
 // Service coordinates statistics sub-services.
 type Service struct {
-	Grades *grades.Service
+	Grades  *grades.Service
+	Catalog *catalog.Service
 }
 
 // NewService creates a stats service with the provided sub-services.
-func NewService(gradeService *grades.Service) *Service {
-	return &Service{Grades: gradeService}
+func NewService(gradeService *grades.Service, catalogService *catalog.Service) *Service {
+	return &Service{Grades: gradeService, Catalog: catalogService}
 }
@@ -17,6 +17,24 @@ type Announcement struct {
 	CreatedAt sql.NullTime `json:"created_at"`
 }
 
+type CourseDetail struct {
+	Term          string         `json:"term"`
+	Subject       string         `json:"subject"`
+	CourseNumber  string         `json:"course_number"`
+	Description   sql.NullString `json:"description"`
+	Prerequisites sql.NullString `json:"prerequisites"`
+	Restrictions  sql.NullString `json:"restrictions"`
+	Fees          sql.NullString `json:"fees"`
+	FetchedAt     sql.NullTime   `json:"fetched_at"`
+}
+
+type CoursePrerequisite struct {
+	Term         string `json:"term"`
+	Subject      string `json:"subject"`
+	CourseNumber string `json:"course_number"`
+	Expression   string `json:"expression"`
+}
+
 type Feedback struct {
 	ID        int64          `json:"id"`
 	SessionID sql.NullString `json:"session_id"`
@@ -141,6 +159,59 @@ type MeetingTime struct {
 	HoursPerWeek        sql.NullFloat64 `json:"hours_per_week"`
 }
 
+type PrerequisiteBuild struct {
+	Term          string       `json:"term"`
+	ParserVersion int64        `json:"parser_version"`
+	BuiltAt       sql.NullTime `json:"built_at"`
+}
+
+type PrerequisiteEdge struct {
+	Term               string `json:"term"`
+	Subject            string `json:"subject"`
+	CourseNumber       string `json:"course_number"`
+	PrereqSubject      string `json:"prereq_subject"`
+	PrereqCourseNumber string `json:"prereq_course_number"`
+}
+
+type SavedSearch struct {
+	ID        int64        `json:"id"`
+	SessionID string       `json:"session_id"`
+	Name      string       `json:"name"`
+	Params    string       `json:"params"`
+	Snapshot  string       `json:"snapshot"`
+	Truncated int64        `json:"truncated"`
+	LastRunAt sql.NullTime `json:"last_run_at"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type SavedSearchAlert struct {
+	ID             int64         `json:"id"`
+	SavedSearchID  int64         `json:"saved_search_id"`
+	SessionID      string        `json:"session_id"`
+	Kind           string        `json:"kind"`
+	Term           string        `json:"term"`
+	Crn            string        `json:"crn"`
+	CourseKey      string        `json:"course_key"`
+	Title          string        `json:"title"`
+	SeatsAvailable sql.NullInt64 `json:"seats_available"`
+	CreatedAt      sql.NullTime  `json:"created_at"`
+}
+
+type ScrapeRun struct {
+	ID         int64          `json:"id"`
+	Term       string         `json:"term"`
+	Job        string         `json:"job"`
+	Status     string         `json:"status"`
+	Stored     int64          `json:"stored"`
+	Expected   int64          `json:"expected"`
+	PageErrors int64          `json:"page_errors"`
+	SaveErrors int64          `json:"save_errors"`
+	Removed    int64          `json:"removed"`
+	Error      sql.NullString `json:"error"`
+	StartedAt  sql.NullTime   `json:"started_at"`
+	FinishedAt sql.NullTime   `json:"finished_at"`
+}
+
 type SearchLog struct {
 	ID           int64           `json:"id"`
 	SessionID    sql.NullString  `json:"session_id"`
@@ -180,6 +251,8 @@ type Section struct {
 	WaitCount               sql.NullInt64  `json:"wait_count"`
 	IsOpen                  sql.NullInt64  `json:"is_open"`
 	UpdatedAt               sql.NullTime   `json:"updated_at"`
+	RemovedAt               sql.NullTime   `json:"removed_at"`
+	IsLinked                int64          `json:"is_linked"`
 }
 
 type SectionAttribute struct {
@@ -189,6 +262,23 @@ type SectionAttribute struct {
 	Description sql.NullString `json:"description"`
 }
 
+type SectionHistory struct {
+	ID        int64          `json:"id"`
+	Term      string         `json:"term"`
+	Crn       string         `json:"crn"`
+	Kind      string         `json:"kind"`
+	OldValue  sql.NullString `json:"old_value"`
+	NewValue  sql.NullString `json:"new_value"`
+	CreatedAt sql.NullTime   `json:"created_at"`
+}
+
+type SectionLink struct {
+	Term      string `json:"term"`
+	Crn       string `json:"crn"`
+	LinkGroup int64  `json:"link_group"`
+	LinkedCrn string `json:"linked_crn"`
+}
+
 type SubjectMapping struct {
 	BannerSubject string `json:"banner_subject"`
 	GradeSubject  string `json:"grade_subject"`
@@ -18,6 +18,15 @@ SELECT * FROM sections WHERE term = ? ORDER BY subject, course_number;
 -- name: GetSectionByTermAndCRN :one
 SELECT * FROM sections WHERE term = ? AND crn = ?;
 
+-- name: GetSectionStatesByCRNs :many
+SELECT
+    s.id, s.crn, s.title, s.max_enrollment, s.seats_available, s.is_open, s.removed_at,
+    i.name AS instructor_name
+FROM sections s
+LEFT JOIN instructors i ON s.id = i.section_id AND i.is_primary = 1
+WHERE s.term = sqlc.arg(term) AND s.crn IN (sqlc.slice('crns'))
+ORDER BY s.id, i.id;
+
 -- name: GetSectionsBySubject :many
 SELECT * FROM sections WHERE term = ? AND subject = ? ORDER BY course_number;
 
@@ -26,8 +35,8 @@ INSERT INTO sections (
     term, crn, subject, subject_description, course_number, sequence_number,
     title, campus, schedule_type, instructional_method, instructional_method_desc,
     credit_hours_low, credit_hours_high, enrollment, max_enrollment, seats_available,
-    wait_capacity, wait_count, is_open, updated_at
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+    wait_capacity, wait_count, is_open, is_linked, updated_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
 ON CONFLICT(term, crn) DO UPDATE SET
     subject = excluded.subject,
     subject_description = excluded.subject_description,
@@ -46,9 +55,16 @@ ON CONFLICT(term, crn) DO UPDATE SET
     wait_capacity = excluded.wait_capacity,
     wait_count = excluded.wait_count,
     is_open = excluded.is_open,
-    updated_at = CURRENT_TIMESTAMP
+    is_linked = excluded.is_linked,
+    updated_at = CURRENT_TIMESTAMP,
+    removed_at = NULL
 RETURNING id;
 
+-- name: MarkSectionsRemoved :many
+UPDATE sections SET removed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE term = sqlc.arg(term) AND removed_at IS NULL AND crn NOT IN (sqlc.slice('crns'))
+RETURNING crn;
+
 -- name: DeleteSectionsByTerm :exec
 DELETE FROM sections WHERE term = ?;
 
@@ -97,6 +113,91 @@ VALUES (?, ?, ?);
 -- name: DeleteSectionAttributesBySection :exec
 DELETE FROM section_attributes WHERE section_id = ?;
 
+-- name: GetCourseDetails :one
+SELECT * FROM course_details WHERE term = ? AND subject = ? AND course_number = ?;
+
+-- name: GetCourseDetailsFetchedAt :many
+SELECT subject, course_number, fetched_at FROM course_details WHERE term = ?;
+
+-- name: GetDetailSections :many
+SELECT subject, course_number, crn, is_linked FROM sections
+WHERE term = ? AND removed_at IS NULL
+ORDER BY subject, course_number, crn;
+
+-- name: UpsertCourseDetails :exec
+INSERT INTO course_details (
+    term, subject, course_number, description, prerequisites, restrictions, fees, fetched_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(term, subject, course_number) DO UPDATE SET
+    description = excluded.description,
+    prerequisites = excluded.prerequisites,
+    restrictions = excluded.restrictions,
+    fees = excluded.fees,
+    fetched_at = CURRENT_TIMESTAMP;
+
+-- name: GetSectionLinks :many
+SELECT link_group, linked_crn FROM section_links
+WHERE term = ? AND crn = ?
+ORDER BY link_group, linked_crn;
+
+-- name: GetSectionLinksByCourse :many
+SELECT l.crn, l.link_group, l.linked_crn
+FROM section_links l
+JOIN sections s ON s.term = l.term AND s.crn = l.crn
+WHERE s.term = ? AND s.subject = ? AND s.course_number = ?
+ORDER BY l.crn, l.link_group, l.linked_crn;
+
+-- name: InsertSectionLink :exec
+INSERT OR IGNORE INTO section_links (term, crn, link_group, linked_crn) VALUES (?, ?, ?, ?);
+
+-- name: DeleteSectionLinks :exec
+DELETE FROM section_links WHERE term = ? AND crn = ?;
+
+-- name: GetCoursePrerequisiteTexts :many
+SELECT subject, course_number, prerequisites FROM course_details
+WHERE term = ? AND prerequisites IS NOT NULL
+ORDER BY subject, course_number;
+
+-- name: GetCoursePrerequisite :one
+SELECT expression FROM course_prerequisites WHERE term = ? AND subject = ? AND course_number = ?;
+
+-- name: GetCoursePrerequisites :many
+SELECT subject, course_number, expression FROM course_prerequisites
+WHERE term = ?
+ORDER BY subject, course_number;
+
+-- name: GetPrerequisiteDependents :many
+SELECT subject, course_number FROM prerequisite_edges
+WHERE term = ? AND prereq_subject = ? AND prereq_course_number = ?
+ORDER BY subject, course_number;
+
+-- name: InsertCoursePrerequisite :exec
+INSERT INTO course_prerequisites (term, subject, course_number, expression) VALUES (?, ?, ?, ?);
+
+-- name: InsertPrerequisiteEdge :exec
+INSERT OR IGNORE INTO prerequisite_edges (
+    term, subject, course_number, prereq_subject, prereq_course_number
+) VALUES (?, ?, ?, ?, ?);
+
+-- name: DeleteCoursePrerequisites :exec
+DELETE FROM course_prerequisites WHERE term = ?;
+
+-- name: DeletePrerequisiteEdges :exec
+DELETE FROM prerequisite_edges WHERE term = ?;
+
+-- name: UpsertPrerequisiteBuild :exec
+INSERT INTO prerequisite_builds (term, parser_version, built_at)
+VALUES (?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(term) DO UPDATE SET
+    parser_version = excluded.parser_version,
+    built_at = CURRENT_TIMESTAMP;
+
+-- name: GetStalePrerequisiteTerms :many
+SELECT DISTINCT d.term FROM course_details d
+LEFT JOIN prerequisite_builds b ON b.term = d.term
+WHERE b.term IS NULL OR b.parser_version != ?
+ORDER BY d.term DESC;
+
 -- name: LogGeneration :one
 INSERT INTO generation_logs (
     session_id, term, courses_count, schedules_generated,
@@ -137,9 +238,14 @@ SELECT
     i.name AS instructor_name, i.email AS instructor_email
 FROM sections s
 LEFT JOIN instructors i ON s.id = i.section_id AND i.is_primary = 1
-WHERE s.term = ?
+WHERE s.term = ? AND s.removed_at IS NULL
 ORDER BY s.id;
 
+-- name: GetSectionSeatsUpdatedSince :many
+SELECT crn, enrollment, max_enrollment, seats_available, wait_count, is_open, updated_at, removed_at
+FROM sections
+WHERE term = sqlc.arg(term) AND datetime(updated_at) >= datetime(sqlc.arg(since));
+
 -- name: GetMeetingTimesByTerm :many
 SELECT
     m.section_id, m.start_time, m.end_time, m.building, m.room,
@@ -153,6 +259,32 @@ ORDER BY m.section_id;
 SELECT code, description, last_scraped_at FROM terms
 WHERE last_scraped_at IS NULL ORDER BY code DESC;
 
+-- name: CreateScrapeRun :one
+INSERT INTO scrape_runs (term, job) VALUES (?, ?)
+RETURNING id;
+
+-- name: FinishScrapeRun :exec
+UPDATE scrape_runs SET
+    status = ?, stored = ?, expected = ?, page_errors = ?, save_errors = ?, removed = ?, error = ?,
+    finished_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: FailUnfinishedScrapeRuns :execrows
+UPDATE scrape_runs SET status = 'failed', error = sqlc.arg(reason), finished_at = CURRENT_TIMESTAMP
+WHERE status = 'running';
+
+-- name: GetLastSuccessfulScrapeRun :one
+SELECT * FROM scrape_runs
+WHERE term = ? AND status = 'success'
+ORDER BY finished_at DESC, id DESC
+LIMIT 1;
+
+-- name: GetScrapeRuns :many
+SELECT * FROM scrape_runs
+WHERE (sqlc.arg(term) = '' OR term = sqlc.arg(term))
+ORDER BY id DESC
+LIMIT sqlc.arg(limit);
+
 -- name: CourseExistsAnyTerm :one
 SELECT EXISTS(
     SELECT 1 FROM sections
@@ -165,12 +297,24 @@ FROM sections
 WHERE term = ?
 ORDER BY subject;
 
+-- name: GetSubjectDescriptions :many
+SELECT DISTINCT subject, subject_description
+FROM sections
+ORDER BY subject;
+
+-- name: GetTermCourses :many
+SELECT subject, course_number, CAST(MAX(title) AS TEXT) AS title
+FROM sections
+WHERE term = ? AND removed_at IS NULL
+GROUP BY subject, course_number
+ORDER BY subject, course_number;
+
 -- name: GetSectionWithInstructorByTermAndCRN :one
 SELECT
     s.id, s.term, s.crn, s.subject, s.subject_description,
     s.course_number, s.title, s.credit_hours_low,
     s.enrollment, s.max_enrollment, s.seats_available, s.wait_count, s.is_open,
-    s.instructional_method,
+    s.instructional_method, s.removed_at,
     i.name AS instructor_name, i.email AS instructor_email
 FROM sections s
 LEFT JOIN instructors i ON s.id = i.section_id AND i.is_primary = 1
@@ -185,51 +329,70 @@ SELECT
     i.name AS instructor_name, i.email AS instructor_email
 FROM sections s
 LEFT JOIN instructors i ON s.id = i.section_id AND i.is_primary = 1
-WHERE s.term = ? AND s.subject = ? AND s.course_number = ?
+WHERE s.term = ? AND s.subject = ? AND s.course_number = ? AND s.removed_at IS NULL
 ORDER BY s.crn;
 
+-- name: GetCourseHistory :many
+SELECT
+    s.id, s.term, t.description AS term_description, s.crn, s.title,
+    s.credit_hours_low, s.enrollment, s.max_enrollment, s.wait_count,
+    i.name AS instructor_name
+FROM sections s
+LEFT JOIN terms t ON t.code = s.term
+LEFT JOIN instructors i ON s.id = i.section_id AND i.is_primary = 1
+WHERE s.subject = ? AND s.course_number = ?
+ORDER BY s.term DESC, s.crn;
+
+-- name: SearchInstructors :many
+SELECT
+    i.name,
+    COUNT(DISTINCT s.subject || ':' || s.course_number) AS course_count,
+    COUNT(*) AS section_count,
+    CAST(MAX(s.term) AS TEXT) AS latest_term
+FROM instructors i
+JOIN sections s ON s.id = i.section_id
+WHERE i.name LIKE ? ESCAPE '\'
+GROUP BY i.name
+ORDER BY section_count DESC, i.name
+LIMIT ?;
+
+-- name: GetInstructorSections :many
+SELECT
+    s.id, s.term, t.description AS term_description, s.crn,
+    s.subject, s.course_number, s.title, s.enrollment, s.max_enrollment,
+    i.email, i.is_primary
+FROM instructors i
+JOIN sections s ON s.id = i.section_id
+LEFT JOIN terms t ON t.code = s.term
+WHERE i.name = ?
+ORDER BY s.term DESC, s.subject, s.course_number, s.crn;
+
+-- name: InsertSectionHistory :exec
+INSERT INTO section_history (term, crn, kind, old_value, new_value) VALUES (?, ?, ?, ?, ?);
+
+-- name: GetSectionHistory :many
+SELECT * FROM section_history WHERE term = ? AND crn = ? ORDER BY id;
+
+-- name: GetTermChanges :many
+SELECT h.id, h.crn, h.kind, h.old_value, h.new_value, h.created_at,
+       s.subject, s.course_number, s.title
+FROM section_history h
+LEFT JOIN sections s ON s.term = h.term AND s.crn = h.crn
+WHERE h.term = sqlc.arg(term) AND h.id > sqlc.arg(after)
+  AND (sqlc.arg(kind) = '' OR h.kind = sqlc.arg(kind))
+ORDER BY h.id
+LIMIT sqlc.arg(limit);
+
 -- name: ValidateCourseForTerm :one
 SELECT
     COUNT(*) AS section_count,
     COALESCE(MAX(title), '') AS title
 FROM sections
-WHERE term = ? AND subject = ? AND course_number = ?;
+WHERE term = ? AND subject = ? AND course_number = ? AND removed_at IS NULL;
 
 -- name: CheckSchemaExists :one
 SELECT COUNT(*) AS count FROM sections;
 
--- name: SearchSections :many
-SELECT
-    s.id, s.term, s.crn, s.subject, s.subject_description,
-    s.course_number, s.title, s.credit_hours_low, s.credit_hours_high,
-    s.enrollment, s.max_enrollment, s.seats_available, s.wait_count, s.is_open,
-    s.instructional_method, s.schedule_type, s.campus,
-    i.name AS instructor_name, i.email AS instructor_email
-FROM sections s
-LEFT JOIN instructors i ON s.id = i.section_id AND i.is_primary = 1
-WHERE
-    -- Term filter (NULL = all terms)
-    (sqlc.narg('term') IS NULL OR s.term = sqlc.narg('term'))
-    -- Subject filter (exact match)
-    AND (sqlc.narg('subject') IS NULL OR s.subject = sqlc.narg('subject'))
-    -- Course number filter (supports LIKE for wildcards)
-    AND (sqlc.narg('course_number') IS NULL OR s.course_number LIKE sqlc.narg('course_number'))
-    -- Title tokens (up to 3)
-    AND (sqlc.narg('title_t1') IS NULL OR LOWER(s.title) LIKE '%' || LOWER(sqlc.narg('title_t1')) || '%')
-    AND (sqlc.narg('title_t2') IS NULL OR LOWER(s.title) LIKE '%' || LOWER(sqlc.narg('title_t2')) || '%')
-    AND (sqlc.narg('title_t3') IS NULL OR LOWER(s.title) LIKE '%' || LOWER(sqlc.narg('title_t3')) || '%')
-    -- Instructor tokens (up to 3)
-    AND (sqlc.narg('instr_t1') IS NULL OR LOWER(i.name) LIKE '%' || LOWER(sqlc.narg('instr_t1')) || '%')
-    AND (sqlc.narg('instr_t2') IS NULL OR LOWER(i.name) LIKE '%' || LOWER(sqlc.narg('instr_t2')) || '%')
-    AND (sqlc.narg('instr_t3') IS NULL OR LOWER(i.name) LIKE '%' || LOWER(sqlc.narg('instr_t3')) || '%')
-    -- Open seats filter
-    AND (sqlc.narg('open_seats') IS NULL OR sqlc.narg('open_seats') = 0 OR s.seats_available > 0)
-    -- Credit range
-    AND (sqlc.narg('min_credits') IS NULL OR s.credit_hours_low >= sqlc.narg('min_credits'))
-    AND (sqlc.narg('max_credits') IS NULL OR s.credit_hours_low <= sqlc.narg('max_credits'))
-ORDER BY s.term DESC, s.subject, s.course_number, s.crn
-LIMIT sqlc.arg('result_limit');
-
 -- name: GetActiveAnnouncement :one
 SELECT id, title, body, type FROM announcements
 WHERE active = 1 ORDER BY id DESC LIMIT 1;
@@ -237,6 +400,42 @@ WHERE active = 1 ORDER BY id DESC LIMIT 1;
 -- name: InsertFeedback :exec
 INSERT INTO feedback (session_id, message) VALUES (?, ?);
 
+-- name: CreateSavedSearch :one
+INSERT INTO saved_searches (session_id, name, params, snapshot, truncated, last_run_at)
+VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+RETURNING id;
+
+-- name: CountSavedSearchesBySession :one
+SELECT COUNT(*) FROM saved_searches WHERE session_id = ?;
+
+-- name: GetSavedSearchesBySession :many
+SELECT * FROM saved_searches WHERE session_id = ? ORDER BY id;
+
+-- name: GetSavedSearches :many
+SELECT * FROM saved_searches ORDER BY id;
+
+-- name: UpdateSavedSearchSnapshot :exec
+UPDATE saved_searches SET snapshot = ?, truncated = ?, last_run_at = CURRENT_TIMESTAMP WHERE id = ?;
+
+-- name: DeleteSavedSearch :execrows
+DELETE FROM saved_searches WHERE id = ? AND session_id = ?;
+
+-- name: InsertSavedSearchAlert :one
+INSERT INTO saved_search_alerts (saved_search_id, session_id, kind, term, crn, course_key, title, seats_available)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, created_at;
+
+-- name: GetSavedSearchAlerts :many
+SELECT * FROM saved_search_alerts
+WHERE session_id = ? AND id > ?
+ORDER BY id LIMIT ?;
+
+-- name: DeleteSavedSearchAlerts :exec
+DELETE FROM saved_search_alerts WHERE saved_search_id = ?;
+
+-- name: DeleteSavedSearchAlertsBefore :exec
+DELETE FROM saved_search_alerts WHERE created_at < datetime('now', sqlc.arg(age));
+
 -- name: InsertGradeRow :exec
 INSERT INTO grade_rows (term, crn, subject, course_number, title, professor,
     students_enrolled, grade_count,
@@ -22,6 +22,17 @@ func (q *Queries) CheckSchemaExists(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const countSavedSearchesBySession = `-- name: CountSavedSearchesBySession :one
+SELECT COUNT(*) FROM saved_searches WHERE session_id = ?
+`
+
+func (q *Queries) CountSavedSearchesBySession(ctx context.Context, sessionID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSavedSearchesBySession, sessionID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const courseExistsAnyTerm = `-- name: CourseExistsAnyTerm :one
 SELECT EXISTS(
     SELECT 1 FROM sections
@@ -41,6 +52,50 @@ func (q *Queries) CourseExistsAnyTerm(ctx context.Context, arg CourseExistsAnyTe
 	return course_exists, err
 }
 
+const createSavedSearch = `-- name: CreateSavedSearch :one
+INSERT INTO saved_searches (session_id, name, params, snapshot, truncated, last_run_at)
+VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+RETURNING id
+`
+
+type CreateSavedSearchParams struct {
+	SessionID string `json:"session_id"`
+	Name      string `json:"name"`
+	Params    string `json:"params"`
+	Snapshot  string `json:"snapshot"`
+	Truncated int64  `json:"truncated"`
+}
+
+func (q *Queries) CreateSavedSearch(ctx context.Context, arg CreateSavedSearchParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createSavedSearch,
+		arg.SessionID,
+		arg.Name,
+		arg.Params,
+		arg.Snapshot,
+		arg.Truncated,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createScrapeRun = `-- name: CreateScrapeRun :one
+INSERT INTO scrape_runs (term, job) VALUES (?, ?)
+RETURNING id
+`
+
+type CreateScrapeRunParams struct {
+	Term string `json:"term"`
+	Job  string `json:"job"`
+}
+
+func (q *Queries) CreateScrapeRun(ctx context.Context, arg CreateScrapeRunParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createScrapeRun, arg.Term, arg.Job)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
 const deleteAllGradeAggregates = `-- name: DeleteAllGradeAggregates :exec
 DELETE FROM grade_aggregates
 `
@@ -50,6 +105,15 @@ func (q *Queries) DeleteAllGradeAggregates(ctx context.Context) error {
 	return err
 }
 
+const deleteCoursePrerequisites = `-- name: DeleteCoursePrerequisites :exec
+DELETE FROM course_prerequisites WHERE term = ?
+`
+
+func (q *Queries) DeleteCoursePrerequisites(ctx context.Context, term string) error {
+	_, err := q.db.ExecContext(ctx, deleteCoursePrerequisites, term)
+	return err
+}
+
 const deleteInstructorsBySection = `-- name: DeleteInstructorsBySection :exec
 DELETE FROM instructors WHERE section_id = ?
 `
@@ -68,6 +132,50 @@ func (q *Queries) DeleteMeetingTimesBySection(ctx context.Context, sectionID int
 	return err
 }
 
+const deletePrerequisiteEdges = `-- name: DeletePrerequisiteEdges :exec
+DELETE FROM prerequisite_edges WHERE term = ?
+`
+
+func (q *Queries) DeletePrerequisiteEdges(ctx context.Context, term string) error {
+	_, err := q.db.ExecContext(ctx, deletePrerequisiteEdges, term)
+	return err
+}
+
+const deleteSavedSearch = `-- name: DeleteSavedSearch :execrows
+DELETE FROM saved_searches WHERE id = ? AND session_id = ?
+`
+
+type DeleteSavedSearchParams struct {
+	ID        int64  `json:"id"`
+	SessionID string `json:"session_id"`
+}
+
+func (q *Queries) DeleteSavedSearch(ctx context.Context, arg DeleteSavedSearchParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteSavedSearch, arg.ID, arg.SessionID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteSavedSearchAlerts = `-- name: DeleteSavedSearchAlerts :exec
+DELETE FROM saved_search_alerts WHERE saved_search_id = ?
+`
+
+func (q *Queries) DeleteSavedSearchAlerts(ctx context.Context, savedSearchID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSavedSearchAlerts, savedSearchID)
+	return err
+}
+
+const deleteSavedSearchAlertsBefore = `-- name: DeleteSavedSearchAlertsBefore :exec
+DELETE FROM saved_search_alerts WHERE created_at < datetime('now', ?1)
+`
+
+func (q *Queries) DeleteSavedSearchAlertsBefore(ctx context.Context, age interface{}) error {
+	_, err := q.db.ExecContext(ctx, deleteSavedSearchAlertsBefore, age)
+	return err
+}
+
 const deleteSectionAttributesBySection = `-- name: DeleteSectionAttributesBySection :exec
 DELETE FROM section_attributes WHERE section_id = ?
 `
@@ -77,6 +185,20 @@ func (q *Queries) DeleteSectionAttributesBySection(ctx context.Context, sectionI
 	return err
 }
 
+const deleteSectionLinks = `-- name: DeleteSectionLinks :exec
+DELETE FROM section_links WHERE term = ? AND crn = ?
+`
+
+type DeleteSectionLinksParams struct {
+	Term string `json:"term"`
+	Crn  string `json:"crn"`
+}
+
+func (q *Queries) DeleteSectionLinks(ctx context.Context, arg DeleteSectionLinksParams) error {
+	_, err := q.db.ExecContext(ctx, deleteSectionLinks, arg.Term, arg.Crn)
+	return err
+}
+
 const deleteSectionsByTerm = `-- name: DeleteSectionsByTerm :exec
 DELETE FROM sections WHERE term = ?
 `
@@ -86,6 +208,51 @@ func (q *Queries) DeleteSectionsByTerm(ctx context.Context, term string) error {
 	return err
 }
 
+const failUnfinishedScrapeRuns = `-- name: FailUnfinishedScrapeRuns :execrows
+UPDATE scrape_runs SET status = 'failed', error = ?1, finished_at = CURRENT_TIMESTAMP
+WHERE status = 'running'
+`
+
+func (q *Queries) FailUnfinishedScrapeRuns(ctx context.Context, reason sql.NullString) (int64, error) {
+	result, err := q.db.ExecContext(ctx, failUnfinishedScrapeRuns, reason)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const finishScrapeRun = `-- name: FinishScrapeRun :exec
+UPDATE scrape_runs SET
+    status = ?, stored = ?, expected = ?, page_errors = ?, save_errors = ?, removed = ?, error = ?,
+    finished_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type FinishScrapeRunParams struct {
+	Status     string         `json:"status"`
+	Stored     int64          `json:"stored"`
+	Expected   int64          `json:"expected"`
+	PageErrors int64          `json:"page_errors"`
+	SaveErrors int64          `json:"save_errors"`
+	Removed    int64          `json:"removed"`
+	Error      sql.NullString `json:"error"`
+	ID         int64          `json:"id"`
+}
+
+func (q *Queries) FinishScrapeRun(ctx context.Context, arg FinishScrapeRunParams) error {
+	_, err := q.db.ExecContext(ctx, finishScrapeRun,
+		arg.Status,
+		arg.Stored,
+		arg.Expected,
+		arg.PageErrors,
+		arg.SaveErrors,
+		arg.Removed,
+		arg.Error,
+		arg.ID,
+	)
+	return err
+}
+
 const getActiveAnnouncement = `-- name: GetActiveAnnouncement :one
 SELECT id, title, body, type FROM announcements
 WHERE active = 1 ORDER BY id DESC LIMIT 1
@@ -218,6 +385,257 @@ func (q *Queries) GetAllGradeRows(ctx context.Context) ([]*GradeRow, error) {
 	return items, nil
 }
 
+const getCourseDetails = `-- name: GetCourseDetails :one
+SELECT term, subject, course_number, description, prerequisites, restrictions, fees, fetched_at FROM course_details WHERE term = ? AND subject = ? AND course_number = ?
+`
+
+type GetCourseDetailsParams struct {
+	Term         string `json:"term"`
+	Subject      string `json:"subject"`
+	CourseNumber string `json:"course_number"`
+}
+
+func (q *Queries) GetCourseDetails(ctx context.Context, arg GetCourseDetailsParams) (*CourseDetail, error) {
+	row := q.db.QueryRowContext(ctx, getCourseDetails, arg.Term, arg.Subject, arg.CourseNumber)
+	var i CourseDetail
+	err := row.Scan(
+		&i.Term,
+		&i.Subject,
+		&i.CourseNumber,
+		&i.Description,
+		&i.Prerequisites,
+		&i.Restrictions,
+		&i.Fees,
+		&i.FetchedAt,
+	)
+	return &i, err
+}
+
+const getCourseDetailsFetchedAt = `-- name: GetCourseDetailsFetchedAt :many
+SELECT subject, course_number, fetched_at FROM course_details WHERE term = ?
+`
+
+type GetCourseDetailsFetchedAtRow struct {
+	Subject      string       `json:"subject"`
+	CourseNumber string       `json:"course_number"`
+	FetchedAt    sql.NullTime `json:"fetched_at"`
+}
+
+func (q *Queries) GetCourseDetailsFetchedAt(ctx context.Context, term string) ([]*GetCourseDetailsFetchedAtRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCourseDetailsFetchedAt, term)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*GetCourseDetailsFetchedAtRow{}
+	for rows.Next() {
+		var i GetCourseDetailsFetchedAtRow
+		if err := rows.Scan(&i.Subject, &i.CourseNumber, &i.FetchedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCourseHistory = `-- name: GetCourseHistory :many
+SELECT
+    s.id, s.term, t.description AS term_description, s.crn, s.title,
+    s.credit_hours_low, s.enrollment, s.max_enrollment, s.wait_count,
+    i.name AS instructor_name
+FROM sections s
+LEFT JOIN terms t ON t.code = s.term
+LEFT JOIN instructors i ON s.id = i.section_id AND i.is_primary = 1
+WHERE s.subject = ? AND s.course_number = ?
+ORDER BY s.term DESC, s.crn
+`
+
+type GetCourseHistoryParams struct {
+	Subject      string `json:"subject"`
+	CourseNumber string `json:"course_number"`
+}
+
+type GetCourseHistoryRow struct {
+	ID              int64          `json:"id"`
+	Term            string         `json:"term"`
+	TermDescription sql.NullString `json:"term_description"`
+	Crn             string         `json:"crn"`
+	Title           string         `json:"title"`
+	CreditHoursLow  sql.NullInt64  `json:"credit_hours_low"`
+	Enrollment      sql.NullInt64  `json:"enrollment"`
+	MaxEnrollment   sql.NullInt64  `json:"max_enrollment"`
+	WaitCount       sql.NullInt64  `json:"wait_count"`
+	InstructorName  sql.NullString `json:"instructor_name"`
+}
+
+func (q *Queries) GetCourseHistory(ctx context.Context, arg GetCourseHistoryParams) ([]*GetCourseHistoryRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCourseHistory, arg.Subject, arg.CourseNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*GetCourseHistoryRow{}
+	for rows.Next() {
+		var i GetCourseHistoryRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Term,
+			&i.TermDescription,
+			&i.Crn,
+			&i.Title,
+			&i.CreditHoursLow,
+			&i.Enrollment,
+			&i.MaxEnrollment,
+			&i.WaitCount,
+			&i.InstructorName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCoursePrerequisite = `-- name: GetCoursePrerequisite :one
+SELECT expression FROM course_prerequisites WHERE term = ? AND subject = ? AND course_number = ?
+`
+
+type GetCoursePrerequisiteParams struct {
+	Term         string `json:"term"`
+	Subject      string `json:"subject"`
+	CourseNumber string `json:"course_number"`
+}
+
+func (q *Queries) GetCoursePrerequisite(ctx context.Context, arg GetCoursePrerequisiteParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getCoursePrerequisite, arg.Term, arg.Subject, arg.CourseNumber)
+	var expression string
+	err := row.Scan(&expression)
+	return expression, err
+}
+
+const getCoursePrerequisiteTexts = `-- name: GetCoursePrerequisiteTexts :many
+SELECT subject, course_number, prerequisites FROM course_details
+WHERE term = ? AND prerequisites IS NOT NULL
+ORDER BY subject, course_number
+`
+
+type GetCoursePrerequisiteTextsRow struct {
+	Subject       string         `json:"subject"`
+	CourseNumber  string         `json:"course_number"`
+	Prerequisites sql.NullString `json:"prerequisites"`
+}
+
+func (q *Queries) GetCoursePrerequisiteTexts(ctx context.Context, term string) ([]*GetCoursePrerequisiteTextsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCoursePrerequisiteTexts, term)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*GetCoursePrerequisiteTextsRow{}
+	for rows.Next() {
+		var i GetCoursePrerequisiteTextsRow
+		if err := rows.Scan(&i.Subject, &i.CourseNumber, &i.Prerequisites); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCoursePrerequisites = `-- name: GetCoursePrerequisites :many
+SELECT subject, course_number, expression FROM course_prerequisites
+WHERE term = ?
+ORDER BY subject, course_number
+`
+
+type GetCoursePrerequisitesRow struct {
+	Subject      string `json:"subject"`
+	CourseNumber string `json:"course_number"`
+	Expression   string `json:"expression"`
+}
+
+func (q *Queries) GetCoursePrerequisites(ctx context.Context, term string) ([]*GetCoursePrerequisitesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCoursePrerequisites, term)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*GetCoursePrerequisitesRow{}
+	for rows.Next() {
+		var i GetCoursePrerequisitesRow
+		if err := rows.Scan(&i.Subject, &i.CourseNumber, &i.Expression); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDetailSections = `-- name: GetDetailSections :many
+SELECT subject, course_number, crn, is_linked FROM sections
+WHERE term = ? AND removed_at IS NULL
+ORDER BY subject, course_number, crn
+`
+
+type GetDetailSectionsRow struct {
+	Subject      string `json:"subject"`
+	CourseNumber string `json:"course_number"`
+	Crn          string `json:"crn"`
+	IsLinked     int64  `json:"is_linked"`
+}
+
+func (q *Queries) GetDetailSections(ctx context.Context, term string) ([]*GetDetailSectionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getDetailSections, term)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*GetDetailSectionsRow{}
+	for rows.Next() {
+		var i GetDetailSectionsRow
+		if err := rows.Scan(
+			&i.Subject,
+			&i.CourseNumber,
+			&i.Crn,
+			&i.IsLinked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getDistinctSubjects = `-- name: GetDistinctSubjects :many
 SELECT DISTINCT subject FROM sections ORDER BY subject
 `
@@ -397,27 +815,54 @@ func (q *Queries) GetInstructorMappings(ctx context.Context) ([]*InstructorMappi
 	return items, nil
 }
 
-const getInstructorsBySection = `-- name: GetInstructorsBySection :many
-SELECT id, section_id, banner_id, name, email, is_primary FROM instructors WHERE section_id = ?
+const getInstructorSections = `-- name: GetInstructorSections :many
+SELECT
+    s.id, s.term, t.description AS term_description, s.crn,
+    s.subject, s.course_number, s.title, s.enrollment, s.max_enrollment,
+    i.email, i.is_primary
+FROM instructors i
+JOIN sections s ON s.id = i.section_id
+LEFT JOIN terms t ON t.code = s.term
+WHERE i.name = ?
+ORDER BY s.term DESC, s.subject, s.course_number, s.crn
 `
 
-func (q *Queries) GetInstructorsBySection(ctx context.Context, sectionID int64) ([]*Instructor, error) {
-	rows, err := q.db.QueryContext(ctx, getInstructorsBySection, sectionID)
+type GetInstructorSectionsRow struct {
+	ID              int64          `json:"id"`
+	Term            string         `json:"term"`
+	TermDescription sql.NullString `json:"term_description"`
+	Crn             string         `json:"crn"`
+	Subject         string         `json:"subject"`
+	CourseNumber    string         `json:"course_number"`
+	Title           string         `json:"title"`
+	Enrollment      sql.NullInt64  `json:"enrollment"`
+	MaxEnrollment   sql.NullInt64  `json:"max_enrollment"`
+	Email           sql.NullString `json:"email"`
+	IsPrimary       sql.NullInt64  `json:"is_primary"`
+}
+
+func (q *Queries) GetInstructorSections(ctx context.Context, name string) ([]*GetInstructorSectionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getInstructorSections, name)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []*Instructor{}
+	items := []*GetInstructorSectionsRow{}
 	for rows.Next() {
-		var i Instructor
+		var i GetInstructorSectionsRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.SectionID,
-			&i.BannerID,
-			&i.Name,
-			&i.Email,
-			&i.IsPrimary,
-		); err != nil {
+			&i.Term,
+			&i.TermDescription,
+			&i.Crn,
+			&i.Subject,
+			&i.CourseNumber,
+			&i.Title,
+			&i.Enrollment,
+			&i.MaxEnrollment,
+			&i.Email,
+			&i.IsPrimary,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, &i)
@@ -431,6 +876,67 @@ func (q *Queries) GetInstructorsBySection(ctx context.Context, sectionID int64)
 	return items, nil
 }
 
+const getInstructorsBySection = `-- name: GetInstructorsBySection :many
+SELECT id, section_id, banner_id, name, email, is_primary FROM instructors WHERE section_id = ?
+`
+
+func (q *Queries) GetInstructorsBySection(ctx context.Context, sectionID int64) ([]*Instructor, error) {
+	rows, err := q.db.QueryContext(ctx, getInstructorsBySection, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Instructor{}
+	for rows.Next() {
+		var i Instructor
+		if err := rows.Scan(
+			&i.ID,
+			&i.SectionID,
+			&i.BannerID,
+			&i.Name,
+			&i.Email,
+			&i.IsPrimary,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLastSuccessfulScrapeRun = `-- name: GetLastSuccessfulScrapeRun :one
+SELECT id, term, job, status, stored, expected, page_errors, save_errors, removed, error, started_at, finished_at FROM scrape_runs
+WHERE term = ? AND status = 'success'
+ORDER BY finished_at DESC, id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLastSuccessfulScrapeRun(ctx context.Context, term string) (*ScrapeRun, error) {
+	row := q.db.QueryRowContext(ctx, getLastSuccessfulScrapeRun, term)
+	var i ScrapeRun
+	err := row.Scan(
+		&i.ID,
+		&i.Term,
+		&i.Job,
+		&i.Status,
+		&i.Stored,
+		&i.Expected,
+		&i.PageErrors,
+		&i.SaveErrors,
+		&i.Removed,
+		&i.Error,
+		&i.StartedAt,
+		&i.FinishedAt,
+	)
+	return &i, err
+}
+
 const getMeetingTimesBySection = `-- name: GetMeetingTimesBySection :many
 SELECT id, section_id, start_time, end_time, start_date, end_date, building, building_description, room, monday, tuesday, wednesday, thursday, friday, saturday, sunday, schedule_type, meeting_type, credit_hours, hours_per_week FROM meeting_times WHERE section_id = ?
 `
@@ -610,6 +1116,46 @@ func (q *Queries) GetMeetingTimesByTerm(ctx context.Context, term string) ([]*Ge
 	return items, nil
 }
 
+const getPrerequisiteDependents = `-- name: GetPrerequisiteDependents :many
+SELECT subject, course_number FROM prerequisite_edges
+WHERE term = ? AND prereq_subject = ? AND prereq_course_number = ?
+ORDER BY subject, course_number
+`
+
+type GetPrerequisiteDependentsParams struct {
+	Term               string `json:"term"`
+	PrereqSubject      string `json:"prereq_subject"`
+	PrereqCourseNumber string `json:"prereq_course_number"`
+}
+
+type GetPrerequisiteDependentsRow struct {
+	Subject      string `json:"subject"`
+	CourseNumber string `json:"course_number"`
+}
+
+func (q *Queries) GetPrerequisiteDependents(ctx context.Context, arg GetPrerequisiteDependentsParams) ([]*GetPrerequisiteDependentsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPrerequisiteDependents, arg.Term, arg.PrereqSubject, arg.PrereqCourseNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*GetPrerequisiteDependentsRow{}
+	for rows.Next() {
+		var i GetPrerequisiteDependentsRow
+		if err := rows.Scan(&i.Subject, &i.CourseNumber); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPrimaryInstructorBySection = `-- name: GetPrimaryInstructorBySection :one
 SELECT id, section_id, banner_id, name, email, is_primary FROM instructors WHERE section_id = ? AND is_primary = 1
 `
@@ -628,6 +1174,172 @@ func (q *Queries) GetPrimaryInstructorBySection(ctx context.Context, sectionID i
 	return &i, err
 }
 
+const getSavedSearchAlerts = `-- name: GetSavedSearchAlerts :many
+SELECT id, saved_search_id, session_id, kind, term, crn, course_key, title, seats_available, created_at FROM saved_search_alerts
+WHERE session_id = ? AND id > ?
+ORDER BY id LIMIT ?
+`
+
+type GetSavedSearchAlertsParams struct {
+	SessionID string `json:"session_id"`
+	ID        int64  `json:"id"`
+	Limit     int64  `json:"limit"`
+}
+
+func (q *Queries) GetSavedSearchAlerts(ctx context.Context, arg GetSavedSearchAlertsParams) ([]*SavedSearchAlert, error) {
+	rows, err := q.db.QueryContext(ctx, getSavedSearchAlerts, arg.SessionID, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*SavedSearchAlert{}
+	for rows.Next() {
+		var i SavedSearchAlert
+		if err := rows.Scan(
+			&i.ID,
+			&i.SavedSearchID,
+			&i.SessionID,
+			&i.Kind,
+			&i.Term,
+			&i.Crn,
+			&i.CourseKey,
+			&i.Title,
+			&i.SeatsAvailable,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSavedSearches = `-- name: GetSavedSearches :many
+SELECT id, session_id, name, params, snapshot, truncated, last_run_at, created_at FROM saved_searches ORDER BY id
+`
+
+func (q *Queries) GetSavedSearches(ctx context.Context) ([]*SavedSearch, error) {
+	rows, err := q.db.QueryContext(ctx, getSavedSearches)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*SavedSearch{}
+	for rows.Next() {
+		var i SavedSearch
+		if err := rows.Scan(
+			&i.ID,
+			&i.SessionID,
+			&i.Name,
+			&i.Params,
+			&i.Snapshot,
+			&i.Truncated,
+			&i.LastRunAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSavedSearchesBySession = `-- name: GetSavedSearchesBySession :many
+SELECT id, session_id, name, params, snapshot, truncated, last_run_at, created_at FROM saved_searches WHERE session_id = ? ORDER BY id
+`
+
+func (q *Queries) GetSavedSearchesBySession(ctx context.Context, sessionID string) ([]*SavedSearch, error) {
+	rows, err := q.db.QueryContext(ctx, getSavedSearchesBySession, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*SavedSearch{}
+	for rows.Next() {
+		var i SavedSearch
+		if err := rows.Scan(
+			&i.ID,
+			&i.SessionID,
+			&i.Name,
+			&i.Params,
+			&i.Snapshot,
+			&i.Truncated,
+			&i.LastRunAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getScrapeRuns = `-- name: GetScrapeRuns :many
+SELECT id, term, job, status, stored, expected, page_errors, save_errors, removed, error, started_at, finished_at FROM scrape_runs
+WHERE (?1 = '' OR term = ?1)
+ORDER BY id DESC
+LIMIT ?2
+`
+
+type GetScrapeRunsParams struct {
+	Term  string `json:"term"`
+	Limit int64  `json:"limit"`
+}
+
+func (q *Queries) GetScrapeRuns(ctx context.Context, arg GetScrapeRunsParams) ([]*ScrapeRun, error) {
+	rows, err := q.db.QueryContext(ctx, getScrapeRuns, arg.Term, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*ScrapeRun{}
+	for rows.Next() {
+		var i ScrapeRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.Term,
+			&i.Job,
+			&i.Status,
+			&i.Stored,
+			&i.Expected,
+			&i.PageErrors,
+			&i.SaveErrors,
+			&i.Removed,
+			&i.Error,
+			&i.StartedAt,
+			&i.FinishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSectionAttributesBySection = `-- name: GetSectionAttributesBySection :many
 SELECT id, section_id, code, description FROM section_attributes WHERE section_id = ?
 `
@@ -661,7 +1373,7 @@ func (q *Queries) GetSectionAttributesBySection(ctx context.Context, sectionID i
 }
 
 const getSectionByTermAndCRN = `-- name: GetSectionByTermAndCRN :one
-SELECT id, term, crn, subject, subject_description, course_number, sequence_number, title, campus, schedule_type, instructional_method, instructional_method_desc, credit_hours_low, credit_hours_high, enrollment, max_enrollment, seats_available, wait_capacity, wait_count, is_open, updated_at FROM sections WHERE term = ? AND crn = ?
+SELECT id, term, crn, subject, subject_description, course_number, sequence_number, title, campus, schedule_type, instructional_method, instructional_method_desc, credit_hours_low, credit_hours_high, enrollment, max_enrollment, seats_available, wait_capacity, wait_count, is_open, updated_at, removed_at, is_linked FROM sections WHERE term = ? AND crn = ?
 `
 
 type GetSectionByTermAndCRNParams struct {
@@ -694,6 +1406,8 @@ func (q *Queries) GetSectionByTermAndCRN(ctx context.Context, arg GetSectionByTe
 		&i.WaitCount,
 		&i.IsOpen,
 		&i.UpdatedAt,
+		&i.RemovedAt,
+		&i.IsLinked,
 	)
 	return &i, err
 }
@@ -709,12 +1423,257 @@ func (q *Queries) GetSectionCount(ctx context.Context, term string) (int64, erro
 	return count, err
 }
 
+const getSectionHistory = `-- name: GetSectionHistory :many
+SELECT id, term, crn, kind, old_value, new_value, created_at FROM section_history WHERE term = ? AND crn = ? ORDER BY id
+`
+
+type GetSectionHistoryParams struct {
+	Term string `json:"term"`
+	Crn  string `json:"crn"`
+}
+
+func (q *Queries) GetSectionHistory(ctx context.Context, arg GetSectionHistoryParams) ([]*SectionHistory, error) {
+	rows, err := q.db.QueryContext(ctx, getSectionHistory, arg.Term, arg.Crn)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*SectionHistory{}
+	for rows.Next() {
+		var i SectionHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.Term,
+			&i.Crn,
+			&i.Kind,
+			&i.OldValue,
+			&i.NewValue,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSectionLinks = `-- name: GetSectionLinks :many
+SELECT link_group, linked_crn FROM section_links
+WHERE term = ? AND crn = ?
+ORDER BY link_group, linked_crn
+`
+
+type GetSectionLinksParams struct {
+	Term string `json:"term"`
+	Crn  string `json:"crn"`
+}
+
+type GetSectionLinksRow struct {
+	LinkGroup int64  `json:"link_group"`
+	LinkedCrn string `json:"linked_crn"`
+}
+
+func (q *Queries) GetSectionLinks(ctx context.Context, arg GetSectionLinksParams) ([]*GetSectionLinksRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSectionLinks, arg.Term, arg.Crn)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*GetSectionLinksRow{}
+	for rows.Next() {
+		var i GetSectionLinksRow
+		if err := rows.Scan(&i.LinkGroup, &i.LinkedCrn); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSectionLinksByCourse = `-- name: GetSectionLinksByCourse :many
+SELECT l.crn, l.link_group, l.linked_crn
+FROM section_links l
+JOIN sections s ON s.term = l.term AND s.crn = l.crn
+WHERE s.term = ? AND s.subject = ? AND s.course_number = ?
+ORDER BY l.crn, l.link_group, l.linked_crn
+`
+
+type GetSectionLinksByCourseParams struct {
+	Term         string `json:"term"`
+	Subject      string `json:"subject"`
+	CourseNumber string `json:"course_number"`
+}
+
+type GetSectionLinksByCourseRow struct {
+	Crn       string `json:"crn"`
+	LinkGroup int64  `json:"link_group"`
+	LinkedCrn string `json:"linked_crn"`
+}
+
+func (q *Queries) GetSectionLinksByCourse(ctx context.Context, arg GetSectionLinksByCourseParams) ([]*GetSectionLinksByCourseRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSectionLinksByCourse, arg.Term, arg.Subject, arg.CourseNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*GetSectionLinksByCourseRow{}
+	for rows.Next() {
+		var i GetSectionLinksByCourseRow
+		if err := rows.Scan(&i.Crn, &i.LinkGroup, &i.LinkedCrn); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSectionSeatsUpdatedSince = `-- name: GetSectionSeatsUpdatedSince :many
+SELECT crn, enrollment, max_enrollment, seats_available, wait_count, is_open, updated_at, removed_at
+FROM sections
+WHERE term = ?1 AND datetime(updated_at) >= datetime(?2)
+`
+
+type GetSectionSeatsUpdatedSinceParams struct {
+	Term  string      `json:"term"`
+	Since interface{} `json:"since"`
+}
+
+type GetSectionSeatsUpdatedSinceRow struct {
+	Crn            string        `json:"crn"`
+	Enrollment     sql.NullInt64 `json:"enrollment"`
+	MaxEnrollment  sql.NullInt64 `json:"max_enrollment"`
+	SeatsAvailable sql.NullInt64 `json:"seats_available"`
+	WaitCount      sql.NullInt64 `json:"wait_count"`
+	IsOpen         sql.NullInt64 `json:"is_open"`
+	UpdatedAt      sql.NullTime  `json:"updated_at"`
+	RemovedAt      sql.NullTime  `json:"removed_at"`
+}
+
+func (q *Queries) GetSectionSeatsUpdatedSince(ctx context.Context, arg GetSectionSeatsUpdatedSinceParams) ([]*GetSectionSeatsUpdatedSinceRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSectionSeatsUpdatedSince, arg.Term, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*GetSectionSeatsUpdatedSinceRow{}
+	for rows.Next() {
+		var i GetSectionSeatsUpdatedSinceRow
+		if err := rows.Scan(
+			&i.Crn,
+			&i.Enrollment,
+			&i.MaxEnrollment,
+			&i.SeatsAvailable,
+			&i.WaitCount,
+			&i.IsOpen,
+			&i.UpdatedAt,
+			&i.RemovedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSectionStatesByCRNs = `-- name: GetSectionStatesByCRNs :many
+SELECT
+    s.id, s.crn, s.title, s.max_enrollment, s.seats_available, s.is_open, s.removed_at,
+    i.name AS instructor_name
+FROM sections s
+LEFT JOIN instructors i ON s.id = i.section_id AND i.is_primary = 1
+WHERE s.term = ? AND s.crn IN (/*SLICE:crns*/?)
+ORDER BY s.id, i.id
+`
+
+type GetSectionStatesByCRNsParams struct {
+	Term string   `json:"term"`
+	Crns []string `json:"crns"`
+}
+
+type GetSectionStatesByCRNsRow struct {
+	ID             int64          `json:"id"`
+	Crn            string         `json:"crn"`
+	Title          string         `json:"title"`
+	MaxEnrollment  sql.NullInt64  `json:"max_enrollment"`
+	SeatsAvailable sql.NullInt64  `json:"seats_available"`
+	IsOpen         sql.NullInt64  `json:"is_open"`
+	RemovedAt      sql.NullTime   `json:"removed_at"`
+	InstructorName sql.NullString `json:"instructor_name"`
+}
+
+func (q *Queries) GetSectionStatesByCRNs(ctx context.Context, arg GetSectionStatesByCRNsParams) ([]*GetSectionStatesByCRNsRow, error) {
+	query := getSectionStatesByCRNs
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.Term)
+	if len(arg.Crns) > 0 {
+		for _, v := range arg.Crns {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:crns*/?", strings.Repeat(",?", len(arg.Crns))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:crns*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*GetSectionStatesByCRNsRow{}
+	for rows.Next() {
+		var i GetSectionStatesByCRNsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Crn,
+			&i.Title,
+			&i.MaxEnrollment,
+			&i.SeatsAvailable,
+			&i.IsOpen,
+			&i.RemovedAt,
+			&i.InstructorName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSectionWithInstructorByTermAndCRN = `-- name: GetSectionWithInstructorByTermAndCRN :one
 SELECT
     s.id, s.term, s.crn, s.subject, s.subject_description,
     s.course_number, s.title, s.credit_hours_low,
     s.enrollment, s.max_enrollment, s.seats_available, s.wait_count, s.is_open,
-    s.instructional_method,
+    s.instructional_method, s.removed_at,
     i.name AS instructor_name, i.email AS instructor_email
 FROM sections s
 LEFT JOIN instructors i ON s.id = i.section_id AND i.is_primary = 1
@@ -741,6 +1700,7 @@ type GetSectionWithInstructorByTermAndCRNRow struct {
 	WaitCount           sql.NullInt64  `json:"wait_count"`
 	IsOpen              sql.NullInt64  `json:"is_open"`
 	InstructionalMethod sql.NullString `json:"instructional_method"`
+	RemovedAt           sql.NullTime   `json:"removed_at"`
 	InstructorName      sql.NullString `json:"instructor_name"`
 	InstructorEmail     sql.NullString `json:"instructor_email"`
 }
@@ -763,6 +1723,7 @@ func (q *Queries) GetSectionWithInstructorByTermAndCRN(ctx context.Context, arg
 		&i.WaitCount,
 		&i.IsOpen,
 		&i.InstructionalMethod,
+		&i.RemovedAt,
 		&i.InstructorName,
 		&i.InstructorEmail,
 	)
@@ -770,7 +1731,7 @@ func (q *Queries) GetSectionWithInstructorByTermAndCRN(ctx context.Context, arg
 }
 
 const getSectionsBySubject = `-- name: GetSectionsBySubject :many
-SELECT id, term, crn, subject, subject_description, course_number, sequence_number, title, campus, schedule_type, instructional_method, instructional_method_desc, credit_hours_low, credit_hours_high, enrollment, max_enrollment, seats_available, wait_capacity, wait_count, is_open, updated_at FROM sections WHERE term = ? AND subject = ? ORDER BY course_number
+SELECT id, term, crn, subject, subject_description, course_number, sequence_number, title, campus, schedule_type, instructional_method, instructional_method_desc, credit_hours_low, credit_hours_high, enrollment, max_enrollment, seats_available, wait_capacity, wait_count, is_open, updated_at, removed_at, is_linked FROM sections WHERE term = ? AND subject = ? ORDER BY course_number
 `
 
 type GetSectionsBySubjectParams struct {
@@ -809,6 +1770,8 @@ func (q *Queries) GetSectionsBySubject(ctx context.Context, arg GetSectionsBySub
 			&i.WaitCount,
 			&i.IsOpen,
 			&i.UpdatedAt,
+			&i.RemovedAt,
+			&i.IsLinked,
 		); err != nil {
 			return nil, err
 		}
@@ -824,7 +1787,7 @@ func (q *Queries) GetSectionsBySubject(ctx context.Context, arg GetSectionsBySub
 }
 
 const getSectionsByTerm = `-- name: GetSectionsByTerm :many
-SELECT id, term, crn, subject, subject_description, course_number, sequence_number, title, campus, schedule_type, instructional_method, instructional_method_desc, credit_hours_low, credit_hours_high, enrollment, max_enrollment, seats_available, wait_capacity, wait_count, is_open, updated_at FROM sections WHERE term = ? ORDER BY subject, course_number
+SELECT id, term, crn, subject, subject_description, course_number, sequence_number, title, campus, schedule_type, instructional_method, instructional_method_desc, credit_hours_low, credit_hours_high, enrollment, max_enrollment, seats_available, wait_capacity, wait_count, is_open, updated_at, removed_at, is_linked FROM sections WHERE term = ? ORDER BY subject, course_number
 `
 
 func (q *Queries) GetSectionsByTerm(ctx context.Context, term string) ([]*Section, error) {
@@ -858,6 +1821,8 @@ func (q *Queries) GetSectionsByTerm(ctx context.Context, term string) ([]*Sectio
 			&i.WaitCount,
 			&i.IsOpen,
 			&i.UpdatedAt,
+			&i.RemovedAt,
+			&i.IsLinked,
 		); err != nil {
 			return nil, err
 		}
@@ -881,7 +1846,7 @@ SELECT
     i.name AS instructor_name, i.email AS instructor_email
 FROM sections s
 LEFT JOIN instructors i ON s.id = i.section_id AND i.is_primary = 1
-WHERE s.term = ? AND s.subject = ? AND s.course_number = ?
+WHERE s.term = ? AND s.subject = ? AND s.course_number = ? AND s.removed_at IS NULL
 ORDER BY s.crn
 `
 
@@ -959,7 +1924,7 @@ SELECT
     i.name AS instructor_name, i.email AS instructor_email
 FROM sections s
 LEFT JOIN instructors i ON s.id = i.section_id AND i.is_primary = 1
-WHERE s.term = ?
+WHERE s.term = ? AND s.removed_at IS NULL
 ORDER BY s.id
 `
 
@@ -982,33 +1947,97 @@ type GetSectionsWithInstructorByTermRow struct {
 	InstructorEmail     sql.NullString `json:"instructor_email"`
 }
 
-func (q *Queries) GetSectionsWithInstructorByTerm(ctx context.Context, term string) ([]*GetSectionsWithInstructorByTermRow, error) {
-	rows, err := q.db.QueryContext(ctx, getSectionsWithInstructorByTerm, term)
+func (q *Queries) GetSectionsWithInstructorByTerm(ctx context.Context, term string) ([]*GetSectionsWithInstructorByTermRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSectionsWithInstructorByTerm, term)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*GetSectionsWithInstructorByTermRow{}
+	for rows.Next() {
+		var i GetSectionsWithInstructorByTermRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Term,
+			&i.Crn,
+			&i.Subject,
+			&i.SubjectDescription,
+			&i.CourseNumber,
+			&i.Title,
+			&i.CreditHoursLow,
+			&i.Enrollment,
+			&i.MaxEnrollment,
+			&i.SeatsAvailable,
+			&i.WaitCount,
+			&i.IsOpen,
+			&i.InstructionalMethod,
+			&i.InstructorName,
+			&i.InstructorEmail,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStalePrerequisiteTerms = `-- name: GetStalePrerequisiteTerms :many
+SELECT DISTINCT d.term FROM course_details d
+LEFT JOIN prerequisite_builds b ON b.term = d.term
+WHERE b.term IS NULL OR b.parser_version != ?
+ORDER BY d.term DESC
+`
+
+func (q *Queries) GetStalePrerequisiteTerms(ctx context.Context, parserVersion int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getStalePrerequisiteTerms, parserVersion)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var term string
+		if err := rows.Scan(&term); err != nil {
+			return nil, err
+		}
+		items = append(items, term)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSubjectDescriptions = `-- name: GetSubjectDescriptions :many
+SELECT DISTINCT subject, subject_description
+FROM sections
+ORDER BY subject
+`
+
+type GetSubjectDescriptionsRow struct {
+	Subject            string         `json:"subject"`
+	SubjectDescription sql.NullString `json:"subject_description"`
+}
+
+func (q *Queries) GetSubjectDescriptions(ctx context.Context) ([]*GetSubjectDescriptionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSubjectDescriptions)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []*GetSectionsWithInstructorByTermRow{}
+	items := []*GetSubjectDescriptionsRow{}
 	for rows.Next() {
-		var i GetSectionsWithInstructorByTermRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.Term,
-			&i.Crn,
-			&i.Subject,
-			&i.SubjectDescription,
-			&i.CourseNumber,
-			&i.Title,
-			&i.CreditHoursLow,
-			&i.Enrollment,
-			&i.MaxEnrollment,
-			&i.SeatsAvailable,
-			&i.WaitCount,
-			&i.IsOpen,
-			&i.InstructionalMethod,
-			&i.InstructorName,
-			&i.InstructorEmail,
-		); err != nil {
+		var i GetSubjectDescriptionsRow
+		if err := rows.Scan(&i.Subject, &i.SubjectDescription); err != nil {
 			return nil, err
 		}
 		items = append(items, &i)
@@ -1106,6 +2135,111 @@ func (q *Queries) GetTermByCode(ctx context.Context, code string) (*Term, error)
 	return &i, err
 }
 
+const getTermChanges = `-- name: GetTermChanges :many
+SELECT h.id, h.crn, h.kind, h.old_value, h.new_value, h.created_at,
+       s.subject, s.course_number, s.title
+FROM section_history h
+LEFT JOIN sections s ON s.term = h.term AND s.crn = h.crn
+WHERE h.term = ?1 AND h.id > ?2
+  AND (?3 = '' OR h.kind = ?3)
+ORDER BY h.id
+LIMIT ?4
+`
+
+type GetTermChangesParams struct {
+	Term  string `json:"term"`
+	After int64  `json:"after"`
+	Kind  string `json:"kind"`
+	Limit int64  `json:"limit"`
+}
+
+type GetTermChangesRow struct {
+	ID           int64          `json:"id"`
+	Crn          string         `json:"crn"`
+	Kind         string         `json:"kind"`
+	OldValue     sql.NullString `json:"old_value"`
+	NewValue     sql.NullString `json:"new_value"`
+	CreatedAt    sql.NullTime   `json:"created_at"`
+	Subject      sql.NullString `json:"subject"`
+	CourseNumber sql.NullString `json:"course_number"`
+	Title        sql.NullString `json:"title"`
+}
+
+func (q *Queries) GetTermChanges(ctx context.Context, arg GetTermChangesParams) ([]*GetTermChangesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTermChanges,
+		arg.Term,
+		arg.After,
+		arg.Kind,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*GetTermChangesRow{}
+	for rows.Next() {
+		var i GetTermChangesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Crn,
+			&i.Kind,
+			&i.OldValue,
+			&i.NewValue,
+			&i.CreatedAt,
+			&i.Subject,
+			&i.CourseNumber,
+			&i.Title,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTermCourses = `-- name: GetTermCourses :many
+SELECT subject, course_number, CAST(MAX(title) AS TEXT) AS title
+FROM sections
+WHERE term = ? AND removed_at IS NULL
+GROUP BY subject, course_number
+ORDER BY subject, course_number
+`
+
+type GetTermCoursesRow struct {
+	Subject      string `json:"subject"`
+	CourseNumber string `json:"course_number"`
+	Title        string `json:"title"`
+}
+
+func (q *Queries) GetTermCourses(ctx context.Context, term string) ([]*GetTermCoursesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTermCourses, term)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*GetTermCoursesRow{}
+	for rows.Next() {
+		var i GetTermCoursesRow
+		if err := rows.Scan(&i.Subject, &i.CourseNumber, &i.Title); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTerms = `-- name: GetTerms :many
 SELECT code, description, last_scraped_at FROM terms ORDER BY code DESC
 `
@@ -1161,6 +2295,27 @@ func (q *Queries) GetTermsNeverScraped(ctx context.Context) ([]*Term, error) {
 	return items, nil
 }
 
+const insertCoursePrerequisite = `-- name: InsertCoursePrerequisite :exec
+INSERT INTO course_prerequisites (term, subject, course_number, expression) VALUES (?, ?, ?, ?)
+`
+
+type InsertCoursePrerequisiteParams struct {
+	Term         string `json:"term"`
+	Subject      string `json:"subject"`
+	CourseNumber string `json:"course_number"`
+	Expression   string `json:"expression"`
+}
+
+func (q *Queries) InsertCoursePrerequisite(ctx context.Context, arg InsertCoursePrerequisiteParams) error {
+	_, err := q.db.ExecContext(ctx, insertCoursePrerequisite,
+		arg.Term,
+		arg.Subject,
+		arg.CourseNumber,
+		arg.Expression,
+	)
+	return err
+}
+
 const insertFeedback = `-- name: InsertFeedback :exec
 INSERT INTO feedback (session_id, message) VALUES (?, ?)
 `
@@ -1390,6 +2545,69 @@ func (q *Queries) InsertMeetingTime(ctx context.Context, arg InsertMeetingTimePa
 	return err
 }
 
+const insertPrerequisiteEdge = `-- name: InsertPrerequisiteEdge :exec
+INSERT OR IGNORE INTO prerequisite_edges (
+    term, subject, course_number, prereq_subject, prereq_course_number
+) VALUES (?, ?, ?, ?, ?)
+`
+
+type InsertPrerequisiteEdgeParams struct {
+	Term               string `json:"term"`
+	Subject            string `json:"subject"`
+	CourseNumber       string `json:"course_number"`
+	PrereqSubject      string `json:"prereq_subject"`
+	PrereqCourseNumber string `json:"prereq_course_number"`
+}
+
+func (q *Queries) InsertPrerequisiteEdge(ctx context.Context, arg InsertPrerequisiteEdgeParams) error {
+	_, err := q.db.ExecContext(ctx, insertPrerequisiteEdge,
+		arg.Term,
+		arg.Subject,
+		arg.CourseNumber,
+		arg.PrereqSubject,
+		arg.PrereqCourseNumber,
+	)
+	return err
+}
+
+const insertSavedSearchAlert = `-- name: InsertSavedSearchAlert :one
+INSERT INTO saved_search_alerts (saved_search_id, session_id, kind, term, crn, course_key, title, seats_available)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, created_at
+`
+
+type InsertSavedSearchAlertParams struct {
+	SavedSearchID  int64         `json:"saved_search_id"`
+	SessionID      string        `json:"session_id"`
+	Kind           string        `json:"kind"`
+	Term           string        `json:"term"`
+	Crn            string        `json:"crn"`
+	CourseKey      string        `json:"course_key"`
+	Title          string        `json:"title"`
+	SeatsAvailable sql.NullInt64 `json:"seats_available"`
+}
+
+type InsertSavedSearchAlertRow struct {
+	ID        int64        `json:"id"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+func (q *Queries) InsertSavedSearchAlert(ctx context.Context, arg InsertSavedSearchAlertParams) (*InsertSavedSearchAlertRow, error) {
+	row := q.db.QueryRowContext(ctx, insertSavedSearchAlert,
+		arg.SavedSearchID,
+		arg.SessionID,
+		arg.Kind,
+		arg.Term,
+		arg.Crn,
+		arg.CourseKey,
+		arg.Title,
+		arg.SeatsAvailable,
+	)
+	var i InsertSavedSearchAlertRow
+	err := row.Scan(&i.ID, &i.CreatedAt)
+	return &i, err
+}
+
 const insertSectionAttribute = `-- name: InsertSectionAttribute :exec
 INSERT INTO section_attributes (section_id, code, description)
 VALUES (?, ?, ?)
@@ -1406,6 +2624,50 @@ func (q *Queries) InsertSectionAttribute(ctx context.Context, arg InsertSectionA
 	return err
 }
 
+const insertSectionHistory = `-- name: InsertSectionHistory :exec
+INSERT INTO section_history (term, crn, kind, old_value, new_value) VALUES (?, ?, ?, ?, ?)
+`
+
+type InsertSectionHistoryParams struct {
+	Term     string         `json:"term"`
+	Crn      string         `json:"crn"`
+	Kind     string         `json:"kind"`
+	OldValue sql.NullString `json:"old_value"`
+	NewValue sql.NullString `json:"new_value"`
+}
+
+func (q *Queries) InsertSectionHistory(ctx context.Context, arg InsertSectionHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, insertSectionHistory,
+		arg.Term,
+		arg.Crn,
+		arg.Kind,
+		arg.OldValue,
+		arg.NewValue,
+	)
+	return err
+}
+
+const insertSectionLink = `-- name: InsertSectionLink :exec
+INSERT OR IGNORE INTO section_links (term, crn, link_group, linked_crn) VALUES (?, ?, ?, ?)
+`
+
+type InsertSectionLinkParams struct {
+	Term      string `json:"term"`
+	Crn       string `json:"crn"`
+	LinkGroup int64  `json:"link_group"`
+	LinkedCrn string `json:"linked_crn"`
+}
+
+func (q *Queries) InsertSectionLink(ctx context.Context, arg InsertSectionLinkParams) error {
+	_, err := q.db.ExecContext(ctx, insertSectionLink,
+		arg.Term,
+		arg.Crn,
+		arg.LinkGroup,
+		arg.LinkedCrn,
+	)
+	return err
+}
+
 const logGeneration = `-- name: LogGeneration :one
 INSERT INTO generation_logs (
     session_id, term, courses_count, schedules_generated,
@@ -1505,120 +2767,91 @@ func (q *Queries) LogSearch(ctx context.Context, arg LogSearchParams) error {
 	return err
 }
 
-const searchSections = `-- name: SearchSections :many
+const markSectionsRemoved = `-- name: MarkSectionsRemoved :many
+UPDATE sections SET removed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE term = ? AND removed_at IS NULL AND crn NOT IN (/*SLICE:crns*/?)
+RETURNING crn
+`
+
+type MarkSectionsRemovedParams struct {
+	Term string   `json:"term"`
+	Crns []string `json:"crns"`
+}
+
+func (q *Queries) MarkSectionsRemoved(ctx context.Context, arg MarkSectionsRemovedParams) ([]string, error) {
+	query := markSectionsRemoved
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.Term)
+	if len(arg.Crns) > 0 {
+		for _, v := range arg.Crns {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:crns*/?", strings.Repeat(",?", len(arg.Crns))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:crns*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var crn string
+		if err := rows.Scan(&crn); err != nil {
+			return nil, err
+		}
+		items = append(items, crn)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchInstructors = `-- name: SearchInstructors :many
 SELECT
-    s.id, s.term, s.crn, s.subject, s.subject_description,
-    s.course_number, s.title, s.credit_hours_low, s.credit_hours_high,
-    s.enrollment, s.max_enrollment, s.seats_available, s.wait_count, s.is_open,
-    s.instructional_method, s.schedule_type, s.campus,
-    i.name AS instructor_name, i.email AS instructor_email
-FROM sections s
-LEFT JOIN instructors i ON s.id = i.section_id AND i.is_primary = 1
-WHERE
-    -- Term filter (NULL = all terms)
-    (?1 IS NULL OR s.term = ?1)
-    -- Subject filter (exact match)
-    AND (?2 IS NULL OR s.subject = ?2)
-    -- Course number filter (supports LIKE for wildcards)
-    AND (?3 IS NULL OR s.course_number LIKE ?3)
-    -- Title tokens (up to 3)
-    AND (?4 IS NULL OR LOWER(s.title) LIKE '%' || LOWER(?4) || '%')
-    AND (?5 IS NULL OR LOWER(s.title) LIKE '%' || LOWER(?5) || '%')
-    AND (?6 IS NULL OR LOWER(s.title) LIKE '%' || LOWER(?6) || '%')
-    -- Instructor tokens (up to 3)
-    AND (?7 IS NULL OR LOWER(i.name) LIKE '%' || LOWER(?7) || '%')
-    AND (?8 IS NULL OR LOWER(i.name) LIKE '%' || LOWER(?8) || '%')
-    AND (?9 IS NULL OR LOWER(i.name) LIKE '%' || LOWER(?9) || '%')
-    -- Open seats filter
-    AND (?10 IS NULL OR ?10 = 0 OR s.seats_available > 0)
-    -- Credit range
-    AND (?11 IS NULL OR s.credit_hours_low >= ?11)
-    AND (?12 IS NULL OR s.credit_hours_low <= ?12)
-ORDER BY s.term DESC, s.subject, s.course_number, s.crn
-LIMIT ?13
-`
-
-type SearchSectionsParams struct {
-	Term         interface{} `json:"term"`
-	Subject      interface{} `json:"subject"`
-	CourseNumber interface{} `json:"course_number"`
-	TitleT1      interface{} `json:"title_t1"`
-	TitleT2      interface{} `json:"title_t2"`
-	TitleT3      interface{} `json:"title_t3"`
-	InstrT1      interface{} `json:"instr_t1"`
-	InstrT2      interface{} `json:"instr_t2"`
-	InstrT3      interface{} `json:"instr_t3"`
-	OpenSeats    interface{} `json:"open_seats"`
-	MinCredits   interface{} `json:"min_credits"`
-	MaxCredits   interface{} `json:"max_credits"`
-	ResultLimit  int64       `json:"result_limit"`
-}
-
-type SearchSectionsRow struct {
-	ID                  int64          `json:"id"`
-	Term                string         `json:"term"`
-	Crn                 string         `json:"crn"`
-	Subject             string         `json:"subject"`
-	SubjectDescription  sql.NullString `json:"subject_description"`
-	CourseNumber        string         `json:"course_number"`
-	Title               string         `json:"title"`
-	CreditHoursLow      sql.NullInt64  `json:"credit_hours_low"`
-	CreditHoursHigh     sql.NullInt64  `json:"credit_hours_high"`
-	Enrollment          sql.NullInt64  `json:"enrollment"`
-	MaxEnrollment       sql.NullInt64  `json:"max_enrollment"`
-	SeatsAvailable      sql.NullInt64  `json:"seats_available"`
-	WaitCount           sql.NullInt64  `json:"wait_count"`
-	IsOpen              sql.NullInt64  `json:"is_open"`
-	InstructionalMethod sql.NullString `json:"instructional_method"`
-	ScheduleType        sql.NullString `json:"schedule_type"`
-	Campus              sql.NullString `json:"campus"`
-	InstructorName      sql.NullString `json:"instructor_name"`
-	InstructorEmail     sql.NullString `json:"instructor_email"`
+    i.name,
+    COUNT(DISTINCT s.subject || ':' || s.course_number) AS course_count,
+    COUNT(*) AS section_count,
+    CAST(MAX(s.term) AS TEXT) AS latest_term
+FROM instructors i
+JOIN sections s ON s.id = i.section_id
+WHERE i.name LIKE ? ESCAPE '\'
+GROUP BY i.name
+ORDER BY section_count DESC, i.name
+LIMIT ?
+`
+
+type SearchInstructorsParams struct {
+	Name  string `json:"name"`
+	Limit int64  `json:"limit"`
 }
 
-func (q *Queries) SearchSections(ctx context.Context, arg SearchSectionsParams) ([]*SearchSectionsRow, error) {
-	rows, err := q.db.QueryContext(ctx, searchSections,
-		arg.Term,
-		arg.Subject,
-		arg.CourseNumber,
-		arg.TitleT1,
-		arg.TitleT2,
-		arg.TitleT3,
-		arg.InstrT1,
-		arg.InstrT2,
-		arg.InstrT3,
-		arg.OpenSeats,
-		arg.MinCredits,
-		arg.MaxCredits,
-		arg.ResultLimit,
-	)
+type SearchInstructorsRow struct {
+	Name         string `json:"name"`
+	CourseCount  int64  `json:"course_count"`
+	SectionCount int64  `json:"section_count"`
+	LatestTerm   string `json:"latest_term"`
+}
+
+func (q *Queries) SearchInstructors(ctx context.Context, arg SearchInstructorsParams) ([]*SearchInstructorsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchInstructors, arg.Name, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []*SearchSectionsRow{}
+	items := []*SearchInstructorsRow{}
 	for rows.Next() {
-		var i SearchSectionsRow
+		var i SearchInstructorsRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.Term,
-			&i.Crn,
-			&i.Subject,
-			&i.SubjectDescription,
-			&i.CourseNumber,
-			&i.Title,
-			&i.CreditHoursLow,
-			&i.CreditHoursHigh,
-			&i.Enrollment,
-			&i.MaxEnrollment,
-			&i.SeatsAvailable,
-			&i.WaitCount,
-			&i.IsOpen,
-			&i.InstructionalMethod,
-			&i.ScheduleType,
-			&i.Campus,
-			&i.InstructorName,
-			&i.InstructorEmail,
+			&i.Name,
+			&i.CourseCount,
+			&i.SectionCount,
+			&i.LatestTerm,
 		); err != nil {
 			return nil, err
 		}
@@ -1633,6 +2866,21 @@ func (q *Queries) SearchSections(ctx context.Context, arg SearchSectionsParams)
 	return items, nil
 }
 
+const updateSavedSearchSnapshot = `-- name: UpdateSavedSearchSnapshot :exec
+UPDATE saved_searches SET snapshot = ?, truncated = ?, last_run_at = CURRENT_TIMESTAMP WHERE id = ?
+`
+
+type UpdateSavedSearchSnapshotParams struct {
+	Snapshot  string `json:"snapshot"`
+	Truncated int64  `json:"truncated"`
+	ID        int64  `json:"id"`
+}
+
+func (q *Queries) UpdateSavedSearchSnapshot(ctx context.Context, arg UpdateSavedSearchSnapshotParams) error {
+	_, err := q.db.ExecContext(ctx, updateSavedSearchSnapshot, arg.Snapshot, arg.Truncated, arg.ID)
+	return err
+}
+
 const updateTermScrapedAt = `-- name: UpdateTermScrapedAt :exec
 UPDATE terms SET last_scraped_at = CURRENT_TIMESTAMP WHERE code = ?
 `
@@ -1642,6 +2890,41 @@ func (q *Queries) UpdateTermScrapedAt(ctx context.Context, code string) error {
 	return err
 }
 
+const upsertCourseDetails = `-- name: UpsertCourseDetails :exec
+INSERT INTO course_details (
+    term, subject, course_number, description, prerequisites, restrictions, fees, fetched_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(term, subject, course_number) DO UPDATE SET
+    description = excluded.description,
+    prerequisites = excluded.prerequisites,
+    restrictions = excluded.restrictions,
+    fees = excluded.fees,
+    fetched_at = CURRENT_TIMESTAMP
+`
+
+type UpsertCourseDetailsParams struct {
+	Term          string         `json:"term"`
+	Subject       string         `json:"subject"`
+	CourseNumber  string         `json:"course_number"`
+	Description   sql.NullString `json:"description"`
+	Prerequisites sql.NullString `json:"prerequisites"`
+	Restrictions  sql.NullString `json:"restrictions"`
+	Fees          sql.NullString `json:"fees"`
+}
+
+func (q *Queries) UpsertCourseDetails(ctx context.Context, arg UpsertCourseDetailsParams) error {
+	_, err := q.db.ExecContext(ctx, upsertCourseDetails,
+		arg.Term,
+		arg.Subject,
+		arg.CourseNumber,
+		arg.Description,
+		arg.Prerequisites,
+		arg.Restrictions,
+		arg.Fees,
+	)
+	return err
+}
+
 const upsertInstructorMapping = `-- name: UpsertInstructorMapping :exec
 INSERT INTO instructor_mappings (banner_name, grade_name, match_count)
 VALUES (?, ?, ?)
@@ -1661,13 +2944,31 @@ func (q *Queries) UpsertInstructorMapping(ctx context.Context, arg UpsertInstruc
 	return err
 }
 
+const upsertPrerequisiteBuild = `-- name: UpsertPrerequisiteBuild :exec
+INSERT INTO prerequisite_builds (term, parser_version, built_at)
+VALUES (?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(term) DO UPDATE SET
+    parser_version = excluded.parser_version,
+    built_at = CURRENT_TIMESTAMP
+`
+
+type UpsertPrerequisiteBuildParams struct {
+	Term          string `json:"term"`
+	ParserVersion int64  `json:"parser_version"`
+}
+
+func (q *Queries) UpsertPrerequisiteBuild(ctx context.Context, arg UpsertPrerequisiteBuildParams) error {
+	_, err := q.db.ExecContext(ctx, upsertPrerequisiteBuild, arg.Term, arg.ParserVersion)
+	return err
+}
+
 const upsertSection = `-- name: UpsertSection :one
 INSERT INTO sections (
     term, crn, subject, subject_description, course_number, sequence_number,
     title, campus, schedule_type, instructional_method, instructional_method_desc,
     credit_hours_low, credit_hours_high, enrollment, max_enrollment, seats_available,
-    wait_capacity, wait_count, is_open, updated_at
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+    wait_capacity, wait_count, is_open, is_linked, updated_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
 ON CONFLICT(term, crn) DO UPDATE SET
     subject = excluded.subject,
     subject_description = excluded.subject_description,
@@ -1686,7 +2987,9 @@ ON CONFLICT(term, crn) DO UPDATE SET
     wait_capacity = excluded.wait_capacity,
     wait_count = excluded.wait_count,
     is_open = excluded.is_open,
-    updated_at = CURRENT_TIMESTAMP
+    is_linked = excluded.is_linked,
+    updated_at = CURRENT_TIMESTAMP,
+    removed_at = NULL
 RETURNING id
 `
 
@@ -1710,6 +3013,7 @@ type UpsertSectionParams struct {
 	WaitCapacity            sql.NullInt64  `json:"wait_capacity"`
 	WaitCount               sql.NullInt64  `json:"wait_count"`
 	IsOpen                  sql.NullInt64  `json:"is_open"`
+	IsLinked                int64          `json:"is_linked"`
 }
 
 func (q *Queries) UpsertSection(ctx context.Context, arg UpsertSectionParams) (int64, error) {
@@ -1733,6 +3037,7 @@ func (q *Queries) UpsertSection(ctx context.Context, arg UpsertSectionParams) (i
 		arg.WaitCapacity,
 		arg.WaitCount,
 		arg.IsOpen,
+		arg.IsLinked,
 	)
 	var id int64
 	err := row.Scan(&id)
@@ -1779,7 +3084,7 @@ SELECT
     COUNT(*) AS section_count,
     COALESCE(MAX(title), '') AS title
 FROM sections
-WHERE term = ? AND subject = ? AND course_number = ?
+WHERE term = ? AND subject = ? AND course_number = ? AND removed_at IS NULL
 `
 
 type ValidateCourseForTermParams struct {
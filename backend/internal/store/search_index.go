@@ -0,0 +1,46 @@
+package store
+
+import "context"
+
+// sections_fts is an FTS5 virtual table, which sqlc can't analyze, so its
+// maintenance queries live here instead of queries.sql. Search reads it with
+// dynamic SQL in internal/search.
+
+const deleteSectionText = `DELETE FROM sections_fts WHERE rowid = ?`
+
+const insertSectionText = `
+INSERT INTO sections_fts (rowid, title, subject_description, instructors)
+SELECT
+    s.id,
+    s.title,
+    COALESCE(s.subject_description, ''),
+    COALESCE((SELECT group_concat(i.name, ' ') FROM instructors i WHERE i.section_id = s.id), '')
+FROM sections s
+WHERE s.id = ?`
+
+// IndexSectionText replaces a section's full-text entry from its current
+// title, subject description, and instructors. Call after instructors are saved.
+func (q *Queries) IndexSectionText(ctx context.Context, sectionID int64) error {
+	if _, err := q.db.ExecContext(ctx, deleteSectionText, sectionID); err != nil {
+		return err
+	}
+	_, err := q.db.ExecContext(ctx, insertSectionText, sectionID)
+	return err
+}
+
+const rebuildSectionText = `
+DELETE FROM sections_fts;
+INSERT INTO sections_fts (rowid, title, subject_description, instructors)
+SELECT
+    s.id,
+    s.title,
+    COALESCE(s.subject_description, ''),
+    COALESCE((SELECT group_concat(i.name, ' ') FROM instructors i WHERE i.section_id = s.id), '')
+FROM sections s`
+
+// RebuildSectionTextIndex reindexes every section. Used after bulk loads that
+// bypass the scraper, such as test fixtures.
+func (q *Queries) RebuildSectionTextIndex(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, rebuildSectionText)
+	return err
+}
@@ -0,0 +1,103 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ExecTx runs fn with Queries bound to a new transaction on db. The
+// transaction commits if fn returns nil and rolls back otherwise, so readers
+// see either all of fn's writes or none of them.
+//
+// Statements are prepared on first use and reused until the transaction ends,
+// which suits bulk writes that repeat a few queries many times. Queries made
+// inside fn must not contain more than one statement, and fn must not use the
+// Queries from other goroutines.
+func ExecTx(ctx context.Context, db *sql.DB, fn func(*Queries) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(New(&preparedTx{tx: tx, stmts: make(map[string]*sql.Stmt)})); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// preparedTx is a DBTX that caches a prepared statement per query string.
+// The transaction closes the statements when it ends.
+type preparedTx struct {
+	tx    *sql.Tx
+	stmts map[string]*sql.Stmt
+}
+
+func (p *preparedTx) stmt(ctx context.Context, query string) (*sql.Stmt, error) {
+	if stmt, ok := p.stmts[query]; ok {
+		return stmt, nil
+	}
+	stmt, err := p.tx.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	p.stmts[query] = stmt
+	return stmt, nil
+}
+
+func (p *preparedTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	stmt, err := p.stmt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.ExecContext(ctx, args...)
+}
+
+func (p *preparedTx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.stmt(ctx, query)
+}
+
+func (p *preparedTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	stmt, err := p.stmt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.QueryContext(ctx, args...)
+}
+
+func (p *preparedTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	stmt, err := p.stmt(ctx, query)
+	if err != nil {
+		// sql.Row can't be built with an error; the unprepared query fails
+		// the same way and reports it on Scan
+		return p.tx.QueryRowContext(ctx, query, args...)
+	}
+	return stmt.QueryRowContext(ctx, args...)
+}
+
+// Savepoint runs fn inside a savepoint of q's transaction. If fn fails, only
+// its writes are undone and the transaction can go on. q must come from
+// ExecTx.
+func Savepoint(ctx context.Context, q *Queries, fn func() error) error {
+	if _, err := q.db.ExecContext(ctx, "SAVEPOINT sp"); err != nil {
+		return fmt.Errorf("begin savepoint: %w", err)
+	}
+	if err := fn(); err != nil {
+		if _, rbErr := q.db.ExecContext(ctx, "ROLLBACK TO sp"); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("roll back savepoint: %w", rbErr))
+		}
+		if _, relErr := q.db.ExecContext(ctx, "RELEASE sp"); relErr != nil {
+			return errors.Join(err, fmt.Errorf("release savepoint: %w", relErr))
+		}
+		return err
+	}
+	if _, err := q.db.ExecContext(ctx, "RELEASE sp"); err != nil {
+		return fmt.Errorf("release savepoint: %w", err)
+	}
+	return nil
+}
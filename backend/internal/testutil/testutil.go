@@ -2,10 +2,12 @@
 package testutil
 
 import (
+	"context"
 	"database/sql"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -29,7 +31,7 @@ func getProjectRoot() string {
 	}
 }
 
-// SetupTestDB creates an in-memory SQLite database with the schema applied.
+// SetupTestDB creates an in-memory SQLite database with all up migrations applied.
 // Returns the database connection and a store.Queries instance.
 func SetupTestDB(t testing.TB) (*sql.DB, *store.Queries) {
 	t.Helper()
@@ -39,15 +41,23 @@ func SetupTestDB(t testing.TB) (*sql.DB, *store.Queries) {
 		t.Fatalf("failed to open test database: %v", err)
 	}
 
-	// Read and apply the migration
-	migrationPath := filepath.Join(getProjectRoot(), "migrations", "000001_initial_schema.up.sql")
-	schema, err := os.ReadFile(migrationPath)
-	if err != nil {
-		t.Fatalf("failed to read migration file: %v", err)
+	// Glob returns migrations in lexical order, which matches their sequence numbers
+	migrations, err := filepath.Glob(filepath.Join(getProjectRoot(), "migrations", "*.up.sql"))
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("failed to find migration files: %v", err)
 	}
 
-	if _, err := db.Exec(string(schema)); err != nil {
-		t.Fatalf("failed to apply schema: %v", err)
+	for _, path := range migrations {
+		schema, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read migration file: %v", err)
+		}
+		if _, err := db.Exec(string(schema)); err != nil {
+			if strings.Contains(err.Error(), "no such module: fts5") {
+				t.Fatalf("failed to apply %s: %v (run tests with -tags sqlite_fts5)", filepath.Base(path), err)
+			}
+			t.Fatalf("failed to apply %s: %v", filepath.Base(path), err)
+		}
 	}
 
 	return db, store.New(db)
@@ -89,4 +99,7 @@ func SeedTestData(t testing.TB, db *sql.DB) {
 	if _, err := db.Exec(data); err != nil {
 		t.Fatalf("failed to seed test data: %v", err)
 	}
+	if err := store.New(db).RebuildSectionTextIndex(context.Background()); err != nil {
+		t.Fatalf("failed to index test data: %v", err)
+	}
 }
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS sections_fts_delete;
+DROP TABLE IF EXISTS sections_fts;
@@ -0,0 +1,24 @@
+-- Full-text index over section titles, subject descriptions, and instructor names.
+-- FTS5, for its built-in bm25() ranking: go-sqlite3 only compiles it behind
+-- the sqlite_fts5 build tag, so the server and migrate are built with it.
+-- rowid mirrors sections.id.
+-- Rows are written by the scraper after a section's instructors are saved
+-- (store.IndexSectionText); deletes cascade through the trigger below.
+CREATE VIRTUAL TABLE sections_fts USING fts5(
+    title,
+    subject_description,
+    instructors,
+    tokenize='unicode61'
+);
+
+INSERT INTO sections_fts (rowid, title, subject_description, instructors)
+SELECT
+    s.id,
+    s.title,
+    COALESCE(s.subject_description, ''),
+    COALESCE((SELECT group_concat(i.name, ' ') FROM instructors i WHERE i.section_id = s.id), '')
+FROM sections s;
+
+CREATE TRIGGER sections_fts_delete AFTER DELETE ON sections BEGIN
+    DELETE FROM sections_fts WHERE rowid = old.id;
+END;
@@ -0,0 +1,3 @@
+-- Read-only view of the sections_fts vocabulary (one row per term and column).
+-- Search loads it to find typo-tolerant candidates for words that match nothing.
+CREATE VIRTUAL TABLE sections_fts_terms USING fts5vocab(sections_fts, col);
@@ -0,0 +1,32 @@
+-- Named searches stored per anonymous session (X-Session-ID). params is the
+-- /api/search query string; snapshot maps "term:crn" to the section's seats
+-- as of the last run, so reruns after a scrape can report what changed.
+-- truncated is set when the last run stopped at its page cap, so sections
+-- past the cut are missing from the snapshot rather than unmatched.
+CREATE TABLE saved_searches (
+    id INTEGER PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    params TEXT NOT NULL,
+    snapshot TEXT NOT NULL DEFAULT '{}',
+    truncated INTEGER NOT NULL DEFAULT 0,
+    last_run_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_saved_searches_session ON saved_searches(session_id);
+
+CREATE TABLE saved_search_alerts (
+    id INTEGER PRIMARY KEY,
+    saved_search_id INTEGER NOT NULL,
+    session_id TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    term TEXT NOT NULL,
+    crn TEXT NOT NULL,
+    course_key TEXT NOT NULL,
+    title TEXT NOT NULL,
+    seats_available INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (saved_search_id) REFERENCES saved_searches(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_saved_search_alerts_session ON saved_search_alerts(session_id, id);
+CREATE INDEX idx_saved_search_alerts_search ON saved_search_alerts(saved_search_id);
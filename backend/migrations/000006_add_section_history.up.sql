@@ -0,0 +1,15 @@
+-- One row per change the scraper saw to a section between scrapes. Sections
+-- are keyed by term and CRN rather than id so history outlives the row.
+-- old_value is NULL for 'added'; values are display strings (seat counts,
+-- instructor names, meeting summaries such as "MWF 1000-1050 CF 105").
+CREATE TABLE section_history (
+    id INTEGER PRIMARY KEY,
+    term TEXT NOT NULL,
+    crn TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_section_history_section ON section_history(term, crn, id);
+CREATE INDEX idx_section_history_term ON section_history(term, id);
@@ -0,0 +1 @@
+ALTER TABLE sections DROP COLUMN removed_at;
@@ -0,0 +1,3 @@
+-- Set when a complete scrape of the term no longer returns the section
+-- (cancelled, or otherwise dropped from Banner); cleared if it comes back.
+ALTER TABLE sections ADD COLUMN removed_at TIMESTAMP;
@@ -0,0 +1,20 @@
+-- One row per term scrape by the jobs service. status is running until the
+-- scrape finishes, then success (every page fetched and stored), partial
+-- (stored, but pages or sections failed, or the source reported more sections
+-- than came back), failed (nothing stored; error says why), or cancelled
+-- (stopped by a pause or shutdown before anything was stored).
+CREATE TABLE scrape_runs (
+    id INTEGER PRIMARY KEY,
+    term TEXT NOT NULL,
+    job TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'running',
+    stored INTEGER NOT NULL DEFAULT 0,
+    expected INTEGER NOT NULL DEFAULT 0,
+    page_errors INTEGER NOT NULL DEFAULT 0,
+    save_errors INTEGER NOT NULL DEFAULT 0,
+    removed INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    started_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+CREATE INDEX idx_scrape_runs_term_status ON scrape_runs(term, status, finished_at);
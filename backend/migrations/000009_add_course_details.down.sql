@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS section_links;
+DROP TABLE IF EXISTS course_details;
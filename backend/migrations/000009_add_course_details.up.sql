@@ -0,0 +1,25 @@
+-- Catalog text Banner shows on a section's detail tabs. Descriptions,
+-- prerequisites, restrictions, and fees are set per course, so they're
+-- fetched from one section of each course and stored once per term.
+-- Text is the HTML with tags stripped; NULL when Banner has none.
+CREATE TABLE course_details (
+    term TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    course_number TEXT NOT NULL,
+    description TEXT,
+    prerequisites TEXT,
+    restrictions TEXT,
+    fees TEXT,
+    fetched_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (term, subject, course_number)
+);
+
+-- Sections that must be registered together with crn, such as a lecture's
+-- labs. Each link_group is one alternative set of linked sections.
+CREATE TABLE section_links (
+    term TEXT NOT NULL,
+    crn TEXT NOT NULL,
+    link_group INTEGER NOT NULL,
+    linked_crn TEXT NOT NULL,
+    PRIMARY KEY (term, crn, link_group, linked_crn)
+);
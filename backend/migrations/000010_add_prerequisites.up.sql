@@ -0,0 +1,23 @@
+-- Prerequisites parsed from course_details.prerequisites, rebuilt for a term
+-- after each scrape. expression is the requirement as a JSON AND/OR tree of
+-- courses; courses with no prerequisite text have no row.
+CREATE TABLE course_prerequisites (
+    term TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    course_number TEXT NOT NULL,
+    expression TEXT NOT NULL,
+    PRIMARY KEY (term, subject, course_number)
+);
+
+-- One edge per course named in a course's prerequisites, for looking up
+-- the courses a course leads to.
+CREATE TABLE prerequisite_edges (
+    term TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    course_number TEXT NOT NULL,
+    prereq_subject TEXT NOT NULL,
+    prereq_course_number TEXT NOT NULL,
+    PRIMARY KEY (term, subject, course_number, prereq_subject, prereq_course_number)
+);
+
+CREATE INDEX idx_prerequisite_edges_prereq ON prerequisite_edges(term, prereq_subject, prereq_course_number);
@@ -0,0 +1 @@
+ALTER TABLE sections DROP COLUMN is_linked;
@@ -0,0 +1,3 @@
+-- Banner's isSectionLinked: the section must be registered together with
+-- others (see section_links). The detail job fetches links for these.
+ALTER TABLE sections ADD COLUMN is_linked INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,8 @@
+-- When each term's prerequisite graph was last rebuilt and by which parser
+-- version. Terms with details but no row, or an older version, are rebuilt
+-- on startup.
+CREATE TABLE prerequisite_builds (
+    term TEXT PRIMARY KEY,
+    parser_version INTEGER NOT NULL,
+    built_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);